@@ -0,0 +1,98 @@
+// Команда rendercheck сверяет рендеринг исходного React компонента (react-dom/server)
+// и сгенерированного templ компонента на фикстуре:
+//
+//	go run ./cmd/rendercheck -fixture fixture.json Counter.tsx
+//
+// Фикстура - JSON с пропсами и значениями состояний: {"props": {...}, "state": {...}}.
+// Сверка выполняет код компонента и собирает временный модуль Go, поэтому доступна
+// только из командной строки, а не через сервер
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/converter"
+	"react-to-templ-converter/internal/generator"
+	"react-to-templ-converter/internal/models"
+	"react-to-templ-converter/internal/parser"
+	"strings"
+)
+
+func main() {
+	fixturePath := flag.String("fixture", "", "JSON файл фикстуры с props и state")
+	parserPath := flag.String("parser", "./parser-js", "каталог Node.js парсера")
+	interactivity := flag.String("interactivity", "", "режим интерактивности (htmx, alpine, hybrid)")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "использование: rendercheck [-fixture fixture.json] Component.tsx")
+		os.Exit(2)
+	}
+
+	check, err := run(flag.Arg(0), *fixturePath, *parserPath, *interactivity)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	output, _ := json.MarshalIndent(check, "", "  ")
+	fmt.Println(string(output))
+	if !check.Equivalent {
+		os.Exit(1)
+	}
+}
+
+// run конвертирует компонент с фикстурой и возвращает результат сверки
+func run(sourcePath string, fixturePath string, parserPath string, interactivity string) (*models.RenderCheck, error) {
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения компонента: %w", err)
+	}
+
+	fixture := &models.RenderFixture{}
+	if fixturePath != "" {
+		data, err := os.ReadFile(fixturePath)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения фикстуры: %w", err)
+		}
+		if err := json.Unmarshal(data, fixture); err != nil {
+			return nil, fmt.Errorf("ошибка разбора фикстуры: %w", err)
+		}
+	}
+
+	reactParser := parser.NewNodeJSParser(parserPath)
+	reactParser.EnableRendering()
+	if err := reactParser.StartParser(); err != nil {
+		return nil, fmt.Errorf("ошибка запуска парсера: %w", err)
+	}
+	defer reactParser.StopParser()
+
+	options := config.NewDefaultOptions()
+	options.UseHtmx = true
+	options.PackageName = "templates"
+	options.ComponentName = strings.TrimSuffix(filepath.Base(sourcePath), filepath.Ext(sourcePath))
+	options.SourcePath = sourcePath
+	options.RenderFixture = fixture
+	if interactivity != "" {
+		options.Interactivity = interactivity
+	}
+
+	templGenerator := generator.NewTemplGenerator(options)
+	templGenerator.SetJSXConverter(converter.NewJSXToHTMXConverter(options))
+	goGenerator := generator.NewGoGenerator(options)
+	goGenerator.SetStateHandler(converter.NewStateHandler(options))
+
+	reactConverter := converter.NewConverter(reactParser,
+		converter.WithTemplGenerator(templGenerator),
+		converter.WithGoGenerator(goGenerator))
+
+	result, err := reactConverter.Convert(string(content), options)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка конвертации: %w", err)
+	}
+	return result.RenderCheck, nil
+}
@@ -61,6 +61,9 @@ func main() {
 	// API для конвертации
 	r.HandleFunc("/api/convert", handleConversion(reactParser)).Methods("POST")
 
+	// API для конвертации проекта (входной файл и импортированные компоненты)
+	r.HandleFunc("/api/convert-project", handleProjectConversion(reactParser)).Methods("POST")
+
 	// API для получения примеров
 	r.HandleFunc("/api/examples/{name}", handleGetExample).Methods("GET")
 
@@ -138,32 +141,13 @@ func handleConversion(reactParser parser.ReactParser) http.HandlerFunc {
 		options.ComponentName = componentName
 		options.PackageName = "templates"
 		options.Debug = false
-
-		// Создаем все необходимые компоненты системы конвертации
-		// JSX конвертер
-		jsxConverter := converter.NewJSXToHTMXConverter(options)
-
-		// Обработчик состояний
-		stateHandler := converter.NewStateHandler(options)
-
-		// Генератор templ шаблонов
-		templGenerator := generator.NewTemplGenerator(options)
-		templGenerator.SetJSXConverter(jsxConverter)
-
-		// Генератор Go контроллеров
-		goGenerator := generator.NewGoGenerator(options)
-		goGenerator.SetStateHandler(stateHandler)
-
-		// Создаем экземпляр конвертера
-		reactConverter := converter.NewConverter(reactParser,
-			converter.WithDebugMode(options.Debug),
-			converter.WithIndentation(options.Indentation.Style, options.Indentation.Size))
-
-		// Устанавливаем генераторы для конвертера
-		if converterImpl, ok := reactConverter.(*converter.ReactToTemplConverter); ok {
-			converterImpl.SetTemplGenerator(templGenerator)
-			converterImpl.SetGoGenerator(goGenerator)
+		if interactivity := r.FormValue("interactivity"); interactivity != "" {
+			options.Interactivity = interactivity
 		}
+		options.TailwindConfig = r.FormValue("tailwind") == "true"
+
+		// Создаем конвертер с генераторами
+		reactConverter := newReactConverter(reactParser, options)
 
 		// Конвертация
 		result, err := reactConverter.Convert(string(content), options)
@@ -174,9 +158,14 @@ func handleConversion(reactParser parser.ReactParser) http.HandlerFunc {
 
 		// Создаем ответ
 		response := map[string]interface{}{
-			"templFile":    result.TemplFile,
-			"goController": result.GoController,
-			"htmxJS":       result.HtmxJS,
+			"templFile":      result.TemplFile,
+			"goController":   result.GoController,
+			"htmxJS":         result.HtmxJS,
+			"warnings":       result.Warnings,
+			"dataflow":       result.Dataflow,
+			"classes":        result.Classes,
+			"tailwindConfig": result.TailwindConfig,
+			"stylesheet":     result.Stylesheet,
 		}
 
 		// Возвращаем результат
@@ -191,6 +180,127 @@ func handleConversion(reactParser parser.ReactParser) http.HandlerFunc {
 	}
 }
 
+// newReactConverter создает конвертер со всеми генераторами. Генераторы используют
+// тот же указатель на опции, что передается в Convert и ConvertProject
+func newReactConverter(reactParser parser.ReactParser, options *config.ConversionOptions) *converter.ReactToTemplConverter {
+	// JSX конвертер
+	jsxConverter := converter.NewJSXToHTMXConverter(options)
+
+	// Обработчик состояний
+	stateHandler := converter.NewStateHandler(options)
+
+	// Генератор templ шаблонов
+	templGenerator := generator.NewTemplGenerator(options)
+	templGenerator.SetJSXConverter(jsxConverter)
+
+	// Генератор Go контроллеров
+	goGenerator := generator.NewGoGenerator(options)
+	goGenerator.SetStateHandler(stateHandler)
+
+	// Создаем экземпляр конвертера
+	reactConverter := converter.NewConverter(reactParser,
+		converter.WithDebugMode(options.Debug),
+		converter.WithIndentation(options.Indentation.Style, options.Indentation.Size),
+		converter.WithTemplGenerator(templGenerator),
+		converter.WithGoGenerator(goGenerator))
+
+	return reactConverter.(*converter.ReactToTemplConverter)
+}
+
+// handleProjectConversion обрабатывает запрос на конвертацию проекта: файлы загружаются
+// с относительными путями в имени (components/Button.tsx), entry задает входной файл
+func handleProjectConversion(reactParser parser.ReactParser) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, 20<<20) // 20 МБ
+
+		if err := r.ParseMultipartForm(20 << 20); err != nil {
+			http.Error(w, "Ошибка разбора формы: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		entry := filepath.Clean(filepath.FromSlash(r.FormValue("entry")))
+		if entry == "." || filepath.IsAbs(entry) || strings.HasPrefix(entry, "..") {
+			http.Error(w, "Не указан входной файл проекта", http.StatusBadRequest)
+			return
+		}
+
+		// Раскладываем файлы проекта во временный каталог
+		dir, err := os.MkdirTemp("", "react-project-")
+		if err != nil {
+			http.Error(w, "Ошибка создания каталога: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer os.RemoveAll(dir)
+
+		for _, header := range r.MultipartForm.File["files"] {
+			name := filepath.Clean(filepath.FromSlash(header.Filename))
+			if filepath.IsAbs(name) || strings.HasPrefix(name, "..") {
+				http.Error(w, "Недопустимый путь файла: "+header.Filename, http.StatusBadRequest)
+				return
+			}
+
+			file, err := header.Open()
+			if err != nil {
+				http.Error(w, "Ошибка получения файла: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			content, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				http.Error(w, "Ошибка чтения файла: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				http.Error(w, "Ошибка создания каталога: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				http.Error(w, "Ошибка записи файла: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		options := config.NewDefaultOptions()
+		options.UseHtmx = true
+		options.PackageName = "templates"
+		options.ProjectRoot = dir
+		if modulePath := r.FormValue("modulePath"); modulePath != "" {
+			options.ModulePath = modulePath
+		}
+		if interactivity := r.FormValue("interactivity"); interactivity != "" {
+			options.Interactivity = interactivity
+		}
+		options.TailwindConfig = r.FormValue("tailwind") == "true"
+
+		result, err := newReactConverter(reactParser, options).ConvertProject(filepath.Join(dir, entry), options)
+		if err != nil {
+			http.Error(w, "Ошибка конвертации: "+strings.ReplaceAll(err.Error(), dir+string(filepath.Separator), ""), http.StatusInternalServerError)
+			return
+		}
+
+		// Пути в ответе - относительно корня проекта
+		result.Entry = filepath.ToSlash(entry)
+		for i, warning := range result.Warnings {
+			result.Warnings[i] = strings.ReplaceAll(warning, dir+string(filepath.Separator), "")
+		}
+		for _, file := range result.Files {
+			if rel, err := filepath.Rel(dir, file.SourceFile); err == nil {
+				file.SourceFile = filepath.ToSlash(rel)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			http.Error(w, "Ошибка сериализации ответа: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Успешно сконвертирован проект %s (%d файлов)", entry, len(result.Files))
+	}
+}
+
 // handleGetExample обрабатывает запрос на получение примера
 func handleGetExample(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -6,3 +6,5 @@ require (
 	github.com/a-h/templ v0.3.833
 	github.com/gorilla/mux v1.8.1
 )
+
+require github.com/a-h/parse v0.0.0-20250122154542-74294addb73e // indirect
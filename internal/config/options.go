@@ -1,22 +1,70 @@
 package config
 
+import (
+	"react-to-templ-converter/internal/models"
+	"strings"
+)
+
 // ConversionOptions определяет опции для конвертации React в Templ/HTMX
 type ConversionOptions struct {
 	// UseHtmx включает использование HTMX для интерактивности
 	UseHtmx bool
 
+	// Interactivity задает способ реализации интерактивности:
+	// "htmx" - все состояния на сервере, "alpine" - все состояния на клиенте (Alpine.js),
+	// "hybrid" - локальное состояние интерфейса на клиенте, остальное на сервере
+	Interactivity string
+
 	// ComponentName задает имя компонента (если не указано, используется имя из парсера)
 	ComponentName string
 
 	// PackageName задает имя пакета для Go/templ файлов
 	PackageName string
 
+	// ModulePath задает путь Go модуля, в котором размещается сгенерированный код
+	ModulePath string
+
+	// PackagePath задает путь пакета шаблонов относительно модуля (по умолчанию PackageName).
+	// При конвертации проекта пакеты повторяют структуру каталогов исходников
+	PackagePath string
+
+	// Components содержит компоненты, импортированные из других файлов проекта,
+	// по локальным именам (заполняется при конвертации проекта)
+	Components map[string]*models.ComponentRef
+
+	// Contexts содержит контексты, созданные в других файлах проекта, по именам
+	// (заполняется при конвертации проекта)
+	Contexts map[string]*models.ContextRef
+
 	// IncludeComments добавляет комментарии к сгенерированному коду
 	IncludeComments bool
 
+	// SourcePath задает путь к исходному файлу компонента (необязательно).
+	// Нужен для разрешения пользовательских хуков из локальных модулей
+	SourcePath string
+
+	// ProjectRoot задает каталог проекта: ConvertProject не загружает импорты за его
+	// пределами (по умолчанию - каталог входного файла). При конвертации файлов проекта
+	// заполняется общим каталогом исходников, относительно него хешируются пути CSS модулей
+	ProjectRoot string
+
 	// CustomImports добавляет пользовательские импорты к Go файлам
 	CustomImports []string
 
+	// TailwindConfig добавляет к результату фрагмент tailwind.config.js: content для
+	// templ файлов и safelist с классами манифеста
+	TailwindConfig bool
+
+	// RenderFixture включает сверку рендеринга: исходный компонент рендерится react-dom/server,
+	// сгенерированный templ - временной сборкой Go, расхождения HTML попадают в RenderCheck результата.
+	// Задается командой rendercheck: сервер сверку не выполняет
+	RenderFixture *models.RenderFixture
+
+	// SubscriptionBaseURL задает адрес сервера, относительно которого контроллеры подключаются
+	// к относительным адресам подписок (new EventSource("/api/feed")). Заголовок Host запроса
+	// клиента для этого не используется
+	SubscriptionBaseURL string
+
 	// StatePersistence определяет способ хранения состояния
 	// Возможные значения: "memory", "redis", "database"
 	StatePersistence string
@@ -31,16 +79,27 @@ type ConversionOptions struct {
 	}
 }
 
+// Режимы интерактивности
+const (
+	InteractivityHtmx   = "htmx"
+	InteractivityAlpine = "alpine"
+	InteractivityHybrid = "hybrid"
+)
+
 // NewDefaultOptions создает новые опции конвертации со значениями по умолчанию
 func NewDefaultOptions() *ConversionOptions {
 	options := &ConversionOptions{
 		UseHtmx:          true,
+		Interactivity:    InteractivityHtmx,
 		PackageName:      "templates",
+		ModulePath:       "react-to-templ",
 		IncludeComments:  true,
 		StatePersistence: "memory",
 		Debug:            false,
 	}
 
+	options.SubscriptionBaseURL = "http://localhost:8080"
+
 	options.Indentation.Style = "spaces"
 	options.Indentation.Size = 4
 
@@ -56,5 +115,48 @@ func (o *ConversionOptions) Clone() *ConversionOptions {
 		copy(clone.CustomImports, o.CustomImports)
 	}
 
+	if o.Components != nil {
+		clone.Components = make(map[string]*models.ComponentRef, len(o.Components))
+		for name, ref := range o.Components {
+			clone.Components[name] = ref
+		}
+	}
+
+	if o.Contexts != nil {
+		clone.Contexts = make(map[string]*models.ContextRef, len(o.Contexts))
+		for name, ref := range o.Contexts {
+			clone.Contexts[name] = ref
+		}
+	}
+
 	return &clone
 }
+
+// TemplatesImportPath возвращает путь импорта пакета шаблонов
+func (o *ConversionOptions) TemplatesImportPath() string {
+	modulePath := o.ModulePath
+	if modulePath == "" {
+		modulePath = "react-to-templ"
+	}
+
+	packagePath := o.PackagePath
+	if packagePath == "" {
+		packagePath = o.PackageName
+	}
+	if packagePath == "" || packagePath == "." {
+		packagePath = "templates"
+	}
+
+	return modulePath + "/" + strings.Trim(packagePath, "/")
+}
+
+// InteractivityMode возвращает режим интерактивности; пустое или неизвестное значение
+// означает режим по умолчанию (htmx)
+func (o *ConversionOptions) InteractivityMode() string {
+	switch o.Interactivity {
+	case InteractivityAlpine, InteractivityHybrid:
+		return o.Interactivity
+	default:
+		return InteractivityHtmx
+	}
+}
@@ -0,0 +1,388 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// blockBodyRegex - тело функции в фигурных скобках
+var blockBodyRegex = regexp.MustCompile(`^\{([\s\S]*)\}$`)
+
+// partitionState разделяет состояния компонента между сервером и клиентом
+// в соответствии с режимом интерактивности: в режиме alpine на клиент переносятся
+// все состояния, в режиме hybrid - состояния класса client по анализу потоков данных.
+// Клиентские состояния переносятся в component.ClientState и обрабатываются Alpine.js
+func partitionState(component *models.ReactComponent, options *config.ConversionOptions) {
+	mode := options.InteractivityMode()
+	if mode == config.InteractivityHtmx {
+		return
+	}
+
+	var serverState []models.StateDefinition
+	for _, state := range component.State {
+		if mode == config.InteractivityAlpine || state.Kind == models.StateKindClient {
+			component.ClientState = append(component.ClientState, state)
+		} else {
+			serverState = append(serverState, state)
+		}
+	}
+	component.State = serverState
+}
+
+// isSetterOnlyCode проверяет, состоит ли код только из вызовов сеттеров состояний
+func isSetterOnlyCode(code string, component *models.ReactComponent) bool {
+	body := strings.TrimSpace(arrowPrefixRegex.ReplaceAllString(strings.TrimSpace(code), ""))
+	setters := setterCallRegex(component)
+	if !setters.MatchString(body) {
+		return false
+	}
+	return strings.Trim(setters.ReplaceAllString(body, ""), " \t\r\n;{}") == ""
+}
+
+// referencesClientState проверяет, использует ли выражение клиентские состояния
+func referencesClientState(component *models.ReactComponent, expr string) bool {
+	if component == nil {
+		return false
+	}
+	for _, state := range component.ClientState {
+		if readsState(expr, state.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// alpineData формирует значение x-data с начальными значениями клиентских состояний
+func alpineData(component *models.ReactComponent) string {
+	var fields []string
+	for _, state := range component.ClientState {
+		fields = append(fields, fmt.Sprintf("%s: %s", state.Name, jsLiteral(state.InitialValue)))
+	}
+	return "{ " + strings.Join(fields, ", ") + " }"
+}
+
+// jsLiteral форматирует значение как литерал JavaScript для HTML атрибута (строки в одинарных кавычках)
+func jsLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "'" + alpineAttributeEscaper.Replace(strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v)) + "'"
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = jsLiteral(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var fields []string
+		for _, key := range keys {
+			fields = append(fields, fmt.Sprintf("%s: %s", strconv.Quote(key)[1:len(strconv.Quote(key))-1], jsLiteral(v[key])))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// alpineAttributeEscaper экранирует JS код для значения атрибута в двойных кавычках
+var alpineAttributeEscaper = strings.NewReplacer(`&`, `&amp;`, `"`, `&quot;`)
+
+// alpineExpression подготавливает JS выражение для атрибута Alpine.js: браузер
+// раскодирует значение атрибута, и Alpine.js получает исходный код без изменений
+func alpineExpression(expr string) string {
+	return alpineAttributeEscaper.Replace(strings.TrimSpace(expr))
+}
+
+// translateAlpineHandler переводит вызовы сеттеров клиентских состояний в обработчике
+// в выражение Alpine.js: setOpen(!open) -> open = !open, параметр события - в $event. Возвращает пустую строку,
+// если обработчик не меняет клиентские состояния, и признак того, что весь
+// обработчик выполняется на клиенте
+func translateAlpineHandler(code string, component *models.ReactComponent) (string, bool) {
+	body := strings.TrimSpace(arrowPrefixRegex.ReplaceAllString(strings.TrimSpace(code), ""))
+	if match := blockBodyRegex.FindStringSubmatch(body); match != nil {
+		body = strings.TrimSpace(match[1])
+	}
+
+	// Событие обработчика в Alpine.js доступно как $event: e => setEmail(e.target.value)
+	if match := handlerParamRegex.FindStringSubmatch(strings.TrimSpace(code)); match != nil {
+		param := regexp.MustCompile(`(^|[^\w.$])` + regexp.QuoteMeta(match[1]+match[2]) + `\b`)
+		body = replaceOutsideStrings(body, func(segment string) string {
+			return param.ReplaceAllString(segment, "${1}$$event")
+		})
+	}
+
+	var statements []string
+	clientOnly := true
+
+	setters := setterCallRegex(component)
+	for _, match := range setters.FindAllStringSubmatch(body, -1) {
+		var state *models.StateDefinition
+		for i := range component.ClientState {
+			if component.ClientState[i].Setter == match[1] {
+				state = &component.ClientState[i]
+			}
+		}
+		if state == nil {
+			clientOnly = false
+			continue
+		}
+
+		expr := strings.TrimSpace(match[2])
+		// Функциональное обновление: setOpen(prev => !prev)
+		if updater := setterUpdaterRegex.FindStringSubmatch(expr); updater != nil {
+			expr = regexp.MustCompile(`\b`+regexp.QuoteMeta(updater[1])+`\b`).ReplaceAllString(updater[2], state.Name)
+		}
+
+		statements = append(statements, fmt.Sprintf("%s = %s", state.Name, alpineExpression(expr)))
+	}
+
+	if len(statements) == 0 {
+		return "", false
+	}
+
+	// Кроме сеттеров в обработчике есть другой код - его обрабатывает HTMX
+	rest := strings.Trim(setters.ReplaceAllString(body, ""), " \t\r\n;")
+	if rest != "" {
+		clientOnly = false
+	}
+
+	return strings.Join(statements, "; "), clientOnly
+}
+
+// alpineEventAttribute возвращает имя атрибута Alpine.js для React события
+func alpineEventAttribute(name string) string {
+	switch name {
+	case "onSubmit":
+		return "x-on:submit.prevent"
+	case "onChange":
+		return "x-on:input"
+	default:
+		return "x-on:" + strings.ToLower(name[2:])
+	}
+}
+
+// convertAlpineHandler преобразует обработчик события, меняющий клиентские состояния,
+// в атрибут x-on. Возвращает атрибут и признак того, что обработчик целиком клиентский
+func (c *JSXToHTMXConverter) convertAlpineHandler(name string, value interface{}) (string, bool) {
+	if c.component == nil || len(c.component.ClientState) == 0 ||
+		!strings.HasPrefix(name, "on") || len(name) < 3 || name[2] < 'A' || name[2] > 'Z' {
+		return "", false
+	}
+
+	valueExpr, ok := value.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	code, ok := valueExpr["code"].(string)
+	if !ok {
+		return "", false
+	}
+
+	expr, clientOnly := translateAlpineHandler(resolveHandlerCode(code, c.component), c.component)
+	if expr == "" {
+		return "", false
+	}
+
+	return fmt.Sprintf(" %s=\"%s\"", alpineEventAttribute(name), expr), clientOnly
+}
+
+// convertAlpineBinding преобразует атрибут, зависящий от клиентских состояний, в x-bind
+func (c *JSXToHTMXConverter) convertAlpineBinding(attrName string, value interface{}) string {
+	valueExpr, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	code, ok := valueExpr["code"].(string)
+	if !ok || !referencesClientState(c.component, code) {
+		return ""
+	}
+
+	return c.alpineAttribute("x-bind:"+attrName, code)
+}
+
+// convertAlpineText преобразует выражение, зависящее от клиентских состояний, в x-text
+func (c *JSXToHTMXConverter) convertAlpineText(content string, indentation string) string {
+	return fmt.Sprintf("%s<span%s></span>\n", indentation, c.alpineAttribute("x-text", content))
+}
+
+// alpineAttribute формирует атрибут Alpine.js с выражением. Серверных состояний и пропсов
+// нет в x-data: их значения подставляются в выражение при рендеринге литералами JavaScript
+func (c *JSXToHTMXConverter) alpineAttribute(name string, expr string) string {
+	expr = strings.TrimSpace(expr)
+	refs := serverReferenceRegex(c.component)
+	if refs == nil {
+		return fmt.Sprintf(" %s=\"%s\"", name, alpineExpression(expr))
+	}
+
+	// Серверные ссылки заменяются метками \x00, затем код между ними - строками Go
+	var values []string
+	marked := replaceOutsideStrings(expr, func(code string) string {
+		return refs.ReplaceAllStringFunc(code, func(match string) string {
+			parts := refs.FindStringSubmatch(match)
+			values = append(values, serverValueExpr(c.component, parts[2]))
+			return parts[1] + "\x00"
+		})
+	})
+	if len(values) == 0 {
+		return fmt.Sprintf(" %s=\"%s\"", name, alpineExpression(expr))
+	}
+
+	var parts []string
+	for i, segment := range strings.Split(marked, "\x00") {
+		if segment != "" {
+			parts = append(parts, strconv.Quote(segment))
+		}
+		if i < len(values) {
+			parts = append(parts, c.javaScriptValue(values[i]))
+		}
+	}
+	return fmt.Sprintf(" %s={ %s }", name, strings.Join(parts, " + "))
+}
+
+// javaScriptValue возвращает выражение Go, формирующее литерал JavaScript со значением поля:
+// числа и булевы значения печатаются как есть, строки и составные значения - как JSON
+func (c *JSXToHTMXConverter) javaScriptValue(goExpr string) string {
+	switch c.fieldGoType(goExpr) {
+	case "float64", "int", "bool":
+		return "fmt.Sprint(" + goExpr + ")"
+	case "string":
+		return "strconv.Quote(" + goExpr + ")"
+	}
+	return "func() string { value, _ := json.Marshal(" + goExpr + "); return string(value) }()"
+}
+
+// serverReferenceRegex возвращает регулярное выражение ссылок на серверные состояния и
+// пропсы компонента (группа 2 - имя или props.имя); nil, если их нет
+func serverReferenceRegex(component *models.ReactComponent) *regexp.Regexp {
+	if component == nil {
+		return nil
+	}
+	var names []string
+	for _, state := range component.State {
+		names = append(names, regexp.QuoteMeta(state.Name))
+	}
+	for _, prop := range component.Props {
+		if prop.Name != "children" && prop.Name != "props" {
+			names = append(names, regexp.QuoteMeta(prop.Name))
+		}
+	}
+	if len(component.Props) > 0 {
+		names = append(names, `props\.\w+`)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(^|[^\w.$])(` + strings.Join(names, "|") + `)\b`)
+}
+
+// serverValueExpr возвращает выражение Go со значением серверного состояния или пропса
+func serverValueExpr(component *models.ReactComponent, ref string) string {
+	if name, ok := strings.CutPrefix(ref, "props."); ok {
+		return "props." + exportedName(name)
+	}
+	for _, state := range component.State {
+		if state.Name == ref {
+			return "state." + exportedName(ref)
+		}
+	}
+	return "props." + exportedName(ref)
+}
+
+// referencesServerValue проверяет, ссылается ли код вне строковых литералов на серверные значения
+func referencesServerValue(refs *regexp.Regexp, code string) bool {
+	found := false
+	replaceOutsideStrings(code, func(segment string) string {
+		found = found || refs.MatchString(segment)
+		return segment
+	})
+	return found
+}
+
+// alpineWarnings сообщает о выражениях Alpine.js, которые читают и клиентские, и серверные
+// значения: серверные подставляются при рендеринге и на клиенте не меняются до перерисовки
+func alpineWarnings(component *models.ReactComponent, jsx *models.JSXElement) []string {
+	if jsx == nil || len(component.ClientState) == 0 {
+		return nil
+	}
+	refs := serverReferenceRegex(component)
+	if refs == nil {
+		return nil
+	}
+
+	var codes []string
+	if jsx.Type == "expression" {
+		if condition, ok := jsx.Props["condition"].(string); ok {
+			codes = append(codes, condition)
+		} else if content, ok := jsx.Props["content"].(string); ok {
+			codes = append(codes, content)
+		}
+	} else {
+		for name, value := range jsx.Props {
+			if v, ok := value.(map[string]interface{}); ok && !isEventProp(name) {
+				if code, ok := v["code"].(string); ok {
+					codes = append(codes, code)
+				}
+			}
+		}
+		sort.Strings(codes)
+	}
+
+	var warnings []string
+	for _, code := range codes {
+		if referencesClientState(component, code) && referencesServerValue(refs, code) {
+			warnings = append(warnings, fmt.Sprintf("Выражение %s читает клиентские состояния и серверные значения: серверные значения подставлены в выражение Alpine.js при рендеринге и обновляются только при перерисовке на сервере", singleLine(code)))
+		}
+	}
+	for _, child := range jsx.Children {
+		warnings = append(warnings, alpineWarnings(component, child)...)
+	}
+	return warnings
+}
+
+// convertConditional преобразует условный рендеринг {cond && <X />}. Условие
+// на клиентских состояниях превращается в x-show, остальные - в templ if
+func (c *JSXToHTMXConverter) convertConditional(jsx *models.JSXElement, indent int) string {
+	var sb strings.Builder
+	indentation := strings.Repeat("\t", indent)
+	condition, _ := jsx.Props["condition"].(string)
+
+	if referencesClientState(c.component, condition) {
+		show := c.alpineAttribute("x-show", condition)
+		for _, child := range jsx.Children {
+			if isHTMLTag(child.Type) {
+				// Атрибут добавляется к самому элементу при конвертации его атрибутов
+				c.pendingAttrs = show
+				sb.WriteString(c.ConvertJSXToTempl(child, indent))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("%s<div%s>\n", indentation, show))
+			sb.WriteString(c.ConvertJSXToTempl(child, indent+1))
+			sb.WriteString(indentation + "</div>\n")
+		}
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("%sif %s {\n", indentation, c.convertReactExprToGoExpr(condition)))
+	for _, child := range jsx.Children {
+		sb.WriteString(c.ConvertJSXToTempl(child, indent+1))
+	}
+	sb.WriteString(indentation + "}\n")
+
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+	"sort"
+	"strings"
+)
+
+// collectClasses собирает имена классов, которые выводит разметка компонента: постоянные
+// className, классы, включаемые условиями (clsx, тернарные выражения), и все возможные
+// имена, собираемые из пропсов с типом-объединением (btn-${variant}). Tailwind не видит
+// такие классы в исходниках шаблонов, поэтому они попадают в манифест (см. models.ClassManifest)
+func collectClasses(component *models.ReactComponent, options *config.ConversionOptions) []string {
+	if component.JSX == nil {
+		return nil
+	}
+
+	c := NewJSXToHTMXConverter(options)
+	c.component = component
+
+	seen := make(map[string]bool)
+	c.collectElementClasses(component.JSX, seen)
+
+	classes := make([]string, 0, len(seen))
+	for name := range seen {
+		classes = append(classes, name)
+	}
+	sort.Strings(classes)
+	return classes
+}
+
+// collectElementClasses добавляет классы элемента и его потомков, включая разметку в пропсах
+func (c *JSXToHTMXConverter) collectElementClasses(jsx *models.JSXElement, seen map[string]bool) {
+	for _, name := range jsx.PropNames() {
+		// className элемента и пропсы с классами дочерних компонентов (headerClassName)
+		if name != "className" && name != "class" && !strings.HasSuffix(name, "ClassName") {
+			continue
+		}
+
+		switch value := jsx.Props[name].(type) {
+		case string:
+			for _, class := range strings.Fields(value) {
+				seen[class] = true
+			}
+		case map[string]interface{}:
+			code, _ := value["code"].(string)
+			items, ok := c.classItems(code)
+			if !ok {
+				items = []classItem{c.composedClass([]templatePiece{{text: code, code: true}})}
+			}
+			for _, item := range items {
+				for _, class := range item.names {
+					seen[class] = true
+				}
+			}
+		}
+	}
+
+	for _, child := range jsx.Children {
+		c.collectElementClasses(child, seen)
+	}
+	_, trees := slotTrees(jsx)
+	for _, tree := range trees {
+		c.collectElementClasses(tree, seen)
+	}
+}
@@ -0,0 +1,462 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Модули, функции которых собирают className из аргументов
+var classNameModules = map[string]bool{"clsx": true, "classnames": true, "clsx/lite": true}
+
+// Обычные имена функций сборки className, если импорт не найден
+var classNameFunctions = map[string]bool{"clsx": true, "classnames": true, "classNames": true, "cx": true, "cn": true}
+
+var (
+	// Вызов функции: clsx('btn', {...})
+	classCallRegex = regexp.MustCompile(`^([A-Za-z_$][\w$]*)\(([\s\S]*)\)$`)
+	// Массив классов, собираемый в строку: ['btn', active && 'active'].join(' ')
+	classJoinRegex = regexp.MustCompile(`^(\[[\s\S]*\])\.join\(\s*['"] ['"]\s*\)$`)
+	// Подстановка в шаблонной строке
+	templateSubstitutionRegex = regexp.MustCompile(`\$\{([^{}]*(?:\{[^{}]*\}[^{}]*)*)\}`)
+	// Простое выражение Go, отрицание которого не требует скобок
+	simpleOperandRegex = regexp.MustCompile(`^!?[\w.]+$`)
+)
+
+// classItem - аргумент templ.Classes: имя класса (литерал Go или строковое выражение)
+// и условие, при котором класс включается (templ.KV)
+type classItem struct {
+	key       string
+	condition string
+	// Имена классов, которые может дать элемент (для манифеста классов)
+	names []string
+}
+
+// constantClass создает элемент templ.Classes с постоянным именем класса
+func constantClass(name string, condition string) classItem {
+	return classItem{key: strconv.Quote(name), condition: condition, names: []string{name}}
+}
+
+// goArg возвращает аргумент templ.Classes
+func (item classItem) goArg() string {
+	if item.condition == "" {
+		return item.key
+	}
+	return fmt.Sprintf("templ.KV(%s, %s)", item.key, item.condition)
+}
+
+// classAttribute переводит className с выражением в атрибут class. Вызовы clsx/classnames,
+// шаблонные строки, конкатенация и условия становятся аргументами templ.Classes
+// (условные классы - templ.KV), постоянные классы - обычным атрибутом
+func (c *JSXToHTMXConverter) classAttribute(tag string, code string) string {
+	items, ok := c.classItems(code)
+	if !ok {
+		return c.expressionAttribute(tag, "class", code)
+	}
+
+	// Все классы постоянные: class="btn primary"
+	var names, args []string
+	constant := true
+	for _, item := range items {
+		args = append(args, item.goArg())
+		if name, err := strconv.Unquote(item.key); err == nil && item.condition == "" {
+			names = append(names, name)
+		} else {
+			constant = false
+		}
+	}
+	if !constant {
+		return fmt.Sprintf(" class={ templ.Classes(%s) }", strings.Join(args, ", "))
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return constantAttribute(tag, "class", strings.Join(names, " "))
+}
+
+// classItems разбирает выражение className в аргументы templ.Classes.
+// ok = false, если выражение не похоже на сборку классов
+func (c *JSXToHTMXConverter) classItems(code string) ([]classItem, bool) {
+	code = strings.TrimSpace(code)
+
+	// clsx('btn', { active: isActive }), classNames(...)
+	if match := classCallRegex.FindStringSubmatch(code); match != nil && c.isClassNameFunction(match[1]) {
+		var items []classItem
+		for _, arg := range splitTopLevel(match[2], ',') {
+			items = append(items, c.classArgItems(arg)...)
+		}
+		return items, true
+	}
+
+	// ['btn', active && 'active'].join(' ')
+	if match := classJoinRegex.FindStringSubmatch(code); match != nil {
+		return c.classArgItems(match[1]), true
+	}
+
+	// `btn ${primary ? 'p' : ''}`
+	if strings.HasPrefix(code, "`") && strings.HasSuffix(code, "`") && len(code) > 1 {
+		return c.templateClassItems(code[1 : len(code)-1]), true
+	}
+
+	// 'btn ' + (primary ? 'p' : '')
+	if parts := splitTopLevel(code, '+'); len(parts) > 1 && hasStringOperand(parts) {
+		var items []classItem
+		for _, part := range parts {
+			items = append(items, c.classArgItems(unwrapParens(part))...)
+		}
+		return items, true
+	}
+
+	// primary ? 'p' : 's', active && 'active'
+	if _, _, _, ok := splitTernary(code); ok || len(splitLogical(code, "&&")) > 1 {
+		return c.classArgItems(code), true
+	}
+	return nil, false
+}
+
+// templatePiece - часть имени класса в шаблонной строке: текст или подстановка ${}
+type templatePiece struct {
+	text string
+	code bool
+}
+
+// templateClassItems разбирает шаблонную строку с классами: постоянные части делятся
+// на имена классов, подстановки разбираются как аргументы clsx. Подстановка внутри
+// имени класса (btn-${variant}) дает имя, собираемое при рендеринге
+func (c *JSXToHTMXConverter) templateClassItems(template string) []classItem {
+	var items []classItem
+	var word []templatePiece
+
+	flush := func() {
+		switch {
+		case len(word) == 1 && word[0].code:
+			items = append(items, c.classArgItems(word[0].text)...)
+		case len(word) == 1:
+			items = append(items, constantClass(word[0].text, ""))
+		case len(word) > 1:
+			items = append(items, c.composedClass(word))
+		}
+		word = nil
+	}
+
+	// static добавляет текст: пробелы разделяют имена классов
+	static := func(text string) {
+		for i, name := range strings.Fields(text) {
+			if i > 0 || startsWithSpace(text) {
+				flush()
+			}
+			word = append(word, templatePiece{text: name})
+		}
+		if endsWithSpace(text) {
+			flush()
+		}
+	}
+
+	last := 0
+	for _, match := range templateSubstitutionRegex.FindAllStringSubmatchIndex(template, -1) {
+		static(template[last:match[0]])
+		word = append(word, templatePiece{text: template[match[2]:match[3]], code: true})
+		last = match[1]
+	}
+	static(template[last:])
+	flush()
+	return items
+}
+
+// composedClass создает класс, имя которого собирается из текста и подстановок:
+// btn-${variant}. Если подстановки - пропсы с типом-объединением строк
+// ('primary' | 'secondary'), в манифест попадают все возможные имена
+func (c *JSXToHTMXConverter) composedClass(pieces []templatePiece) classItem {
+	var keys []string
+	names := []string{""}
+	for _, piece := range pieces {
+		if !piece.code {
+			keys = append(keys, strconv.Quote(piece.text))
+			for i := range names {
+				names[i] += piece.text
+			}
+			continue
+		}
+
+		goExpr := c.convertReactExprToGoExpr(unwrapParens(piece.text))
+		keys = append(keys, c.stringExpression(goExpr))
+		var expanded []string
+		for _, name := range names {
+			for _, value := range c.fieldValues(goExpr) {
+				expanded = append(expanded, name+value)
+			}
+		}
+		names = expanded
+	}
+	return classItem{key: strings.Join(keys, " + "), names: names}
+}
+
+// fieldValues возвращает возможные значения пропса с типом-объединением строковых литералов
+func (c *JSXToHTMXConverter) fieldValues(goExpr string) []string {
+	match := fieldReferenceRegex.FindStringSubmatch(strings.TrimSpace(goExpr))
+	if match == nil || match[1] != "props" || c.component == nil {
+		return nil
+	}
+	for _, prop := range c.component.Props {
+		if exportedName(prop.Name) == match[2] {
+			values, _ := models.StringUnionValues(prop.Type)
+			return values
+		}
+	}
+	return nil
+}
+
+// classArgItems переводит аргумент clsx в аргументы templ.Classes: строки - в имена
+// классов, объекты {active: isActive} и cond && 'x' - в templ.KV, тернарные выражения -
+// в пару templ.KV с противоположными условиями. Остальные выражения передаются строкой
+func (c *JSXToHTMXConverter) classArgItems(arg string) []classItem {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return nil
+	}
+
+	if literal, ok := models.ParseJSLiteral(arg); ok {
+		switch v := literal.(type) {
+		case string:
+			return constantClasses(strings.Fields(v), "")
+		case float64:
+			if v != 0 {
+				return []classItem{constantClass(strconv.FormatFloat(v, 'f', -1, 64), "")}
+			}
+			return nil
+		case []interface{}, *models.JSObject:
+		default:
+			// null, undefined, true, false
+			return nil
+		}
+	}
+
+	// [...] - аргументы clsx во вложенном массиве
+	if strings.HasPrefix(arg, "[") && strings.HasSuffix(arg, "]") {
+		var items []classItem
+		for _, item := range splitTopLevel(arg[1:len(arg)-1], ',') {
+			items = append(items, c.classArgItems(item)...)
+		}
+		return items
+	}
+
+	// { active: isActive, 'btn-lg': size === 'lg' }
+	if entries, ok := styleEntries(arg); ok {
+		var items []classItem
+		for _, entry := range entries {
+			items = append(items, c.conditionalClasses(entry[0], entry[1])...)
+		}
+		return items
+	}
+
+	// primary ? 'p' : 's'
+	if condition, whenTrue, whenFalse, ok := splitTernary(arg); ok {
+		goCondition := c.classCondition(condition)
+		items := c.branchClasses(whenTrue, goCondition)
+		return append(items, c.branchClasses(whenFalse, negateCondition(goCondition))...)
+	}
+
+	// active && 'active'
+	if operands := splitLogical(arg, "&&"); len(operands) > 1 {
+		last := operands[len(operands)-1]
+		if _, ok := literalClassNames(last); ok {
+			return c.conditionalClasses(last, strings.Join(operands[:len(operands)-1], " && "))
+		}
+	}
+
+	// Выражение, значение которого - строка классов
+	return []classItem{c.composedClass([]templatePiece{{text: arg, code: true}})}
+}
+
+// conditionalClasses возвращает классы, включаемые условием (ключ объекта clsx
+// или литерал после &&)
+func (c *JSXToHTMXConverter) conditionalClasses(classes string, condition string) []classItem {
+	names, ok := literalClassNames(classes)
+	if !ok {
+		names = strings.Fields(strings.Trim(classes, `"'`))
+	}
+
+	if literal, ok := models.ParseJSLiteral(condition); ok {
+		// {active: true} - класс включен всегда, {active: false} - никогда
+		if literal == nil || literal == false || literal == "" || literal == 0.0 {
+			return nil
+		}
+		return constantClasses(names, "")
+	}
+	return constantClasses(names, c.classCondition(condition))
+}
+
+// branchClasses возвращает классы ветки тернарного выражения с условием ветки:
+// литерал делится на имена классов, выражение (styles.active) передается строкой
+func (c *JSXToHTMXConverter) branchClasses(branch string, condition string) []classItem {
+	if names, ok := literalClassNames(branch); ok {
+		return constantClasses(names, condition)
+	}
+	item := c.composedClass([]templatePiece{{text: branch, code: true}})
+	item.condition = condition
+	return []classItem{item}
+}
+
+// constantClasses создает элементы templ.Classes для имен классов с общим условием
+func constantClasses(names []string, condition string) []classItem {
+	var items []classItem
+	for _, name := range names {
+		items = append(items, constantClass(name, condition))
+	}
+	return items
+}
+
+// classCondition переводит условие класса в булево выражение Go: значения JavaScript
+// проверяются на истинность по типу поля (число - != 0, строка - != "")
+func (c *JSXToHTMXConverter) classCondition(condition string) string {
+	goExpr := c.convertReactExprToGoExpr(unwrapParens(condition))
+	switch c.fieldGoType(goExpr) {
+	case "int", "float64":
+		return goExpr + " != 0"
+	case "string":
+		return goExpr + ` != ""`
+	}
+	return goExpr
+}
+
+// isClassNameFunction проверяет, собирает ли функция className: импорт из clsx/classnames
+// или одно из обычных имен таких функций
+func (c *JSXToHTMXConverter) isClassNameFunction(name string) bool {
+	if c.component != nil {
+		for _, imp := range c.component.Imports {
+			if !classNameModules[imp.Source] {
+				continue
+			}
+			if imp.Defaults == name {
+				return true
+			}
+			for _, named := range imp.Named {
+				if named == name {
+					return true
+				}
+			}
+		}
+	}
+	return classNameFunctions[name]
+}
+
+// hasStringOperand проверяет, есть ли среди слагаемых строковый литерал (конкатенация строк)
+func hasStringOperand(parts []string) bool {
+	for _, part := range parts {
+		if _, ok := literalClassNames(part); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// literalClassNames возвращает имена классов строкового литерала (пустая строка - пустой список)
+func literalClassNames(code string) ([]string, bool) {
+	literal, ok := models.ParseJSLiteral(code)
+	if !ok {
+		return nil, false
+	}
+	text, ok := literal.(string)
+	return strings.Fields(text), ok
+}
+
+// endsWithSpace проверяет, заканчивается ли текст пробельным символом (или пуст)
+func endsWithSpace(text string) bool {
+	return text == "" || strings.TrimRight(text, " \t\n") != text
+}
+
+// startsWithSpace проверяет, начинается ли текст пробельным символом (или пуст)
+func startsWithSpace(text string) bool {
+	return text == "" || strings.TrimLeft(text, " \t\n") != text
+}
+
+// negateCondition возвращает отрицание булева выражения Go
+func negateCondition(condition string) string {
+	if strings.HasPrefix(condition, "!") && simpleOperandRegex.MatchString(condition) {
+		return condition[1:]
+	}
+	if simpleOperandRegex.MatchString(condition) {
+		return "!" + condition
+	}
+	return "!(" + condition + ")"
+}
+
+// splitLogical делит выражение по логическому оператору верхнего уровня (&&, ||)
+func splitLogical(code string, operator string) []string {
+	var operands []string
+	for _, part := range strings.Split(code, operator) {
+		if len(operands) > 0 && !balanced(operands[len(operands)-1]) {
+			operands[len(operands)-1] += operator + part
+			continue
+		}
+		operands = append(operands, part)
+	}
+	for i := range operands {
+		operands[i] = strings.TrimSpace(operands[i])
+	}
+	return operands
+}
+
+// balanced проверяет, закрыты ли в коде все скобки и строки
+func balanced(code string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(' || ch == '[' || ch == '{':
+			depth++
+		case ch == ')' || ch == ']' || ch == '}':
+			if depth--; depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0 && quote == 0
+}
+
+// callArgument возвращает начало кода до скобки, закрывающей вызов: аргумент, захваченный
+// вместе с хвостом внешнего вызова ("a"), 100 из setTimeout(() => setQuery("a"), 100)), - "a"
+func callArgument(code string) string {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(' || ch == '[' || ch == '{':
+			depth++
+		case ch == ')' || ch == ']' || ch == '}':
+			if depth--; depth < 0 {
+				return code[:i]
+			}
+		}
+	}
+	return code
+}
+
+// unwrapParens убирает внешние скобки выражения: (a ? 'x' : 'y') -> a ? 'x' : 'y'
+func unwrapParens(code string) string {
+	code = strings.TrimSpace(code)
+	for strings.HasPrefix(code, "(") && strings.HasSuffix(code, ")") && balanced(code[1:len(code)-1]) {
+		code = strings.TrimSpace(code[1 : len(code)-1])
+	}
+	return code
+}
@@ -0,0 +1,74 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+	"strings"
+)
+
+// Оболочка - внешний templ компонент с тем, что должно пережить перерисовку корневого
+// элемента через hx-swap: триггеры эффектов, иначе каждый ответ сервера пересоздавал бы
+// таймеры и подключение SSE, и x-data клиентских состояний, иначе Alpine.js сбрасывал бы
+// их при каждом ответе сервера. Обработчики перерисовывают только корневой элемент -
+// templ компонент <Name>Root, а оболочку выводят New<Name> и родительские компоненты
+
+// hasShell проверяет, нужна ли компоненту оболочка
+func hasShell(component *models.ReactComponent, options *config.ConversionOptions) bool {
+	if component == nil || !options.UseHtmx {
+		return false
+	}
+	if hasClientStateShell(component) {
+		return true
+	}
+	for _, effect := range component.Effects {
+		if needsEffectHandler(effect) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasClientStateShell проверяет, выносится ли x-data в оболочку: клиентские состояния
+// нужно сохранить, только если корневой элемент перерисовывают серверные обработчики
+func hasClientStateShell(component *models.ReactComponent) bool {
+	return len(component.ClientState) > 0 && (len(component.State) > 0 || len(component.Reducers) > 0)
+}
+
+// rootTemplName возвращает имя templ компонента, который перерисовывают обработчики
+func rootTemplName(component *models.ReactComponent, options *config.ConversionOptions) string {
+	if hasShell(component, options) {
+		return exportedName(component.Name) + "Root"
+	}
+	return exportedName(component.Name)
+}
+
+// RootTemplName возвращает имя templ компонента с корневым элементом: генератор
+// объявляет под ним разметку, если компоненту нужна оболочка
+func (c *JSXToHTMXConverter) RootTemplName() string {
+	return rootTemplName(c.component, c.options)
+}
+
+// ShellTemplate возвращает тело templ компонента-оболочки, который выводит корневой
+// компонент с аргументами args и триггеры эффектов рядом с ним (внутри элемента с x-data,
+// если он нужен). Пустая строка - оболочка не нужна
+func (c *JSXToHTMXConverter) ShellTemplate(args string) string {
+	if !hasShell(c.component, c.options) {
+		return ""
+	}
+
+	// Оболочка с x-data не создает блока в раскладке: display: contents
+	indent := 1
+	var sb strings.Builder
+	if hasClientStateShell(c.component) {
+		sb.WriteString(fmt.Sprintf("\t<div x-data=\"%s\" style=\"display: contents\">\n", alpineData(c.component)))
+		indent = 2
+	}
+	indentation := strings.Repeat("\t", indent)
+	sb.WriteString(fmt.Sprintf("%s@%s(%s)\n", indentation, rootTemplName(c.component, c.options), args))
+	sb.WriteString(c.convertEffectTriggers(indent))
+	if hasClientStateShell(c.component) {
+		sb.WriteString("\t</div>\n")
+	}
+	return sb.String()
+}
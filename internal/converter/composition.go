@@ -0,0 +1,206 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Обращение к пропсу: header или props.header
+	propReferenceRegex = regexp.MustCompile(`^(?:props\.)?(\w+)$`)
+	// Вызов render prop в разметке: renderItem(item) или props.renderItem(item)
+	renderPropCallRegex = regexp.MustCompile(`^(?:props\.)?(\w+)\(([\s\S]*)\)$`)
+)
+
+// jsxPropValue возвращает JSX дерево, если значение пропса - разметка
+// (header={<h1>...</h1>}), иначе nil
+func jsxPropValue(value interface{}) *models.JSXElement {
+	valueMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if _, isCode := valueMap["code"]; isCode {
+		return nil
+	}
+	if kind, ok := valueMap["type"].(string); !ok || kind == "expression" || kind == "spread" || kind == "renderProp" {
+		return nil
+	}
+
+	data, err := json.Marshal(valueMap)
+	if err != nil {
+		return nil
+	}
+	var jsx models.JSXElement
+	if err := json.Unmarshal(data, &jsx); err != nil {
+		return nil
+	}
+	return &jsx
+}
+
+// slotReference возвращает имя пропса-разметки, на который ссылается выражение
+// ({children}, {props.header}). Для children возвращается "children"
+func (c *JSXToHTMXConverter) slotReference(expr string) string {
+	match := propReferenceRegex.FindStringSubmatch(strings.TrimSpace(expr))
+	if match == nil || c.component == nil {
+		return ""
+	}
+
+	for _, prop := range c.component.Props {
+		if prop.Name != match[1] {
+			continue
+		}
+		if prop.Name == "children" || models.IsSlotType(prop.Type) {
+			return prop.Name
+		}
+	}
+	return ""
+}
+
+// convertSlotExpression выводит пропс-разметку: children - через механизм дочерних
+// элементов templ, остальные пропсы - как templ.Component
+func (c *JSXToHTMXConverter) convertSlotExpression(name string, indentation string) string {
+	if name == "children" {
+		return indentation + "{ children... }\n"
+	}
+
+	field := "props." + exportedName(name)
+	return fmt.Sprintf("%sif %s != nil {\n%s\t@%s\n%s}\n", indentation, field, indentation, field, indentation)
+}
+
+// childComponentID возвращает id экземпляра дочернего компонента, производный от id
+// родителя: по key, если он задан, иначе по номеру экземпляра этого компонента
+func (c *JSXToHTMXConverter) childComponentID(jsx *models.JSXElement) string {
+	switch key := jsx.Props["key"].(type) {
+	case string:
+		return fmt.Sprintf("id + %q", "-"+key)
+	case map[string]interface{}:
+		if code, ok := key["code"].(string); ok {
+			return fmt.Sprintf("id + \"-\" + fmt.Sprint(%s)", c.convertReactExprToGoExpr(code))
+		}
+	}
+
+	if c.childIDs == nil {
+		c.childIDs = make(map[string]int)
+	}
+	c.childIDs[jsx.Type]++
+	return fmt.Sprintf("id + \"-%s%d\"", strings.ToLower(jsx.Type), c.childIDs[jsx.Type])
+}
+
+// convertSlotTemplate выносит разметку, переданную в пропс, в отдельный templ компонент
+// с параметрами родителя (и параметрами render prop) и возвращает вызов этого компонента
+func (c *JSXToHTMXConverter) convertSlotTemplate(owner string, prop string, jsx *models.JSXElement, extraParams []string, extraArgs []string) string {
+	c.slotCount++
+	name := fmt.Sprintf("%s%sSlot%d", strings.ToLower(c.componentName()[:1]), c.componentName()[1:], c.slotCount)
+
+	// Разметка слота конвертируется как корень фрагмента: без атрибутов корневого элемента
+	savedRegion, savedIndent, savedPending := c.currentRegion, c.indent, c.pendingAttrs
+	c.currentRegion, c.pendingAttrs = jsx, ""
+	body := c.ConvertJSXToTempl(jsx, 1)
+	c.currentRegion, c.indent, c.pendingAttrs = savedRegion, savedIndent, savedPending
+
+	params := appendNonEmpty([]string{c.templParams}, extraParams...)
+	args := appendNonEmpty([]string{c.templArgs}, extraArgs...)
+
+	c.regionTemplates = append(c.regionTemplates,
+		fmt.Sprintf("// %s - разметка пропса %s компонента %s\ntempl %s(%s) {\n%s}\n\n",
+			name, prop, owner, name, strings.Join(params, ", "), body))
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+}
+
+// convertRenderPropTemplate выносит разметку render prop в templ компонент и возвращает
+// функцию, создающую его по аргументам: func(item string) templ.Component { ... }.
+// Типы параметров берутся из объявления пропса у дочернего компонента
+func (c *JSXToHTMXConverter) convertRenderPropTemplate(owner string, prop string, params []string, jsx *models.JSXElement, propType string) string {
+	_, goTypes, ok := models.RenderPropParams(propType)
+
+	var typed []string
+	for i, param := range params {
+		goType := "interface{}"
+		if ok && i < len(goTypes) {
+			goType = goTypes[i]
+		}
+		typed = append(typed, param+" "+goType)
+	}
+
+	call := c.convertSlotTemplate(owner, prop, jsx, typed, params)
+	return fmt.Sprintf("func(%s) templ.Component { return %s }", strings.Join(typed, ", "), call)
+}
+
+// appendNonEmpty добавляет к срезу непустые значения, убирая пустые
+func appendNonEmpty(values []string, items ...string) []string {
+	var result []string
+	for _, value := range append(values, items...) {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// slotTrees возвращает разметку, переданную в пропсы элемента, в порядке исходного кода
+func slotTrees(jsx *models.JSXElement) (names []string, trees []*models.JSXElement) {
+	var slotNames []string
+	for _, name := range jsx.PropNames() {
+		tree := jsxPropValue(jsx.Props[name])
+		if tree == nil {
+			_, tree = renderPropValue(jsx.Props[name])
+		}
+		if tree != nil {
+			slotNames = append(slotNames, name)
+			trees = append(trees, tree)
+		}
+	}
+	return slotNames, trees
+}
+
+// renderPropValue возвращает параметры и разметку render prop (renderItem={item => <li />})
+func renderPropValue(value interface{}) ([]string, *models.JSXElement) {
+	valueMap, ok := value.(map[string]interface{})
+	if !ok || valueMap["type"] != "renderProp" {
+		return nil, nil
+	}
+
+	var params []string
+	if list, ok := valueMap["params"].([]interface{}); ok {
+		for _, param := range list {
+			if name, ok := param.(string); ok {
+				params = append(params, name)
+			}
+		}
+	}
+
+	return params, jsxPropValue(valueMap["jsx"])
+}
+
+// convertRenderPropCall выводит вызов render prop ({renderItem(item)}) как templ компонент
+func (c *JSXToHTMXConverter) convertRenderPropCall(expr string, indentation string) (string, bool) {
+	match := renderPropCallRegex.FindStringSubmatch(strings.TrimSpace(expr))
+	if match == nil || c.component == nil {
+		return "", false
+	}
+
+	for _, prop := range c.component.Props {
+		if prop.Name != match[1] {
+			continue
+		}
+		if _, _, ok := models.RenderPropParams(prop.Type); !ok {
+			return "", false
+		}
+
+		var args []string
+		for _, arg := range splitTopLevel(match[2], ',') {
+			if arg = strings.TrimSpace(arg); arg != "" {
+				args = append(args, c.convertReactExprToGoExpr(arg))
+			}
+		}
+
+		field := "props." + exportedName(prop.Name)
+		return fmt.Sprintf("%sif %s != nil {\n%s\t@%s(%s)\n%s}\n",
+			indentation, field, indentation, field, strings.Join(args, ", "), indentation), true
+	}
+	return "", false
+}
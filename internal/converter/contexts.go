@@ -0,0 +1,37 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+)
+
+// contextWarnings сообщает о контекстах, которые компонент читает через useContext, но
+// которые создаются в другом модуле и не читаются из его пакета: вне проекта шаблон читает
+// их через заглушку с нулевым значением, а контексты модуля без разметки (он не
+// конвертируется) - через значение по умолчанию из createContext
+func contextWarnings(component *models.ReactComponent, options *config.ConversionOptions) []string {
+	created := make(map[string]bool)
+	for _, context := range component.Contexts {
+		if context.Created {
+			created[context.Name] = true
+		}
+	}
+
+	var warnings []string
+	reported := make(map[string]bool)
+	for _, context := range component.Contexts {
+		if context.Variable == "" || created[context.Name] || reported[context.Name] {
+			continue
+		}
+		reported[context.Name] = true
+		if ref := options.Contexts[context.Name]; ref != nil {
+			if ref.ImportPath == "" {
+				warnings = append(warnings, fmt.Sprintf("Контекст %s создается в %s, который не содержит разметки и не конвертируется: шаблон читает значение по умолчанию из createContext, а не значение провайдера", context.Name, ref.SourceFile))
+			}
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("Контекст %s создается в другом модуле, который не конвертируется: шаблон читает его через заглушку с нулевым значением, замените ее вызовом %sValue(ctx) из пакета этого модуля", context.Name, context.Name))
+	}
+	return warnings
+}
@@ -5,6 +5,7 @@ import (
 	"react-to-templ-converter/internal/config"
 	"react-to-templ-converter/internal/models"
 	"react-to-templ-converter/internal/parser"
+	"sort"
 	"strings"
 )
 
@@ -44,11 +45,26 @@ type GoGenerator interface {
 // Convert преобразует React код в templ шаблоны и Go код
 func (c *ReactToTemplConverter) Convert(reactCode string, options *config.ConversionOptions) (*models.ConversionResult, error) {
 	// Парсинг React компонента
-	component, err := c.parser.ParseComponent(reactCode)
+	component, err := c.parse(reactCode, options.SourcePath)
 	if err != nil {
 		return nil, err
 	}
 
+	result, err := c.convertParsed(component, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Сверка рендеринга с исходным компонентом на фикстуре
+	if options.RenderFixture != nil {
+		result.RenderCheck = c.checkRendering(reactCode, component, result, options)
+	}
+
+	return result, nil
+}
+
+// convertParsed конвертирует уже разобранный компонент
+func (c *ReactToTemplConverter) convertParsed(component *models.ReactComponent, options *config.ConversionOptions) (*models.ConversionResult, error) {
 	// Если имя компонента не указано, используем имя из опций
 	if component.Name == "" {
 		if options.ComponentName != "" {
@@ -59,13 +75,17 @@ func (c *ReactToTemplConverter) Convert(reactCode string, options *config.Conver
 				if defaultExport, ok := component.Exports["default"].(string); ok && defaultExport != "" {
 					component.Name = defaultExport
 				} else if len(component.Exports) > 0 {
-					// Берем первый экспорт
+					// Берем первый по алфавиту экспорт, чтобы имя не зависело от обхода map
+					var names []string
 					for name := range component.Exports {
 						if name != "default" {
-							component.Name = name
-							break
+							names = append(names, name)
 						}
 					}
+					sort.Strings(names)
+					if len(names) > 0 {
+						component.Name = names[0]
+					}
 				}
 			}
 
@@ -76,10 +96,44 @@ func (c *ReactToTemplConverter) Convert(reactCode string, options *config.Conver
 		}
 	}
 
+	// Определяем вид эффектов (таймеры, подписки) для всех генераторов
+	normalizeEffects(component)
+	markDOMEffects(component)
+
+	// Классы CSS модулей (styles.title) становятся литералами с уникальными именами,
+	// стилизованные компоненты (styled.h1) - HTML элементами с templ css компонентами
+	moduleStyles, styleWarnings := resolveCSSModules(component, options)
+	styledStyles, styledWarnings := resolveStyledComponents(component)
+	styleWarnings = append(styleWarnings, styledWarnings...)
+
+	// <div {...props}> получает атрибуты через rest-пропс attrs
+	addAttrsProp(component)
+
+	// Анализ потоков данных: классифицируем состояния (server, client, derived, readonly)
+	dataflow := analyzeDataflow(component)
+
+	// Разделяем состояния между сервером (HTMX) и клиентом (Alpine.js)
+	partitionState(component, options)
+
+	// Фрагменты: дочерние узлы выводятся на месте фрагмента
+	fragmentWarnings := normalizeFragments(component, options)
+
+	// Элементы форм: defaultValue, value у textarea и select
+	formWarnings := normalizeFormControls(component)
+
 	// Создаем конвертеры и генераторы с указанными опциями
 	c.jsxConverter = NewJSXToHTMXConverter(options)
 	c.stateHandler = NewStateHandler(options)
 
+	// Структура состояния объявляется в templ файле: ее принимает templ компонент
+	if stateAware, ok := c.templGenerator.(interface {
+		SetStateTypeGenerator(interface {
+			GenerateStateType(*models.ReactComponent) string
+		})
+	}); ok {
+		stateAware.SetStateTypeGenerator(c.stateHandler)
+	}
+
 	// Применяем настройки отступов и режима отладки
 	if c.debug {
 		c.jsxConverter.SetDebug(c.debug)
@@ -119,7 +173,7 @@ func (c *ReactToTemplConverter) Convert(reactCode string, options *config.Conver
 	var goController string
 	if c.goGenerator != nil {
 		goController = c.goGenerator.GenerateGoController(component)
-	} else if options.UseHtmx && (len(component.State) > 0 || len(component.Effects) > 0) {
+	} else if options.UseHtmx && (len(component.State) > 0 || len(component.Effects) > 0 || len(component.Reducers) > 0) {
 		// Генерация контроллеров для состояний, если они есть и используется HTMX
 		goController = c.stateHandler.GenerateStateHandlers(component)
 	}
@@ -134,21 +188,82 @@ func (c *ReactToTemplConverter) Convert(reactCode string, options *config.Conver
 	}
 
 	// Формирование результата
-	result := models.NewConversionResult(options.ComponentName, "")
+	result := models.NewConversionResult(options.ComponentName, options.SourcePath)
 	result.TemplFile = templCode
 	result.GoController = goController
 	result.HtmxJS = htmxJS
+	result.Dataflow = dataflow
+
+	// Таблица стилей CSS модулей и вложенных правил стилизованных компонентов
+	result.Stylesheet = moduleStyles + styledStyles
+	result.Warnings = append(result.Warnings, styleWarnings...)
+	result.Warnings = append(result.Warnings, fragmentWarnings...)
+	result.Warnings = append(result.Warnings, formWarnings...)
+
+	// Манифест классов разметки: Tailwind не видит классы, собираемые при рендеринге
+	result.Classes = collectClasses(component, options)
+	if options.TailwindConfig {
+		result.TailwindConfig = models.TailwindConfigSnippet(result.Classes)
+	}
+
+	// Сообщаем о пользовательских хуках, которые не удалось встроить
+	for _, hook := range component.Hooks {
+		if hook.Resolved {
+			continue
+		}
+		warning := fmt.Sprintf("Не удалось разрешить пользовательский хук %s", hook.Name)
+		if hook.Source != "" {
+			warning += fmt.Sprintf(" (импорт из %s)", hook.Source)
+		}
+		result.Warnings = append(result.Warnings, warning)
+	}
+
+	// Сообщаем о разметке, выводимой без экранирования
+	if component.JSX != nil {
+		result.Warnings = append(result.Warnings, escapingWarnings(component.JSX)...)
+	}
+
+	// Сообщаем об атрибутах {...expr}, которые не передаются дочерним компонентам
+	result.Warnings = append(result.Warnings, spreadWarnings(component.JSX, options)...)
+
+	// Сообщаем об эффектах, перевод которых нужно доработать вручную
+	result.Warnings = append(result.Warnings, effectWarnings(component)...)
+
+	// Сообщаем о выражениях Alpine.js с подставленными серверными значениями
+	result.Warnings = append(result.Warnings, alpineWarnings(component, component.JSX)...)
+
+	// Сообщаем о контекстах из модулей, которые не конвертируются
+	result.Warnings = append(result.Warnings, contextWarnings(component, options)...)
+
+	// Сообщаем о действиях редьюсеров, которые не удалось перевести
+	result.Warnings = append(result.Warnings, c.stateHandler.reducerWarnings(component)...)
+	result.Warnings = append(result.Warnings, dispatchWarnings(component, component.JSX)...)
+
+	// Сообщаем об обертках компонента (forwardRef, observer, HOC)
+	result.Warnings = append(result.Warnings, wrapperWarnings(component)...)
 
 	// Сохраняем настройки конвертации в результате
 	result.Settings = map[string]interface{}{
 		"useHtmx":          options.UseHtmx,
 		"packageName":      options.PackageName,
 		"statePersistence": options.StatePersistence,
+		"interactivity":    options.InteractivityMode(),
 	}
 
 	return result, nil
 }
 
+// parse разбирает React компонент. Если известен путь к файлу и парсер его поддерживает,
+// передаем путь для разрешения хуков из локальных модулей
+func (c *ReactToTemplConverter) parse(reactCode string, sourcePath string) (*models.ReactComponent, error) {
+	if fileParser, ok := c.parser.(interface {
+		ParseComponentFile(code string, filePath string) (*models.ReactComponent, error)
+	}); ok && sourcePath != "" {
+		return fileParser.ParseComponentFile(reactCode, sourcePath)
+	}
+	return c.parser.ParseComponent(reactCode)
+}
+
 // generateBasicTempl создает простой templ шаблон, если генератор не установлен
 func (c *ReactToTemplConverter) generateBasicTempl(component *models.ReactComponent, options *config.ConversionOptions) string {
 	// Базовая реализация для генерации templ шаблона без использования внешнего генератора
@@ -176,49 +291,46 @@ func (c *ReactToTemplConverter) generateBasicTempl(component *models.ReactCompon
 		sb.WriteString(fmt.Sprintf("// %sProps определяет пропсы для компонента\n", component.Name))
 		sb.WriteString(fmt.Sprintf("type %sProps struct {\n", component.Name))
 		for _, prop := range component.Props {
-			sb.WriteString(fmt.Sprintf("\t%s %s\n", strings.Title(prop.Name), c.convertTypeToGo(prop.Type)))
+			// children передаются через механизм дочерних элементов templ
+			if prop.Name == "children" {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("\t%s %s\n", strings.Title(prop.Name), models.PropFieldType(prop)))
 		}
 		sb.WriteString("}\n\n")
+		sb.WriteString(models.PropsConstructor(component, "\t"))
 	}
 
-	// Определение templ компонента
-	funcName := strings.ToLower(string(component.Name[0])) + component.Name[1:]
+	// Определение templ компонента. Имя экспортируется: контроллеры вызывают компонент
+	// из своего пакета
+	funcName := exportedName(component.Name)
 
-	params := ""
+	var params, args []string
 	if len(component.Props) > 0 {
-		params = fmt.Sprintf("props %sProps", component.Name)
+		params = append(params, fmt.Sprintf("props %sProps", component.Name))
+		args = append(args, "props")
 	}
 
 	if options.UseHtmx {
-		if params != "" {
-			params += ", "
-		}
-		params += "id string"
+		params = append(params, "id string")
+		args = append(args, "id")
 	}
 
-	// Параметр для хранения состояния
-	if len(component.State) > 0 {
-		if params != "" {
-			params += ", "
-		}
-		for _, state := range component.State {
-			if state.Type == "number" {
-				params += fmt.Sprintf("%s int", state.Name)
-				break
-			} else if state.Type == "string" {
-				params += fmt.Sprintf("%s string", state.Name)
-				break
-			} else {
-				params += fmt.Sprintf("%s interface{}", state.Name)
-				break
-			}
-		}
+	// Состояние компонента передается структурой
+	if len(component.State) > 0 || len(component.Reducers) > 0 {
+		sb.WriteString(c.stateHandler.GenerateStateType(component))
+		params = append(params, fmt.Sprintf("state %sState", component.Name))
+		args = append(args, "state")
 	}
 
-	sb.WriteString(fmt.Sprintf("templ %s(%s) {\n", funcName, params))
+	// Фрагментам, перерисовываемым отдельно, нужны те же параметры
+	c.jsxConverter.SetTemplSignature(strings.Join(params, ", "), strings.Join(args, ", "))
+
+	sb.WriteString(fmt.Sprintf("templ %s(%s) {\n", funcName, strings.Join(params, ", ")))
 
 	// Если есть JSX, конвертируем его
 	if component.JSX != nil {
+		c.jsxConverter.SetComponent(component)
 		jsxTemplate := c.jsxConverter.ConvertJSXToTempl(component.JSX, 1)
 		sb.WriteString(jsxTemplate)
 	} else if len(component.State) > 0 {
@@ -227,7 +339,7 @@ func (c *ReactToTemplConverter) generateBasicTempl(component *models.ReactCompon
 
 		// Внешний div с ID для HTMX (если используется)
 		if options.UseHtmx {
-			sb.WriteString(fmt.Sprintf("%s<div id=\"%s-{id}\">\n", indent, component.Name))
+			sb.WriteString(fmt.Sprintf("%s<div id={ \"%s-\" + id }>\n", indent, component.Name))
 		} else {
 			sb.WriteString(fmt.Sprintf("%s<div>\n", indent))
 		}
@@ -237,12 +349,10 @@ func (c *ReactToTemplConverter) generateBasicTempl(component *models.ReactCompon
 				// Заголовок счетчика
 				sb.WriteString(fmt.Sprintf("%s%s<h2>Счетчик: ", indent, indent))
 
-				if state.Type == "number" {
-					sb.WriteString("{ strconv.Itoa(count) }</h2>\n")
-				} else if state.Type == "string" {
-					sb.WriteString("{ count }</h2>\n")
+				if state.Type == "string" {
+					sb.WriteString("{ state.Count }</h2>\n")
 				} else {
-					sb.WriteString("{ fmt.Sprint(count) }</h2>\n")
+					sb.WriteString("{ fmt.Sprint(state.Count) }</h2>\n")
 				}
 
 				// Кнопки
@@ -250,8 +360,8 @@ func (c *ReactToTemplConverter) generateBasicTempl(component *models.ReactCompon
 
 				// Кнопка уменьшения (-)
 				if options.UseHtmx {
-					sb.WriteString(fmt.Sprintf("%s%s%s<button hx-post=\"/api/%s/setCount?id={id}&value=-1\" "+
-						"hx-target=\"#%s-{id}\" hx-swap=\"outerHTML\">-</button>\n",
+					sb.WriteString(fmt.Sprintf("%s%s%s<button hx-post={ \"/api/%s/setCount?value=-1&id=\" + id } "+
+						"hx-target={ \"#%s-\" + id } hx-swap=\"outerHTML\">-</button>\n",
 						indent, indent, indent, strings.ToLower(component.Name), component.Name))
 				} else {
 					sb.WriteString(fmt.Sprintf("%s%s%s<button>-</button>\n", indent, indent, indent))
@@ -259,8 +369,8 @@ func (c *ReactToTemplConverter) generateBasicTempl(component *models.ReactCompon
 
 				// Кнопка увеличения (+)
 				if options.UseHtmx {
-					sb.WriteString(fmt.Sprintf("%s%s%s<button hx-post=\"/api/%s/setCount?id={id}&value=1\" "+
-						"hx-target=\"#%s-{id}\" hx-swap=\"outerHTML\">+</button>\n",
+					sb.WriteString(fmt.Sprintf("%s%s%s<button hx-post={ \"/api/%s/setCount?value=1&id=\" + id } "+
+						"hx-target={ \"#%s-\" + id } hx-swap=\"outerHTML\">+</button>\n",
 						indent, indent, indent, strings.ToLower(component.Name), component.Name))
 				} else {
 					sb.WriteString(fmt.Sprintf("%s%s%s<button>+</button>\n", indent, indent, indent))
@@ -272,12 +382,11 @@ func (c *ReactToTemplConverter) generateBasicTempl(component *models.ReactCompon
 				sb.WriteString(fmt.Sprintf("%s%s<h3>%s: ", indent, indent, state.Name))
 
 				// Отображаем значение в зависимости от типа
-				if state.Type == "number" {
-					sb.WriteString(fmt.Sprintf("{ strconv.Itoa(%s) }</h3>\n", state.Name))
-				} else if state.Type == "string" {
-					sb.WriteString(fmt.Sprintf("{ %s }</h3>\n", state.Name))
+				fieldName := strings.ToUpper(state.Name[:1]) + state.Name[1:]
+				if state.Type == "string" {
+					sb.WriteString(fmt.Sprintf("{ state.%s }</h3>\n", fieldName))
 				} else {
-					sb.WriteString(fmt.Sprintf("{ fmt.Sprint(%s) }</h3>\n", state.Name))
+					sb.WriteString(fmt.Sprintf("{ fmt.Sprint(state.%s) }</h3>\n", fieldName))
 				}
 			}
 		}
@@ -291,21 +400,19 @@ func (c *ReactToTemplConverter) generateBasicTempl(component *models.ReactCompon
 
 	sb.WriteString("}\n")
 
+	// Фрагменты, вынесенные в отдельные templ компоненты
+	if regions := c.jsxConverter.RegionTemplates(); regions != "" {
+		sb.WriteString("\n" + regions)
+	}
+
 	return sb.String()
 }
 
 // convertTypeToGo преобразует тип TypeScript в Go
 func (c *ReactToTemplConverter) convertTypeToGo(tsType string) string {
-	switch tsType {
-	case "string":
-		return "string"
-	case "number":
-		return "int"
-	case "boolean":
-		return "bool"
-	default:
-		return "interface{}"
-	}
+	// Разметка в пропсе (header: ReactNode) и render props передаются templ компонентами,
+	// колбэки - URL действий родителя
+	return models.PropGoType(tsType)
 }
 
 // SetParser устанавливает парсер для конвертера
@@ -0,0 +1,188 @@
+package converter_test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/converter"
+	"react-to-templ-converter/internal/generator"
+	"react-to-templ-converter/internal/models"
+)
+
+// jsonParser разбирает компоненты из JSON (формат вывода парсера): фикстуры и файлы
+// тестовых проектов хранят уже разобранный компонент
+type jsonParser struct{}
+
+func (jsonParser) ParseComponent(code string) (*models.ReactComponent, error) {
+	var component models.ReactComponent
+	err := json.Unmarshal([]byte(code), &component)
+	return &component, err
+}
+
+func (p jsonParser) ParseComponentFile(code string, filePath string) (*models.ReactComponent, error) {
+	return p.ParseComponent(code)
+}
+
+func (jsonParser) StartParser() error { return nil }
+func (jsonParser) StopParser()        {}
+
+// newTestConverter создает конвертер с генераторами, как это делает сервер
+func newTestConverter(options *config.ConversionOptions) *converter.ReactToTemplConverter {
+	templGenerator := generator.NewTemplGenerator(options)
+	templGenerator.SetJSXConverter(converter.NewJSXToHTMXConverter(options))
+	goGenerator := generator.NewGoGenerator(options)
+	goGenerator.SetStateHandler(converter.NewStateHandler(options))
+
+	return converter.NewConverter(jsonParser{},
+		converter.WithTemplGenerator(templGenerator),
+		converter.WithGoGenerator(goGenerator)).(*converter.ReactToTemplConverter)
+}
+
+// convertFixture конвертирует фикстуру, как это делает сервер. Имя компонента в опциях
+// сервер берет из имени файла, здесь - из самой фикстуры
+func convertFixture(t *testing.T, path string, interactivity string) *models.ConversionResult {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ошибка чтения фикстуры: %v", err)
+	}
+	var component models.ReactComponent
+	if err := json.Unmarshal(data, &component); err != nil {
+		t.Fatalf("ошибка разбора фикстуры: %v", err)
+	}
+
+	options := config.NewDefaultOptions()
+	options.Interactivity = interactivity
+	options.ComponentName = component.Name
+
+	result, err := newTestConverter(options).Convert(string(data), options)
+	if err != nil {
+		t.Fatalf("ошибка конвертации: %v", err)
+	}
+	return result
+}
+
+// TestConvertIsDeterministic проверяет, что одинаковый вход дает побайтово одинаковый
+// результат: порядок атрибутов, пропсов и импортов не зависит от обхода map
+func TestConvertIsDeterministic(t *testing.T) {
+	for _, interactivity := range []string{config.InteractivityHtmx, config.InteractivityHybrid} {
+		first := convertFixture(t, "testdata/ordering.json", interactivity)
+		firstDataflow, _ := json.Marshal(first.Dataflow)
+
+		// Порядок обхода map случаен: одно совпадение двух запусков ничего не доказывает
+		for run := 0; run < 20; run++ {
+			next := convertFixture(t, "testdata/ordering.json", interactivity)
+			nextDataflow, _ := json.Marshal(next.Dataflow)
+
+			if next.TemplFile != first.TemplFile {
+				t.Fatalf("%s: templ файл отличается при повторной конвертации:\n%s\n---\n%s", interactivity, first.TemplFile, next.TemplFile)
+			}
+			if next.GoController != first.GoController {
+				t.Fatalf("%s: Go контроллер отличается при повторной конвертации:\n%s\n---\n%s", interactivity, first.GoController, next.GoController)
+			}
+			if next.HtmxJS != first.HtmxJS {
+				t.Fatalf("%s: JavaScript отличается при повторной конвертации", interactivity)
+			}
+			if string(nextDataflow) != string(firstDataflow) {
+				t.Fatalf("%s: граф потоков данных отличается при повторной конвертации", interactivity)
+			}
+			if next.ComponentName != first.ComponentName {
+				t.Fatalf("%s: имя компонента отличается: %s и %s", interactivity, first.ComponentName, next.ComponentName)
+			}
+		}
+	}
+}
+
+// TestConvertKeepsSourceAttributeOrder проверяет, что атрибуты выводятся в порядке исходного кода
+func TestConvertKeepsSourceAttributeOrder(t *testing.T) {
+	result := convertFixture(t, "testdata/ordering.json", config.InteractivityHtmx)
+
+	assertOrder(t, result.TemplFile, `role="toolbar"`, `class="toolbar"`, `data-kind="main"`, `aria-label="Панель"`)
+	assertOrder(t, result.TemplFile, `title="Заголовок"`, `class="title"`, `id="toolbar-title"`)
+	assertOrder(t, result.TemplFile, `type="text"`, `placeholder="Поиск"`, `autocomplete="off"`)
+	assertOrder(t, result.TemplFile, `Tone: "info"`, `Size: 2`, `Label: "Новое"`, `Active: true`, `DataKind: "badge"`)
+}
+
+// TestConvertReportsUntranslatedCode проверяет, что код, который не удалось перевести,
+// попадает в предупреждения результата, а не только в комментарии сгенерированного кода
+func TestConvertReportsUntranslatedCode(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		warnings []string
+	}{
+		{"testdata/golden/reducer/counter.json", []string{
+			`Действие "weird" редьюсера reducer не переведено`,
+			`Payload действия "add" (e.detail) не передается в CounterDispatch`,
+		}},
+		{"testdata/golden/context/imported.json", []string{"Контекст ThemeContext создается в другом модуле"}},
+		{"testdata/golden/jsx/styles.json", []string{"{...rest} не передается в Button: компонент не найден среди файлов проекта"}},
+	}
+
+	for _, tt := range tests {
+		result := convertFixture(t, tt.fixture, config.InteractivityHtmx)
+		for _, warning := range tt.warnings {
+			if !containsWarning(result.Warnings, warning) {
+				t.Errorf("%s: нет предупреждения %q среди %q", tt.fixture, warning, result.Warnings)
+			}
+		}
+	}
+}
+
+// TestConvertClassifiesStateByDataflow проверяет, что клиентскими становятся состояния,
+// которые читают и меняют только обработчики событий и HTML разметка, а состояния,
+// переданные дочерним компонентам или серверным состояниям, остаются серверными
+func TestConvertClassifiesStateByDataflow(t *testing.T) {
+	result := convertFixture(t, "testdata/classes.json", config.InteractivityHybrid)
+
+	expected := map[string]string{
+		"query": models.StateKindClient,
+		"count": models.StateKindClient,
+		"draft": models.StateKindServer, // читает обработчик, меняющий серверное title
+		"title": models.StateKindServer, // пропс компонента Card
+		"tab":   models.StateKindServer, // пропс компонента Tabs
+	}
+	for name, kind := range expected {
+		if result.Dataflow.Classes[name] != kind {
+			t.Errorf("класс состояния %s: %q, ожидался %q", name, result.Dataflow.Classes[name], kind)
+		}
+	}
+}
+
+// TestConvertWarnsAboutMixedAlpineExpressions проверяет, что выражение Alpine.js, читающее
+// клиентское состояние и серверное значение, попадает в предупреждения гибридного режима
+func TestConvertWarnsAboutMixedAlpineExpressions(t *testing.T) {
+	result := convertFixture(t, "testdata/golden/state/client_state.hybrid.json", config.InteractivityHybrid)
+
+	warning := "Выражение open && count > 0 ? 'Hide' : 'Show' читает клиентские состояния и серверные значения"
+	if !containsWarning(result.Warnings, warning) {
+		t.Errorf("нет предупреждения %q среди %q", warning, result.Warnings)
+	}
+}
+
+// containsWarning проверяет, есть ли среди предупреждений начинающееся с prefix
+func containsWarning(warnings []string, prefix string) bool {
+	for _, warning := range warnings {
+		if strings.HasPrefix(warning, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// assertOrder проверяет, что фрагменты встречаются в тексте в заданном порядке
+func assertOrder(t *testing.T, text string, fragments ...string) {
+	t.Helper()
+
+	position := 0
+	for _, fragment := range fragments {
+		index := strings.Index(text[position:], fragment)
+		if index < 0 {
+			t.Fatalf("фрагмент %q не найден после позиции %d в:\n%s", fragment, position, text)
+		}
+		position += index + len(fragment)
+	}
+}
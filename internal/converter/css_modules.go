@@ -0,0 +1,237 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// Класс в селекторе: .title
+	cssClassSelectorRegex = regexp.MustCompile(`\.(-?[_a-zA-Z][\w-]*)`)
+	// Классы, которые CSS модуль не переименовывает: :global(.title)
+	cssGlobalRegex = regexp.MustCompile(`:global\(([^)]*)\)`)
+	// Явно локальные классы: :local(.title)
+	cssLocalRegex = regexp.MustCompile(`:local\(([^)]*)\)`)
+	// CSS модуль: ./Card.module.css, ./Card.module.scss
+	cssModuleSourceRegex = regexp.MustCompile(`\.module\.(css|scss|sass|less)$`)
+	// Селектор правила из одного класса, в котором допустим composes
+	singleClassSelectorRegex = regexp.MustCompile(`^\.(-?[_a-zA-Z][\w-]*)$`)
+	// Строковый литерал класса, подставленный в шаблонную строку: `${'Card_title__1a2b3'} big`
+	inlinedClassRegex = regexp.MustCompile(`\$\{\s*'([^'\\]*)'\s*\}`)
+)
+
+// cssModule - CSS модуль, импортированный компонентом: import styles from './Card.module.css'
+type cssModule struct {
+	variable string
+	source   string
+	path     string // Путь файла модуля для хеша имен классов (cssModulePath)
+	// Имена классов модуля -> итоговые классы (с учетом composes)
+	classes map[string][]string
+}
+
+// isCSSModule проверяет, является ли импорт CSS модулем
+func isCSSModule(source string) bool {
+	return cssModuleSourceRegex.MatchString(source)
+}
+
+// resolveCSSModules заменяет в выражениях JSX обращения к классам CSS модулей
+// (styles.title, styles['title-x']) строками с уникальными именами классов, которые
+// CSS модуль выдал бы при сборке, и возвращает таблицу стилей модулей с этими именами
+func resolveCSSModules(component *models.ReactComponent, options *config.ConversionOptions) (string, []string) {
+	var modules []*cssModule
+	var stylesheet strings.Builder
+	var warnings []string
+
+	for _, imp := range component.Imports {
+		if imp.Defaults == "" || !isCSSModule(imp.Source) {
+			continue
+		}
+		module := &cssModule{variable: imp.Defaults, source: imp.Source}
+		modules = append(modules, module)
+
+		if !strings.HasSuffix(imp.Source, ".module.css") {
+			warnings = append(warnings, fmt.Sprintf("CSS модуль %s не поддерживается: нужен скомпилированный .module.css, классы выводятся без переименования", imp.Source))
+			continue
+		}
+		if options.SourcePath == "" {
+			warnings = append(warnings, fmt.Sprintf("CSS модуль %s не прочитан: путь исходного файла неизвестен, классы выводятся без переименования", imp.Source))
+			continue
+		}
+		cssPath := filepath.Join(filepath.Dir(options.SourcePath), filepath.FromSlash(imp.Source))
+		css, err := os.ReadFile(cssPath)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("CSS модуль %s не прочитан: %v, классы выводятся без переименования", imp.Source, err))
+			continue
+		}
+		module.path = cssModulePath(cssPath, options)
+
+		module.classes = make(map[string][]string)
+		statements := module.scope(parseCSS(string(css)))
+		stylesheet.WriteString(fmt.Sprintf("/* %s */\n", imp.Source))
+		writeCSS(&stylesheet, statements, 0)
+	}
+
+	if len(modules) > 0 && component.JSX != nil {
+		missing := make(map[string]bool)
+		rewriter := jsxRewriter{code: func(code string) string {
+			for _, module := range modules {
+				code = module.replaceReferences(code, missing)
+			}
+			return code
+		}}
+		rewriter.rewrite(component.JSX)
+
+		var names []string
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			source, local, _ := strings.Cut(name, "\x00")
+			warnings = append(warnings, fmt.Sprintf("Класс %s не найден в CSS модуле %s", local, source))
+		}
+	}
+
+	return stylesheet.String(), warnings
+}
+
+// scopedClassName возвращает уникальное имя класса в формате css-loader: Card_title__1a2b3.
+// source - путь CSS модуля (cssModulePath) или компонент со стилизованными компонентами. Хеш
+// зависит от пути и класса, поэтому имена совпадают во всех компонентах, импортирующих модуль,
+// а одноименные модули разных каталогов не пересекаются
+func scopedClassName(source string, local string) string {
+	base := strings.TrimSuffix(path.Base(source), ".module.css")
+	sum := sha256.Sum256([]byte(source + ":" + local))
+	return fmt.Sprintf("%s_%s__%s", base, local, hex.EncodeToString(sum[:])[:5])
+}
+
+// cssModulePath возвращает путь CSS модуля относительно корня проекта, как context
+// в css-loader. Вне проекта путь берется относительно каталога компонента
+func cssModulePath(cssPath string, options *config.ConversionOptions) string {
+	root := options.ProjectRoot
+	if root == "" {
+		root = filepath.Dir(options.SourcePath)
+	}
+	if rel, err := filepath.Rel(root, cssPath); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(cssPath)
+}
+
+// scope переименовывает классы в селекторах правил модуля и собирает таблицу классов.
+// Объявления composes: base; добавляют классы к классу правила и из таблицы стилей удаляются
+func (m *cssModule) scope(statements []cssStatement) []cssStatement {
+	var scoped []cssStatement
+	for _, statement := range statements {
+		if statement.prelude == "" {
+			scoped = append(scoped, statement)
+			continue
+		}
+
+		prelude := statement.prelude
+		if !strings.HasPrefix(prelude, "@") {
+			prelude = m.scopeSelector(prelude)
+		}
+		rules := m.scope(statement.rules)
+
+		// .title { composes: base; } - только для правила с одним классом
+		var kept []cssStatement
+		for _, rule := range rules {
+			name, value, _ := splitDeclaration(rule.declaration)
+			if rule.prelude != "" || name != "composes" {
+				kept = append(kept, rule)
+				continue
+			}
+			if match := singleClassSelectorRegex.FindStringSubmatch(statement.prelude); match != nil && !strings.Contains(value, " from ") {
+				for _, composed := range strings.Fields(value) {
+					if classes, ok := m.classes[composed]; ok {
+						m.classes[match[1]] = append(m.classes[match[1]], classes...)
+					} else {
+						m.classes[match[1]] = append(m.classes[match[1]], scopedClassName(m.path, composed))
+					}
+				}
+			}
+		}
+		scoped = append(scoped, cssStatement{prelude: prelude, rules: kept})
+	}
+	return scoped
+}
+
+// scopeSelector переименовывает классы селектора, кроме обернутых в :global()
+func (m *cssModule) scopeSelector(selector string) string {
+	var sb strings.Builder
+	last := 0
+	for _, match := range cssGlobalRegex.FindAllStringSubmatchIndex(selector, -1) {
+		sb.WriteString(m.scopeClasses(selector[last:match[0]]))
+		sb.WriteString(selector[match[2]:match[3]])
+		last = match[1]
+	}
+	sb.WriteString(m.scopeClasses(selector[last:]))
+	return sb.String()
+}
+
+// scopeClasses переименовывает классы в части селектора и добавляет их в таблицу классов
+func (m *cssModule) scopeClasses(selector string) string {
+	selector = cssLocalRegex.ReplaceAllString(selector, "$1")
+	return replaceOutsideStrings(selector, func(code string) string {
+		return cssClassSelectorRegex.ReplaceAllStringFunc(code, func(class string) string {
+			local := class[1:]
+			if _, ok := m.classes[local]; !ok {
+				m.classes[local] = []string{scopedClassName(m.path, local)}
+			}
+			return "." + scopedClassName(m.path, local)
+		})
+	})
+}
+
+// className возвращает итоговые классы для имени класса модуля. Если модуль не прочитан,
+// имя выводится как есть
+func (m *cssModule) className(local string) []string {
+	if m.classes == nil {
+		return []string{local}
+	}
+	return m.classes[local]
+}
+
+// replaceReferences заменяет обращения к классам модуля в выражении строковыми литералами.
+// Строки, подставленные в шаблонную строку, встраиваются в ее текст.
+// Обращения к классам, которых нет в модуле, попадают в missing
+func (m *cssModule) replaceReferences(code string, missing map[string]bool) string {
+	variable := regexp.QuoteMeta(m.variable)
+	literal := func(local string) string {
+		classes := m.className(local)
+		if len(classes) == 0 {
+			missing[m.source+"\x00"+local] = true
+		}
+		return "'" + strings.Join(classes, " ") + "'"
+	}
+
+	// styles['title-x']
+	bracketRegex := regexp.MustCompile(`(^|[^\w.$])` + variable + `\[\s*['"]([^'"]+)['"]\s*\]`)
+	code = bracketRegex.ReplaceAllStringFunc(code, func(match string) string {
+		parts := bracketRegex.FindStringSubmatch(match)
+		return parts[1] + literal(parts[2])
+	})
+
+	// styles.title
+	dotRegex := regexp.MustCompile(`(^|[^\w.$])` + variable + `\.([A-Za-z_$][\w$]*)`)
+	code = replaceOutsideStrings(code, func(segment string) string {
+		return dotRegex.ReplaceAllStringFunc(segment, func(match string) string {
+			parts := dotRegex.FindStringSubmatch(match)
+			return parts[1] + literal(parts[2])
+		})
+	})
+
+	return inlinedClassRegex.ReplaceAllStringFunc(code, func(match string) string {
+		return inlinedClassRegex.FindStringSubmatch(match)[1]
+	})
+}
@@ -0,0 +1,113 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Комментарии CSS
+var cssCommentRegex = regexp.MustCompile(`/\*[\s\S]*?\*/`)
+
+// cssStatement - объявление свойства (declaration) или правило с селектором
+// либо at-правилом (prelude) и вложенными инструкциями (rules)
+type cssStatement struct {
+	declaration string
+	prelude     string
+	rules       []cssStatement
+}
+
+// parseCSS разбирает таблицу стилей на объявления и правила верхнего уровня.
+// Вложенные правила (@media, &:hover) разбираются рекурсивно
+func parseCSS(css string) []cssStatement {
+	return parseCSSBlock(cssCommentRegex.ReplaceAllString(css, ""))
+}
+
+// parseCSSBlock разбирает содержимое блока без комментариев
+func parseCSSBlock(css string) []cssStatement {
+	var statements []cssStatement
+	start := 0
+	var quote byte
+
+	for i := 0; i < len(css); i++ {
+		ch := css[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == ';':
+			if declaration := strings.TrimSpace(css[start:i]); declaration != "" {
+				statements = append(statements, cssStatement{declaration: declaration})
+			}
+			start = i + 1
+		case ch == '{':
+			end := matchingBrace(css, i)
+			statements = append(statements, cssStatement{
+				prelude: strings.Join(strings.Fields(css[start:i]), " "),
+				rules:   parseCSSBlock(css[i+1 : end]),
+			})
+			i = end
+			start = end + 1
+		}
+	}
+
+	if declaration := strings.TrimSpace(css[min(start, len(css)):]); declaration != "" {
+		statements = append(statements, cssStatement{declaration: declaration})
+	}
+	return statements
+}
+
+// matchingBrace возвращает позицию фигурной скобки, закрывающей открытую в open
+// (или конец строки, если блок не закрыт)
+func matchingBrace(css string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(css); i++ {
+		ch := css[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '{':
+			depth++
+		case ch == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(css)
+}
+
+// splitDeclaration делит объявление на имя свойства и значение
+func splitDeclaration(declaration string) (string, string, bool) {
+	name, value, ok := strings.Cut(declaration, ":")
+	if !ok {
+		return "", "", false
+	}
+	return strings.TrimSpace(name), strings.TrimSpace(value), true
+}
+
+// writeCSS выводит инструкции таблицы стилей с отступом
+func writeCSS(sb *strings.Builder, statements []cssStatement, indent int) {
+	indentation := strings.Repeat("  ", indent)
+	for _, statement := range statements {
+		if statement.prelude == "" {
+			sb.WriteString(indentation + statement.declaration + ";\n")
+			continue
+		}
+		sb.WriteString(indentation + statement.prelude + " {\n")
+		writeCSS(sb, statement.rules, indent+1)
+		sb.WriteString(indentation + "}\n")
+	}
+}
@@ -0,0 +1,432 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strings"
+)
+
+// stateAssignmentRegex находит присваивание полю состояния в сгенерированном Go: state.Count = ...
+var stateAssignmentRegex = regexp.MustCompile(`^state\.(\w+) = `)
+
+// dataflowAnalyzer строит граф потоков данных компонента: какие узлы JSX читают
+// состояния, какие обработчики и колбэки их меняют и от чего зависят эффекты
+type dataflowAnalyzer struct {
+	component *models.ReactComponent
+	graph     *models.DataflowGraph
+	code      map[string]string // Код обработчиков, колбэков и эффектов по ID узла
+}
+
+// analyzeDataflow строит граф потоков данных и классифицирует состояния компонента
+// (поле Kind): server - нужно серверу, client - локальное состояние интерфейса,
+// derived - вычисляется из других состояний и не меняется из разметки, readonly - не меняется
+func analyzeDataflow(component *models.ReactComponent) *models.DataflowGraph {
+	a := &dataflowAnalyzer{
+		component: component,
+		graph:     models.NewDataflowGraph(),
+		code:      make(map[string]string),
+	}
+
+	for _, state := range component.State {
+		a.graph.AddNode(models.StateNodeID(state.Name), models.DataflowNodeState, state.Name)
+	}
+
+	if component.JSX != nil {
+		a.walkJSX(component.JSX, component.JSX.Type)
+	}
+
+	for _, callback := range component.Callbacks {
+		id := models.DataflowNodeCallback + ":" + callback.Name
+		a.graph.AddNode(id, models.DataflowNodeCallback, callback.Name)
+		a.code[id] = callback.Body
+		a.addCodeEdges(id, callback.Body)
+	}
+
+	for i, effect := range component.Effects {
+		id := fmt.Sprintf("%s:%d", models.DataflowNodeEffect, i+1)
+		a.graph.AddNode(id, models.DataflowNodeEffect, fmt.Sprintf("Effect%d", i+1))
+		a.code[id] = effect.Body
+		a.addCodeEdges(id, effect.Body)
+
+		for _, dependency := range effect.Dependencies {
+			if findState(component, dependency) != nil {
+				a.graph.AddEdge(models.StateNodeID(dependency), id, models.DataflowEdgeDepends)
+			}
+		}
+	}
+
+	for _, state := range component.State {
+		a.graph.Classes[state.Name] = a.classify(state)
+	}
+	a.demoteServerLinkedStates()
+	for i, state := range component.State {
+		component.State[i].Kind = a.graph.Classes[state.Name]
+	}
+
+	return a.graph
+}
+
+// walkJSX добавляет в граф чтения состояний в узлах JSX и обработчики событий.
+// ID узла - путь от корня: div/ul[1]/li[0]
+func (a *dataflowAnalyzer) walkJSX(jsx *models.JSXElement, path string) {
+	id := models.DataflowNodeJSX + ":" + path
+
+	for _, name := range jsx.PropNames() {
+		value := jsx.Props[name]
+		var code string
+		switch v := value.(type) {
+		case string:
+			// Содержимое и условие узла-выражения
+			if jsx.Type == "expression" && (name == "content" || name == "condition") {
+				code = v
+			}
+		case map[string]interface{}:
+			code, _ = v["code"].(string)
+		}
+		if code == "" {
+			continue
+		}
+
+		if isEventProp(name) {
+			handlerID := models.DataflowNodeHandler + ":" + path + "." + name
+			a.graph.AddNode(handlerID, models.DataflowNodeHandler, name)
+			a.code[handlerID] = resolveHandlerCode(code, a.component)
+			a.addCodeEdges(handlerID, code)
+
+			for _, callback := range a.component.Callbacks {
+				if regexp.MustCompile(`\b` + regexp.QuoteMeta(callback.Name) + `\b`).MatchString(code) {
+					a.graph.AddNode(models.DataflowNodeCallback+":"+callback.Name, models.DataflowNodeCallback, callback.Name)
+					a.graph.AddEdge(handlerID, models.DataflowNodeCallback+":"+callback.Name, models.DataflowEdgeCalls)
+				}
+			}
+			continue
+		}
+
+		for _, state := range a.component.State {
+			if readsState(code, state.Name) {
+				a.graph.AddNode(id, models.DataflowNodeJSX, jsx.Type)
+				a.graph.AddEdge(models.StateNodeID(state.Name), id, models.DataflowEdgeReads)
+			}
+		}
+	}
+
+	for i, child := range jsx.Children {
+		a.walkJSX(child, fmt.Sprintf("%s/%s[%d]", path, child.Type, i))
+	}
+
+	// Разметка, переданная в пропсы дочернего компонента: Card.header/h2
+	names, trees := slotTrees(jsx)
+	for i, tree := range trees {
+		a.walkJSX(tree, fmt.Sprintf("%s.%s/%s", path, names[i], tree.Type))
+	}
+}
+
+// addCodeEdges добавляет ребра чтения и записи состояний для кода узла
+func (a *dataflowAnalyzer) addCodeEdges(id string, code string) {
+	for _, state := range a.component.State {
+		if callsSetter(code, state.Setter) {
+			a.graph.AddEdge(id, models.StateNodeID(state.Name), models.DataflowEdgeWrites)
+		}
+		if readsState(code, state.Name) {
+			a.graph.AddEdge(models.StateNodeID(state.Name), id, models.DataflowEdgeReads)
+		}
+	}
+}
+
+// classify определяет класс состояния по графу
+func (a *dataflowAnalyzer) classify(state models.StateDefinition) string {
+	writers := a.writers(state.Name)
+	if len(writers) == 0 {
+		return models.StateKindReadOnly
+	}
+
+	// Сеттер вызывается только эффектами, пересчитывающими значение из других
+	// состояний: обработчик сеттера не нужен
+	derived := true
+	for _, writer := range writers {
+		if writer.Kind != models.DataflowNodeEffect || !a.isDerivingEffect(writer.ID) {
+			derived = false
+		}
+	}
+	if derived {
+		return models.StateKindDerived
+	}
+
+	if a.isClientOnly(state, writers) {
+		return models.StateKindClient
+	}
+
+	return models.StateKindServer
+}
+
+// writers возвращает узлы, меняющие состояние, с учетом обработчиков,
+// которые меняют его через колбэк
+func (a *dataflowAnalyzer) writers(name string) []models.DataflowNode {
+	writers := a.graph.Writers(name)
+	for _, edge := range a.graph.Edges {
+		if edge.Kind != models.DataflowEdgeCalls {
+			continue
+		}
+		for _, writer := range writers {
+			if writer.ID == edge.To {
+				writers = append(writers, models.DataflowNode{ID: edge.From, Kind: models.DataflowNodeHandler})
+				break
+			}
+		}
+	}
+	return writers
+}
+
+// isDerivingEffect проверяет, что эффект только пересчитывает состояния через сеттеры
+// (без таймеров, подписок и загрузки данных)
+func (a *dataflowAnalyzer) isDerivingEffect(id string) bool {
+	var index int
+	if _, err := fmt.Sscanf(id, models.DataflowNodeEffect+":%d", &index); err != nil || index < 1 || index > len(a.component.Effects) {
+		return false
+	}
+	effect := a.component.Effects[index-1]
+
+	return (effect.Kind == "" || effect.Kind == models.EffectKindGeneric) &&
+		!isEffectForDataFetching(effect) && isSetterOnlyCode(effect.Body, a.component)
+}
+
+// isClientOnly проверяет, что состояние не нужно серверу: его меняют только обработчики
+// событий из одних сеттеров, а читают только HTML разметка и такие же обработчики.
+// Эффекты, пропсы дочерних компонентов (они рендерятся сервером) и обработчики с другим
+// кодом (запросы к серверу) делают состояние серверным
+func (a *dataflowAnalyzer) isClientOnly(state models.StateDefinition, writers []models.DataflowNode) bool {
+	for _, writer := range writers {
+		if writer.Kind == models.DataflowNodeEffect || !isSetterOnlyCode(a.code[writer.ID], a.component) {
+			return false
+		}
+	}
+
+	if len(a.graph.Dependents(state.Name)) > 0 {
+		return false
+	}
+
+	for _, reader := range a.graph.Readers(state.Name) {
+		switch reader.Kind {
+		case models.DataflowNodeJSX:
+			if isComponentType(reader.Name) {
+				return false
+			}
+		case models.DataflowNodeEffect:
+			return false
+		default:
+			if !isSetterOnlyCode(a.code[reader.ID], a.component) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// demoteServerLinkedStates делает серверными клиентские состояния, связанные с серверными
+// через обработчики: обработчик, который читает клиентское состояние и меняет серверное,
+// передает значение серверу, а клиентское состояние, вычисляемое из серверного, сервер
+// должен знать при рендеринге. Проверка повторяется, пока классы меняются (цепочки)
+func (a *dataflowAnalyzer) demoteServerLinkedStates() {
+	for changed := true; changed; {
+		changed = false
+		for _, state := range a.component.State {
+			if a.graph.Classes[state.Name] != models.StateKindClient {
+				continue
+			}
+
+			linked := false
+			for _, reader := range a.graph.Readers(state.Name) {
+				for _, written := range a.writtenStates(reader.ID) {
+					linked = linked || a.graph.Classes[written] != models.StateKindClient
+				}
+			}
+			for _, writer := range a.writers(state.Name) {
+				for _, other := range a.component.State {
+					if a.graph.Classes[other.Name] != models.StateKindClient && readsState(a.code[writer.ID], other.Name) {
+						linked = true
+					}
+				}
+			}
+
+			if linked {
+				a.graph.Classes[state.Name] = models.StateKindServer
+				changed = true
+			}
+		}
+	}
+}
+
+// writtenStates возвращает имена состояний, которые меняет узел
+func (a *dataflowAnalyzer) writtenStates(id string) []string {
+	var names []string
+	for _, edge := range a.graph.Edges {
+		if edge.Kind == models.DataflowEdgeWrites && edge.From == id {
+			names = append(names, strings.TrimPrefix(edge.To, models.DataflowNodeState+":"))
+		}
+	}
+	return names
+}
+
+// isComponentType проверяет, что тип узла JSX - пользовательский компонент (Card, Tabs.Panel)
+func isComponentType(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
+
+// needsSetterEndpoint проверяет, нужен ли состоянию серверный обработчик сеттера.
+// Производным и неизменяемым состояниям он не нужен, клиентским - только если их
+// читает разметка (в режиме htmx перерисовка выполняется сервером)
+func needsSetterEndpoint(component *models.ReactComponent, state models.StateDefinition) bool {
+	return state.Kind != models.StateKindDerived && state.Kind != models.StateKindReadOnly &&
+		needsServerStorage(component, state)
+}
+
+// needsServerStorage проверяет, нужно ли хранить состояние на сервере. Клиентское
+// состояние, которое разметка не читает (isHovered без использования), не хранится
+func needsServerStorage(component *models.ReactComponent, state models.StateDefinition) bool {
+	return state.Kind != models.StateKindClient || jsxReadsState(component.JSX, state.Name)
+}
+
+// jsxReadsState проверяет, читается ли состояние в разметке (без обработчиков событий)
+func jsxReadsState(jsx *models.JSXElement, name string) bool {
+	if jsx == nil {
+		return false
+	}
+
+	for _, prop := range jsx.PropNames() {
+		value := jsx.Props[prop]
+		switch v := value.(type) {
+		case string:
+			if jsx.Type == "expression" && readsState(v, name) {
+				return true
+			}
+		case map[string]interface{}:
+			if code, ok := v["code"].(string); ok && !isEventProp(prop) && readsState(code, name) {
+				return true
+			}
+		}
+	}
+
+	for _, child := range jsx.Children {
+		if jsxReadsState(child, name) {
+			return true
+		}
+	}
+
+	_, trees := slotTrees(jsx)
+	for _, tree := range trees {
+		if jsxReadsState(tree, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isNeedlessHandler проверяет, что обработчик события только вызывает сеттеры состояний,
+// которым не нужен серверный обработчик: такой атрибут в разметку не попадает
+func (c *JSXToHTMXConverter) isNeedlessHandler(name string, value interface{}) bool {
+	if c.component == nil || !isEventProp(name) {
+		return false
+	}
+
+	valueExpr, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	code, ok := valueExpr["code"].(string)
+	if !ok {
+		return false
+	}
+
+	code = resolveHandlerCode(code, c.component)
+	if !isSetterOnlyCode(code, c.component) {
+		return false
+	}
+
+	for _, match := range setterCallRegex(c.component).FindAllStringSubmatch(code, -1) {
+		state := findStateBySetter(c.component, match[1])
+		if state == nil || needsSetterEndpoint(c.component, *state) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isDerivedStateEffect проверяет, что эффект пересчитывает серверные состояния из других
+// состояний (useEffect(() => setFull(first + '!'), [first])) и переводится в Go: такой
+// эффект применяется в обработчиках, меняющих его зависимости, а не в браузере
+func isDerivedStateEffect(component *models.ReactComponent, effect models.EffectDefinition) bool {
+	return (effect.Kind == "" || effect.Kind == models.EffectKindGeneric) && len(effect.Dependencies) > 0 &&
+		!isEffectForDataFetching(effect) && isSetterOnlyCode(effect.Body, component) &&
+		len(translateSetterCalls(effect.Body, component)) > 0
+}
+
+// withDerivedUpdates дополняет присваивания состояниям (state.First = newValue) пересчетом
+// производных состояний: эффекты, зависящие от измененных состояний, применяются следом,
+// в том числе цепочкой (first -> full -> title)
+func withDerivedUpdates(component *models.ReactComponent, statements []string) []string {
+	changed := make(map[string]bool)
+	markChanged := func(statements []string) {
+		for _, statement := range statements {
+			if match := stateAssignmentRegex.FindStringSubmatch(statement); match != nil {
+				for _, state := range component.State {
+					if exportedName(state.Name) == match[1] {
+						changed[state.Name] = true
+					}
+				}
+			}
+		}
+	}
+	markChanged(statements)
+
+	applied := make(map[int]bool)
+	for progress := true; progress; {
+		progress = false
+		for i, effect := range component.Effects {
+			if applied[i] || !isDerivedStateEffect(component, effect) {
+				continue
+			}
+			for _, dependency := range effect.Dependencies {
+				if !changed[dependency] {
+					continue
+				}
+				updates := translateSetterCalls(effect.Body, component)
+				statements = append(statements, updates...)
+				markChanged(updates)
+				applied[i] = true
+				progress = true
+				break
+			}
+		}
+	}
+
+	return statements
+}
+
+// findState ищет состояние компонента по имени
+func findState(component *models.ReactComponent, name string) *models.StateDefinition {
+	for i := range component.State {
+		if component.State[i].Name == name {
+			return &component.State[i]
+		}
+	}
+	return nil
+}
+
+// isEventProp проверяет, является ли пропс обработчиком события (onClick)
+func isEventProp(name string) bool {
+	return strings.HasPrefix(name, "on") && len(name) > 2 && name[2] >= 'A' && name[2] <= 'Z'
+}
+
+// readsState проверяет, читает ли код состояние (не как поле другого объекта)
+func readsState(code string, name string) bool {
+	return regexp.MustCompile(`(^|[^\w.$])` + regexp.QuoteMeta(name) + `\b`).MatchString(code)
+}
+
+// callsSetter проверяет, вызывает ли код сеттер состояния
+func callsSetter(code string, setter string) bool {
+	return setter != "" && regexp.MustCompile(`\b`+regexp.QuoteMeta(setter)+`\(`).MatchString(code)
+}
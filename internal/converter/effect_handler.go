@@ -0,0 +1,376 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	timerCallRegex      = regexp.MustCompile(`(?:window\.)?(setInterval|setTimeout)\(\s*([\s\S]*?),\s*(\d+)\s*\)`)
+	subscriptionRegex   = regexp.MustCompile(`new\s+(?:EventSource|WebSocket)\(\s*(?:['"\x60]([^'"\x60]*)['"\x60]|([^)]*))\s*\)`)
+	cleanupReturnRegex  = regexp.MustCompile(`return\s*(?:\(\s*\)|function\s*\(\s*\))\s*(?:=>)?\s*(\{[\s\S]*\}|[^;\n]+)\s*;?\s*\}\s*$`)
+	setterUpdaterRegex  = regexp.MustCompile(`^\(?\s*(\w+)\s*\)?\s*=>\s*([\s\S]+)$`)
+	simpleGoExprRegex   = regexp.MustCompile(`^[\w\s+\-*/%!<>=&|().'"]+$`)
+	identifierRegex     = regexp.MustCompile(`"[^"]*"|\b[A-Za-z_]\w*\b`)
+	stringLiteralsRegex = regexp.MustCompile(`'([^'\\]*)'`)
+	messageHandlerRegex = regexp.MustCompile(`\.(?:onmessage\s*=|addEventListener\(\s*['"]message['"]\s*,)\s*(?:\(?\s*(\w+)\s*\)?\s*=>|function\s*\(\s*(\w+)\s*\))\s*`)
+)
+
+// Преобразования данных сообщения подписки перед записью в состояние
+const (
+	messageRaw    = "raw"    // e.data, String(e.data)
+	messageNumber = "number" // Number(e.data), parseFloat(e.data), parseInt(e.data)
+	messageJSON   = "json"   // JSON.parse(e.data)
+)
+
+// messageUpdate описывает запись данных сообщения подписки в состояние:
+// es.onmessage = e => setCount(Number(e.data))
+type messageUpdate struct {
+	State      models.StateDefinition
+	Conversion string
+	Statement  string // Готовое присваивание Go, если сеттер не читает данные (setCount(c => c + 1))
+}
+
+var (
+	// noMatchRegex - шаблон, который ничего не находит
+	noMatchRegex = regexp.MustCompile(`[^\s\S]`)
+	// setterRegexCache - скомпилированные шаблоны вызовов сеттеров по тексту шаблона
+	setterRegexCache sync.Map
+)
+
+// setterCallRegex возвращает шаблон вызовов сеттеров состояний компонента: setCount(count + 1).
+// Шаблон строится по известным сеттерам, чтобы setTimeout и setInterval не считались сеттерами
+func setterCallRegex(component *models.ReactComponent) *regexp.Regexp {
+	var setters []string
+	for _, states := range [][]models.StateDefinition{component.State, component.ClientState} {
+		for _, state := range states {
+			if state.Setter != "" {
+				setters = append(setters, regexp.QuoteMeta(state.Setter))
+			}
+		}
+	}
+	if len(setters) == 0 {
+		// Компонент без сеттеров: шаблон, который ничего не находит
+		return noMatchRegex
+	}
+
+	// Шаблон нужен при обходе каждого узла разметки: скомпилированные шаблоны кешируются
+	pattern := `\b(` + strings.Join(setters, "|") + `)\(([^;\n]*)\)`
+	if cached, ok := setterRegexCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	compiled, _ := setterRegexCache.LoadOrStore(pattern, regexp.MustCompile(pattern))
+	return compiled.(*regexp.Regexp)
+}
+
+// normalizeEffects дополняет эффекты компонента информацией о виде эффекта.
+// Парсер заполняет эти поля сам, но для JSON, полученного другим путем,
+// вид эффекта определяется эвристически по его телу.
+func normalizeEffects(component *models.ReactComponent) {
+	for i, effect := range component.Effects {
+		component.Effects[i] = analyzeEffect(effect)
+	}
+}
+
+// analyzeEffect определяет вид эффекта (таймер, подписка, обычный) и его функцию очистки
+func analyzeEffect(effect models.EffectDefinition) models.EffectDefinition {
+	if effect.Kind != "" {
+		return effect
+	}
+
+	effect.Kind = models.EffectKindGeneric
+
+	if match := timerCallRegex.FindStringSubmatch(effect.Body); match != nil {
+		if match[1] == "setInterval" {
+			effect.Kind = models.EffectKindInterval
+		} else {
+			effect.Kind = models.EffectKindTimeout
+		}
+		effect.Callback = strings.TrimSpace(match[2])
+		effect.Delay, _ = strconv.Atoi(match[3])
+	} else if match := subscriptionRegex.FindStringSubmatch(effect.Body); match != nil {
+		effect.Kind = models.EffectKindSubscription
+		effect.Source = match[1]
+		if effect.Source == "" {
+			effect.Source = strings.TrimSpace(match[2])
+		}
+	}
+
+	if effect.Cleanup == "" {
+		if match := cleanupReturnRegex.FindStringSubmatch(effect.Body); match != nil {
+			effect.Cleanup = strings.TrimSpace(match[1])
+		}
+	}
+
+	return effect
+}
+
+// needsEffectHandler проверяет, нужен ли эффекту серверный обработчик: его вызывает
+// разметка (таймеры через hx-trigger, подписки через sse-connect). Остальные эффекты
+// выполняются в браузере
+func needsEffectHandler(effect models.EffectDefinition) bool {
+	switch effect.Kind {
+	case models.EffectKindInterval, models.EffectKindTimeout, models.EffectKindSubscription:
+		return true
+	}
+	return false
+}
+
+// isSubscriptionURL проверяет, что источник подписки - адрес, к которому сервер может
+// подключиться сам: EventSource с литералом пути или http(s) URL
+func isSubscriptionURL(effect models.EffectDefinition) bool {
+	if strings.Contains(effect.Body, "WebSocket") {
+		return false
+	}
+	return strings.HasPrefix(effect.Source, "/") || strings.HasPrefix(effect.Source, "http://") || strings.HasPrefix(effect.Source, "https://")
+}
+
+// subscriptionUpdates переводит обработчик сообщений подписки в записи состояний.
+// Возвращает false, если обработчик не найден или содержит вызовы, которые не удалось перевести
+func subscriptionUpdates(effect models.EffectDefinition, component *models.ReactComponent) ([]messageUpdate, bool) {
+	match := messageHandlerRegex.FindStringSubmatchIndex(effect.Body)
+	if match == nil {
+		return nil, false
+	}
+	var param string
+	if match[2] >= 0 {
+		param = effect.Body[match[2]:match[3]]
+	} else {
+		param = effect.Body[match[4]:match[5]]
+	}
+	code := handlerStatement(effect.Body[match[1]:])
+
+	data := regexp.QuoteMeta(param) + `\.data`
+	conversions := []struct {
+		regex      *regexp.Regexp
+		conversion string
+	}{
+		{regexp.MustCompile(`^(?:String\(\s*` + data + `\s*\)|` + data + `)$`), messageRaw},
+		{regexp.MustCompile(`^(?:(?:Number|parseFloat)\(\s*` + data + `\s*\)|parseInt\(\s*` + data + `\s*(?:,\s*10\s*)?\)|\+\s*` + data + `)$`), messageNumber},
+		{regexp.MustCompile(`^JSON\.parse\(\s*` + data + `\s*\)$`), messageJSON},
+	}
+
+	var updates []messageUpdate
+	for _, call := range setterCallRegex(component).FindAllStringSubmatch(code, -1) {
+		state := findStateBySetter(component, call[1])
+		if state == nil {
+			return nil, false
+		}
+
+		arg := strings.TrimSpace(call[2])
+		update := messageUpdate{State: *state}
+		for _, candidate := range conversions {
+			if candidate.regex.MatchString(arg) {
+				update.Conversion = candidate.conversion
+				break
+			}
+		}
+		if update.Conversion == "" {
+			statements := translateSetterCalls(call[0], component)
+			if len(statements) != 1 {
+				return nil, false
+			}
+			update.Statement = statements[0]
+		}
+		updates = append(updates, update)
+	}
+
+	return updates, len(updates) > 0
+}
+
+// handlerStatement возвращает тело обработчика, записанного после стрелки:
+// блок в фигурных скобках или выражение до конца инструкции
+func handlerStatement(code string) string {
+	if strings.HasPrefix(code, "{") {
+		depth := 0
+		for i, r := range code {
+			switch r {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					return code[:i+1]
+				}
+			}
+		}
+		return code
+	}
+	if end := strings.IndexAny(code, ";\n"); end >= 0 {
+		return code[:end]
+	}
+	return code
+}
+
+// effectWarnings сообщает об эффектах, перевод которых требует ручной доработки:
+// подписках, к источнику которых сервер не подключается сам, эффектах в браузере,
+// которые читают серверные состояния и пропсы и поэтому не переносятся, и функциях
+// очистки эффектов, которые выполняет сервер без обработчика (загрузка данных, пересчет состояний)
+func effectWarnings(component *models.ReactComponent) []string {
+	var warnings []string
+
+	for i, effect := range component.Effects {
+		switch {
+		case effect.Kind == models.EffectKindSubscription && !isSubscriptionURL(effect):
+			warnings = append(warnings, fmt.Sprintf("Источник подписки эффекта %d (%s) не является адресом EventSource: подключите его к обработчику %s вручную", i+1, effect.Source, effectHandlerName(component, i)))
+		case effect.Kind == models.EffectKindSubscription:
+			if _, ok := subscriptionUpdates(effect, component); !ok {
+				warnings = append(warnings, fmt.Sprintf("Обработчик сообщений подписки эффекта %d не переведен: %s перерисовывает компонент на каждое сообщение без изменения состояния", i+1, effectHandlerName(component, i)))
+			}
+		case isClientEffect(component, effect):
+			if names := serverValuesRead(effect.Body, component); len(names) > 0 {
+				warnings = append(warnings, fmt.Sprintf("Эффект %d не перенесен в браузер: он читает %s, а серверные состояния и пропсы недоступны в JavaScript. Перенесите его вручную, передав значения через data-атрибуты", i+1, strings.Join(names, ", ")))
+			}
+		case effect.Cleanup != "" && !needsEffectHandler(effect):
+			warnings = append(warnings, fmt.Sprintf("Функция очистки эффекта %d не перенесена: %s", i+1, singleLine(effect.Cleanup)))
+		}
+	}
+
+	return warnings
+}
+
+// serverValuesRead возвращает имена серверных состояний и пропсов, которые читает код
+func serverValuesRead(code string, component *models.ReactComponent) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, ident := range identifierRegex.FindAllString(code, -1) {
+		if seen[ident] {
+			continue
+		}
+		for _, state := range component.State {
+			if state.Name == ident {
+				names = append(names, ident)
+				seen[ident] = true
+			}
+		}
+		for _, prop := range component.Props {
+			if prop.Name == ident && !seen[ident] {
+				names = append(names, ident)
+				seen[ident] = true
+			}
+		}
+	}
+	return names
+}
+
+// formatHtmxDuration преобразует задержку в миллисекундах в формат времени HTMX
+func formatHtmxDuration(delay int) string {
+	if delay <= 0 {
+		return "1s"
+	}
+	if delay%1000 == 0 {
+		return fmt.Sprintf("%ds", delay/1000)
+	}
+	return fmt.Sprintf("%dms", delay)
+}
+
+// effectTrigger возвращает значение hx-trigger для эффекта-таймера
+func effectTrigger(effect models.EffectDefinition) string {
+	switch effect.Kind {
+	case models.EffectKindInterval:
+		return "every " + formatHtmxDuration(effect.Delay)
+	case models.EffectKindTimeout:
+		return "load delay:" + formatHtmxDuration(effect.Delay)
+	default:
+		return "load"
+	}
+}
+
+// effectRoutePath возвращает путь маршрута эффекта (нумерация с единицы, как у обработчиков EffectN)
+func effectRoutePath(component *models.ReactComponent, index int) string {
+	return fmt.Sprintf("/api/%s/effect/%d", strings.ToLower(component.Name), index+1)
+}
+
+// translateSetterCalls переводит простые вызовы сеттеров (setCount(c => c + 1),
+// setOpen(false)) в присваивания Go. Возвращает nil, если код не удалось перевести.
+func translateSetterCalls(code string, component *models.ReactComponent) []string {
+	var statements []string
+
+	for _, match := range setterCallRegex(component).FindAllStringSubmatch(code, -1) {
+		state := findStateBySetter(component, match[1])
+		if state == nil {
+			return nil
+		}
+
+		fieldName := strings.ToUpper(string(state.Name[0])) + state.Name[1:]
+		expr := strings.TrimSpace(match[2])
+
+		// Функциональное обновление: setCount(prev => prev + 1)
+		if updater := setterUpdaterRegex.FindStringSubmatch(expr); updater != nil {
+			expr = regexp.MustCompile(`\b`+regexp.QuoteMeta(updater[1])+`\b`).ReplaceAllString(updater[2], state.Name)
+		}
+
+		goExpr, ok := translateStateExpression(expr, *state, component)
+		if !ok {
+			return nil
+		}
+
+		statements = append(statements, fmt.Sprintf("state.%s = %s", fieldName, goExpr))
+	}
+
+	return statements
+}
+
+// translateStateExpression переводит простое JS выражение над состояниями в Go.
+// null и undefined становятся нулевым значением типа состояния target, которому
+// присваивается выражение: поля float64 и bool не принимают nil
+func translateStateExpression(expr string, target models.StateDefinition, component *models.ReactComponent) (string, bool) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" || !simpleGoExprRegex.MatchString(expr) {
+		return "", false
+	}
+
+	expr = strings.ReplaceAll(expr, "===", "==")
+	expr = strings.ReplaceAll(expr, "!==", "!=")
+	expr = stringLiteralsRegex.ReplaceAllString(expr, `"$1"`)
+
+	known := true
+	expr = identifierRegex.ReplaceAllStringFunc(expr, func(ident string) string {
+		// Строковые литералы оставляем без изменений
+		if strings.HasPrefix(ident, "\"") {
+			return ident
+		}
+		switch ident {
+		case "true", "false":
+			return ident
+		case "null", "undefined":
+			return zeroValue(valueGoType(target.Type, target.InitialValue))
+		}
+		for _, state := range component.State {
+			if state.Name == ident {
+				return "state." + strings.ToUpper(string(ident[0])) + ident[1:]
+			}
+		}
+		known = false
+		return ident
+	})
+
+	return expr, known
+}
+
+// zeroValue возвращает нулевое значение типа Go
+func zeroValue(goType string) string {
+	switch goType {
+	case "string":
+		return `""`
+	case "int", "float64":
+		return "0"
+	case "bool":
+		return "false"
+	}
+	return "nil"
+}
+
+// findStateBySetter ищет состояние по имени его сеттера
+func findStateBySetter(component *models.ReactComponent, setter string) *models.StateDefinition {
+	for i := range component.State {
+		if component.State[i].Setter == setter {
+			return &component.State[i]
+		}
+	}
+	return nil
+}
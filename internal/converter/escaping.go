@@ -0,0 +1,393 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Контексты, в которые выводятся значения атрибутов и текста. Для каждого контекста
+// свои правила экранирования
+const (
+	contextAttribute = "attribute"
+	contextText      = "text"
+	contextURL       = "url"
+	contextScript    = "script"
+	contextStyle     = "style"
+)
+
+var (
+	// dangerouslySetInnerHTML={{ __html: value }}
+	innerHTMLRegex = regexp.MustCompile(`^\{\s*__html\s*:\s*([\s\S]*?)\s*,?\s*\}$`)
+	// Строка, которую templ разберет как оператор или вызов компонента
+	templStatementRegex = regexp.MustCompile(`^(if|for|switch|else|case|default)\b|^@`)
+	// Схемы URL, исполняющие код
+	unsafeURLRegex = regexp.MustCompile(`(?i)^\s*(javascript|vbscript|data):`)
+	// Поле состояния или пропса: state.Count, props.Title
+	fieldReferenceRegex = regexp.MustCompile(`^(state|props)\.(\w+)$`)
+)
+
+// Атрибуты, значение которых - URL
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true, "poster": true,
+	"cite": true, "background": true, "ping": true, "xlink:href": true,
+}
+
+// Булевы атрибуты HTML: выводятся без значения, если выражение истинно
+var booleanAttributes = map[string]bool{
+	"disabled": true, "checked": true, "readonly": true, "required": true, "selected": true,
+	"hidden": true, "multiple": true, "autofocus": true, "open": true, "novalidate": true,
+	"autoplay": true, "controls": true, "loop": true, "muted": true, "default": true,
+	"defer": true, "async": true, "inert": true, "formnovalidate": true, "allowfullscreen": true,
+}
+
+// attributeContext возвращает контекст значения атрибута
+func attributeContext(attrName string) string {
+	switch {
+	case urlAttributes[attrName]:
+		return contextURL
+	case attrName == "style":
+		return contextStyle
+	case strings.HasPrefix(attrName, "on") && !strings.Contains(attrName, "-"):
+		// Обработчик, записанный строкой: onclick="..."
+		return contextScript
+	default:
+		return contextAttribute
+	}
+}
+
+// isRawTextElement проверяет, выводит ли templ содержимое элемента без разбора (script, style)
+func isRawTextElement(tag string) bool {
+	return tag == "script" || tag == "style"
+}
+
+// constantAttribute выводит атрибут с постоянным значением. templ экранирует значение
+// в кавычках сам, но не умеет кавычку внутри него: такое значение передается строкой Go.
+// URL со схемой javascript:/data: проходит через templ.URL и заменяется безопасной заглушкой
+func constantAttribute(tag string, attrName string, value string) string {
+	switch attributeContext(attrName) {
+	case contextURL:
+		if unsafeURLRegex.MatchString(value) {
+			return fmt.Sprintf(" %s={ %s }", attrName, urlExpression(tag, attrName, strconv.Quote(value)))
+		}
+	case contextScript:
+		// Код обработчика в атрибуте templ ожидает как templ.ComponentScript
+		return fmt.Sprintf(" %s={ templ.JSUnsafeFuncCall(%s) }", attrName, strconv.Quote(value))
+	}
+
+	if strings.Contains(value, `"`) {
+		if tag == "a" && attrName == "href" || tag == "form" && attrName == "action" {
+			// Значение из исходного кода компонента - доверенный URL
+			return fmt.Sprintf(" %s={ templ.SafeURL(%s) }", attrName, strconv.Quote(value))
+		}
+		return fmt.Sprintf(" %s={ %s }", attrName, strconv.Quote(value))
+	}
+	return fmt.Sprintf(" %s=\"%s\"", attrName, value)
+}
+
+// expressionAttribute выводит атрибут со значением-выражением с учетом контекста:
+// литералы - как постоянные значения, булевы атрибуты - через ?=, URL - через templ.URL,
+// остальные значения приводятся к строке (templ экранирует их сам)
+func (c *JSXToHTMXConverter) expressionAttribute(tag string, attrName string, code string) string {
+	if literal, ok := models.ParseJSLiteral(code); ok {
+		if attr, ok := literalAttribute(tag, attrName, literal); ok {
+			return attr
+		}
+	}
+
+	goExpr := c.convertReactExpressionToGo(code)
+
+	switch {
+	case booleanAttributes[attrName]:
+		return fmt.Sprintf(" %s?={ %s }", attrName, goExpr)
+	case attributeContext(attrName) == contextURL:
+		return fmt.Sprintf(" %s={ %s }", attrName, urlExpression(tag, attrName, c.stringExpression(goExpr)))
+	case attributeContext(attrName) == contextStyle:
+		// templ проверяет значение style сам (SanitizeStyleAttributeValues)
+		return fmt.Sprintf(" %s={ %s }", attrName, c.stringExpression(goExpr))
+	default:
+		return fmt.Sprintf(" %s={ %s }", attrName, c.stringExpression(goExpr))
+	}
+}
+
+// literalAttribute выводит атрибут со значением-литералом: null и false не выводятся,
+// true - атрибут без значения. ok = false для массивов и объектов
+func literalAttribute(tag string, attrName string, literal interface{}) (string, bool) {
+	switch v := literal.(type) {
+	case nil:
+		return "", true
+	case bool:
+		if v {
+			return " " + attrName, true
+		}
+		return "", true
+	case string:
+		return constantAttribute(tag, attrName, v), true
+	case float64:
+		return constantAttribute(tag, attrName, strconv.FormatFloat(v, 'f', -1, 64)), true
+	}
+	return "", false
+}
+
+// urlExpression проводит URL через templ.URL: небезопасные схемы заменяются заглушкой.
+// Для a href и form action templ ожидает templ.SafeURL, для остальных атрибутов - строку
+func urlExpression(tag string, attrName string, goExpr string) string {
+	if tag == "a" && attrName == "href" || tag == "form" && attrName == "action" {
+		return fmt.Sprintf("templ.URL(%s)", goExpr)
+	}
+	return fmt.Sprintf("string(templ.URL(%s))", goExpr)
+}
+
+// jsonAttribute выводит атрибут с JSON значением (hx-vals). JSON содержит двойные
+// кавычки, поэтому значение берется в одинарные, а если в нем есть и они - передается строкой Go
+func jsonAttribute(attrName string, json string) string {
+	if strings.Contains(json, "'") {
+		return fmt.Sprintf(" %s={ %s }", attrName, strconv.Quote(json))
+	}
+	return fmt.Sprintf(" %s='%s'", attrName, json)
+}
+
+// textNode выводит текст JSX. Текст с символами разметки templ ({, }, <) или похожий
+// на оператор templ передается строкой Go: templ экранирует ее при выводе.
+// Содержимое script и style templ выводит как есть
+func textNode(text string, parentTag string, indentation string) string {
+	if isRawTextElement(parentTag) {
+		return indentation + escapeRawText(text, parentTag) + "\n"
+	}
+	if strings.ContainsAny(text, "{}<>&") || templStatementRegex.MatchString(text) {
+		return indentation + "{ " + strconv.Quote(text) + " }\n"
+	}
+	return indentation + text + "\n"
+}
+
+// escapeRawText не дает тексту внутри script или style закрыть элемент раньше времени
+func escapeRawText(text string, tag string) string {
+	return regexp.MustCompile(`(?i)</(`+tag+`)`).ReplaceAllString(text, `<\/$1`)
+}
+
+// stringExpression приводит выражение Go к строке для вывода в текст или атрибут:
+// templ принимает только строки. Строковые поля состояния и пропсов и строковые
+// литералы выводятся как есть, остальное - через fmt.Sprint
+func (c *JSXToHTMXConverter) stringExpression(goExpr string) string {
+	goExpr = strings.TrimSpace(goExpr)
+	if c.isStringExpression(goExpr) {
+		return goExpr
+	}
+	return fmt.Sprintf("fmt.Sprint(%s)", goExpr)
+}
+
+// isStringExpression проверяет, что выражение Go имеет тип string
+func (c *JSXToHTMXConverter) isStringExpression(goExpr string) bool {
+	if strings.HasPrefix(goExpr, "fmt.Sprint") || strings.HasPrefix(goExpr, "string(") {
+		return true
+	}
+	if literal, err := strconv.Unquote(goExpr); err == nil && literal != goExpr {
+		return true
+	}
+	return c.fieldGoType(goExpr) == "string"
+}
+
+// fieldGoType возвращает тип Go поля пропса или состояния (props.Title, state.Count):
+// объявленный или выведенный из начального значения. Для других выражений - пустая строка
+func (c *JSXToHTMXConverter) fieldGoType(goExpr string) string {
+	match := fieldReferenceRegex.FindStringSubmatch(strings.TrimSpace(goExpr))
+	if match == nil || c.component == nil {
+		return ""
+	}
+
+	if match[1] == "props" {
+		for _, prop := range c.component.Props {
+			if exportedName(prop.Name) == match[2] {
+				return models.PropFieldType(prop)
+			}
+		}
+		return ""
+	}
+
+	for _, state := range c.component.State {
+		if exportedName(state.Name) == match[2] {
+			return valueGoType(state.Type, state.InitialValue)
+		}
+	}
+	for _, reducer := range c.component.Reducers {
+		for _, field := range reducer.Fields {
+			if exportedName(field.Name) == match[2] {
+				return valueGoType(field.Type, field.InitialValue)
+			}
+		}
+	}
+	return ""
+}
+
+// valueGoType возвращает тип Go поля состояния по объявленному типу TypeScript или по
+// начальному значению (числа состояния хранятся как float64, см. StateHandler)
+func valueGoType(tsType string, initialValue interface{}) string {
+	if tsType == "number" {
+		return "float64"
+	}
+	if tsType != "" {
+		return models.PropGoType(tsType)
+	}
+	switch initialValue.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "float64"
+	case bool:
+		return "bool"
+	}
+	return ""
+}
+
+// innerHTMLExpression возвращает выражение Go для dangerouslySetInnerHTML={{ __html: value }}
+func (c *JSXToHTMXConverter) innerHTMLExpression(value interface{}) (string, bool) {
+	valueExpr, ok := value.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	code, _ := valueExpr["code"].(string)
+	match := innerHTMLRegex.FindStringSubmatch(strings.TrimSpace(code))
+	if match == nil {
+		return "", false
+	}
+
+	if literal, ok := models.ParseJSLiteral(match[1]); ok {
+		if s, isString := literal.(string); isString {
+			return strconv.Quote(s), true
+		}
+	}
+	return c.stringExpression(c.convertReactExprToGoExpr(match[1])), true
+}
+
+// convertInnerHTML выводит разметку из dangerouslySetInnerHTML через templ.Raw - без
+// экранирования, как и в React. Для script и style элемент целиком собирается строкой:
+// templ не разбирает их содержимое
+func (c *JSXToHTMXConverter) convertInnerHTML(jsx *models.JSXElement, goExpr string, attrs string, indentation string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s// dangerouslySetInnerHTML: HTML выводится без экранирования\n", indentation))
+
+	if isRawTextElement(jsx.Type) {
+		sb.WriteString(fmt.Sprintf("%s@templ.Raw(%s + %s + %s)\n", indentation,
+			strconv.Quote("<"+jsx.Type+staticAttributes(jsx)+">"), goExpr, strconv.Quote("</"+jsx.Type+">")))
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("%s<%s%s>\n", indentation, jsx.Type, attrs))
+	sb.WriteString(fmt.Sprintf("%s\t@templ.Raw(%s)\n", indentation, goExpr))
+	sb.WriteString(fmt.Sprintf("%s</%s>\n", indentation, jsx.Type))
+	return sb.String()
+}
+
+// staticAttributes собирает постоянные атрибуты элемента в HTML (для элементов,
+// которые выводятся строкой), экранируя значения
+func staticAttributes(jsx *models.JSXElement) string {
+	var sb strings.Builder
+	for _, name := range jsx.PropNames() {
+		attrName := models.HTMLAttributeName(name)
+		switch v := jsx.Props[name].(type) {
+		case bool:
+			if v {
+				sb.WriteString(" " + attrName)
+			}
+		case string:
+			sb.WriteString(fmt.Sprintf(" %s=\"%s\"", attrName, html.EscapeString(v)))
+		}
+	}
+	return sb.String()
+}
+
+// escapingWarnings возвращает предупреждения о разметке, которую нельзя вывести
+// с экранированием: dangerouslySetInnerHTML и выражения внутри script и style
+func escapingWarnings(jsx *models.JSXElement) []string {
+	if jsx == nil {
+		return nil
+	}
+
+	var warnings []string
+	if _, ok := jsx.Props["dangerouslySetInnerHTML"]; ok {
+		warnings = append(warnings, fmt.Sprintf("Элемент <%s> использует dangerouslySetInnerHTML: HTML выводится через templ.Raw без экранирования, проверьте источник данных", jsx.Type))
+	}
+	for _, child := range jsx.Children {
+		if content, dropped := droppedRawTextExpression(jsx, child); dropped {
+			warnings = append(warnings, fmt.Sprintf("Выражение {%s} внутри <%s> не выводится: %s", content, jsx.Type, rawTextHint(jsx.Type)))
+		}
+		warnings = append(warnings, escapingWarnings(child)...)
+	}
+	_, trees := slotTrees(jsx)
+	for _, tree := range trees {
+		warnings = append(warnings, escapingWarnings(tree)...)
+	}
+	return warnings
+}
+
+// droppedRawTextExpression возвращает код выражения внутри script или style, которое
+// не выводится: templ не разбирает содержимое этих элементов
+func droppedRawTextExpression(parent *models.JSXElement, child *models.JSXElement) (string, bool) {
+	if !isRawTextElement(parent.Type) || child.Type != "expression" || rawTextExpression(child) != "" {
+		return "", false
+	}
+	content, _ := child.Props["content"].(string)
+	return content, content != ""
+}
+
+// rawTextHint подсказывает, как вывести данные внутри script или style
+func rawTextHint(tag string) string {
+	if tag == "script" {
+		return "templ не разбирает содержимое элемента, передайте данные скрипту через @templ.JSONScript"
+	}
+	return "templ не разбирает содержимое элемента, задайте стиль атрибутом style или классом"
+}
+
+// droppedRawTextComments выводит перед script или style комментарий о каждом
+// невыведенном выражении, как и для разметки через templ.Raw
+func droppedRawTextComments(jsx *models.JSXElement, indentation string) string {
+	var sb strings.Builder
+	for _, child := range jsx.Children {
+		if content, dropped := droppedRawTextExpression(jsx, child); dropped {
+			sb.WriteString(fmt.Sprintf("%s// {%s} внутри <%s> не выводится: %s\n", indentation, content, jsx.Type, rawTextHint(jsx.Type)))
+		}
+	}
+	return sb.String()
+}
+
+// rawTextExpression возвращает значение выражения внутри script или style, если это
+// строковый литерал (<style>{`.a { color: red }`}</style>), иначе пустую строку
+func rawTextExpression(jsx *models.JSXElement) string {
+	content, _ := jsx.Props["content"].(string)
+	if literal, ok := models.ParseJSLiteral(content); ok {
+		if s, isString := literal.(string); isString {
+			return s
+		}
+	}
+	return ""
+}
+
+// setterValueAttribute передает аргумент вызова сеттера (setCount(count + 1)) в hx-vals:
+// литерал - постоянным JSON, выражение - через templ.JSONString со значением на момент
+// рендеринга. Функциональные обновления и значения из события не передаются
+func (c *JSXToHTMXConverter) setterValueAttribute(code string, setter string) string {
+	match := regexp.MustCompile(`\b` + regexp.QuoteMeta(setter) + `\(([\s\S]*)\)`).FindStringSubmatch(code)
+	if match == nil {
+		return ""
+	}
+	arg := strings.TrimSpace(callArgument(match[1]))
+	if arg == "" || strings.Contains(arg, "=>") || c.component == nil ||
+		regexp.MustCompile(`^\w+$`).MatchString(arg) && findState(c.component, arg) == nil {
+		return ""
+	}
+
+	goExpr := c.convertReactExprToGoExpr(arg)
+	if literal, ok := models.ParseJSLiteral(arg); ok {
+		switch literal.(type) {
+		case string, float64, bool, nil:
+			data, _ := json.Marshal(map[string]interface{}{"value": literal})
+			return jsonAttribute("hx-vals", string(data))
+		}
+		goExpr = models.GoLiteral(literal, "")
+	}
+	return fmt.Sprintf(" hx-vals={ templ.JSONString(map[string]interface{}{\"value\": %s}) }", goExpr)
+}
@@ -0,0 +1,210 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"strings"
+)
+
+// normalizeFormControls переводит атрибуты элементов форм React в разметку HTML:
+// defaultValue и defaultChecked - в value и checked, value у <textarea> - в содержимое,
+// value у <select> - в атрибут selected варианта (<option>) с тем же значением.
+// Значения, зависящие от клиентских состояний, остаются атрибутами: их связывает Alpine.js.
+// Возвращает предупреждения о том, что перенести нельзя
+func normalizeFormControls(component *models.ReactComponent) []string {
+	var warnings []string
+	var normalize func(jsx *models.JSXElement)
+	normalize = func(jsx *models.JSXElement) {
+		if jsx == nil {
+			return
+		}
+		if jsx.Props != nil {
+			renameProp(jsx, "defaultValue", "value")
+			renameProp(jsx, "defaultChecked", "checked")
+
+			switch jsx.Type {
+			case "textarea":
+				textareaContent(component, jsx)
+			case "select":
+				if warning := selectOptions(component, jsx); warning != "" {
+					warnings = append(warnings, warning)
+				}
+			}
+		}
+		for _, child := range jsx.Children {
+			normalize(child)
+		}
+	}
+	normalize(component.JSX)
+	return warnings
+}
+
+// renameProp заменяет неуправляемый атрибут (defaultValue) управляемым (value) на том же
+// месте в порядке атрибутов. Если заданы оба, остается управляемый
+func renameProp(jsx *models.JSXElement, from string, to string) {
+	value, ok := jsx.Props[from]
+	if !ok {
+		return
+	}
+	delete(jsx.Props, from)
+	if _, controlled := jsx.Props[to]; controlled {
+		return
+	}
+	jsx.Props[to] = value
+	for i, name := range jsx.PropOrder {
+		if name == from {
+			jsx.PropOrder[i] = to
+		}
+	}
+}
+
+// textareaContent выводит value у <textarea> содержимым элемента: у textarea нет атрибута value
+func textareaContent(component *models.ReactComponent, jsx *models.JSXElement) {
+	value, ok := jsx.Props["value"]
+	if !ok {
+		return
+	}
+
+	var content *models.JSXElement
+	switch v := value.(type) {
+	case string:
+		content = &models.JSXElement{Type: "text", Props: map[string]interface{}{"content": v}}
+	case map[string]interface{}:
+		code, _ := v["code"].(string)
+		if code == "" || referencesClientState(component, code) {
+			// Alpine.js связывает :value со свойством value элемента
+			return
+		}
+		content = &models.JSXElement{Type: "expression", Props: map[string]interface{}{"content": code}}
+	default:
+		return
+	}
+
+	delete(jsx.Props, "value")
+	jsx.Children = []*models.JSXElement{content}
+}
+
+// selectOptions переносит value у <select> в атрибут selected вариантов: каждый <option>
+// получает выражение сравнения своего значения со значением select
+func selectOptions(component *models.ReactComponent, jsx *models.JSXElement) string {
+	value, ok := jsx.Props["value"]
+	if !ok {
+		return ""
+	}
+
+	code := ""
+	switch v := value.(type) {
+	case string:
+		code = quoteJSString(v)
+	case map[string]interface{}:
+		code, _ = v["code"].(string)
+	}
+	if code == "" || referencesClientState(component, code) {
+		// Alpine.js выбирает варианты по :value у select сам
+		return ""
+	}
+	delete(jsx.Props, "value")
+
+	// <select multiple> выбирает варианты, значения которых есть в массиве
+	_, multiple := jsx.Props["multiple"]
+	if multiple && !isStringArrayState(component, code) {
+		return fmt.Sprintf("Выбранные значения <select multiple value={%s}> переносятся только из состояния - массива строк: отметьте варианты атрибутом selected", code)
+	}
+
+	for _, option := range findOptions(jsx) {
+		optionCode := optionValueCode(option)
+		if optionCode == "" {
+			continue
+		}
+		option.Props["selected"] = map[string]interface{}{"code": code, "option": optionCode, "multiple": multiple}
+		option.PropOrder = append(option.PropOrder, "selected")
+	}
+	return ""
+}
+
+// isStringArrayState проверяет, что выражение - состояние с массивом строк (string[])
+func isStringArrayState(component *models.ReactComponent, code string) bool {
+	state := findState(component, strings.TrimSpace(code))
+	if state == nil {
+		return false
+	}
+	switch state.Type {
+	case "string[]", "Array<string>":
+		return true
+	case "":
+		items, ok := state.InitialValue.([]interface{})
+		for _, item := range items {
+			if _, isString := item.(string); !isString {
+				return false
+			}
+		}
+		return ok && len(items) > 0
+	}
+	return false
+}
+
+// isSelectOption проверяет, что элемент - вариант или группа вариантов select: они
+// перерисовываются вместе с select
+func isSelectOption(jsx *models.JSXElement) bool {
+	return jsx.Type == "option" || jsx.Type == "optgroup"
+}
+
+// findOptions возвращает варианты <option> внутри select, в том числе в <optgroup>
+// и в выражениях ({items.map(...)})
+func findOptions(jsx *models.JSXElement) []*models.JSXElement {
+	var options []*models.JSXElement
+	for _, child := range jsx.Children {
+		if child.Type == "option" {
+			if child.Props == nil {
+				child.Props = make(map[string]interface{})
+			}
+			options = append(options, child)
+			continue
+		}
+		options = append(options, findOptions(child)...)
+	}
+	return options
+}
+
+// optionValueCode возвращает код значения варианта: атрибут value или текст варианта
+func optionValueCode(option *models.JSXElement) string {
+	switch v := option.Props["value"].(type) {
+	case string:
+		return quoteJSString(v)
+	case map[string]interface{}:
+		code, _ := v["code"].(string)
+		return code
+	}
+
+	if len(option.Children) == 1 {
+		if text, ok := option.Children[0].Props["content"].(string); ok && option.Children[0].Type == "text" {
+			return quoteJSString(strings.TrimSpace(text))
+		}
+		if content, ok := option.Children[0].Props["content"].(string); ok && option.Children[0].Type == "expression" {
+			return content
+		}
+	}
+	return ""
+}
+
+// quoteJSString записывает строку литералом JavaScript в одинарных кавычках
+func quoteJSString(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// selectedAttribute выводит selected варианта управляемого select: значение select
+// и значение варианта сравниваются строками, поэтому числовое состояние совпадает
+// с value="2". Вариант select multiple выбран, если его значение есть в массиве
+func (c *JSXToHTMXConverter) selectedAttribute(value map[string]interface{}) string {
+	code, _ := value["code"].(string)
+	optionCode, _ := value["option"].(string)
+
+	option := c.stringExpression(c.convertReactExpressionToGo(optionCode))
+	if literal, ok := models.ParseJSLiteral(optionCode); ok && literal != nil {
+		option = models.GoLiteral(literal, "string")
+	}
+	if multiple, _ := value["multiple"].(bool); multiple {
+		return fmt.Sprintf(" selected?={ slices.Contains(%s, %s) }", c.convertReactExpressionToGo(code), option)
+	}
+	return fmt.Sprintf(" selected?={ %s == %s }", c.stringExpression(c.convertReactExpressionToGo(code)), option)
+}
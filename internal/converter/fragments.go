@@ -0,0 +1,54 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+)
+
+// normalizeFragments убирает фрагменты (<>...</>, <React.Fragment key={...}>) из дерева
+// разметки: их дочерние узлы встают на место фрагмента, в том числе внутри условий
+// {cond && <>...</>}, где каждый узел получает x-show или попадает в templ if.
+// Корневому элементу нужен id (HTMX) или x-data (Alpine.js), поэтому корневой фрагмент
+// с несколькими узлами заменяется на <div>. Возвращает предупреждения об этой замене
+func normalizeFragments(component *models.ReactComponent, options *config.ConversionOptions) []string {
+	if component.JSX == nil {
+		return nil
+	}
+
+	var flatten func(jsx *models.JSXElement)
+	flatten = func(jsx *models.JSXElement) {
+		jsx.Children = fragmentChildren(jsx.Children)
+		for _, child := range jsx.Children {
+			flatten(child)
+		}
+	}
+	flatten(component.JSX)
+
+	if !component.JSX.IsFragment() {
+		return nil
+	}
+	if len(component.JSX.Children) == 1 && isHTMLTag(component.JSX.Children[0].Type) {
+		component.JSX = component.JSX.Children[0]
+		return nil
+	}
+	if !options.UseHtmx && len(component.ClientState) == 0 {
+		return nil
+	}
+
+	component.JSX = &models.JSXElement{Type: "div", Props: map[string]interface{}{}, Children: component.JSX.Children}
+	return []string{fmt.Sprintf("Корневой фрагмент компонента %s содержит несколько узлов: они обернуты в <div>, который получает id и состояние компонента", component.Name)}
+}
+
+// fragmentChildren заменяет фрагменты в списке дочерних узлов их содержимым
+func fragmentChildren(children []*models.JSXElement) []*models.JSXElement {
+	flat := make([]*models.JSXElement, 0, len(children))
+	for _, child := range children {
+		if child != nil && child.IsFragment() {
+			flat = append(flat, fragmentChildren(child.Children)...)
+			continue
+		}
+		flat = append(flat, child)
+	}
+	return flat
+}
@@ -0,0 +1,274 @@
+package converter_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator"
+	parser "github.com/a-h/templ/parser/v2"
+
+	"react-to-templ-converter/internal/config"
+)
+
+var update = flag.Bool("update", false, "перезаписать эталонные файлы в testdata/golden результатом конвертации")
+
+// goldenOutputs сопоставляет расширение эталонного файла с частью результата конвертации
+var goldenOutputs = []struct {
+	extension string
+	name      string
+}{
+	{".templ.golden", "templ файл"},
+	{".go.golden", "Go контроллер"},
+	{".js.golden", "JavaScript"},
+}
+
+// TestConvertGolden конвертирует фикстуры testdata/golden/<подсистема>/<имя>.json и сравнивает
+// templ файл, Go контроллер и JavaScript с эталонами <имя>.templ.golden, <имя>.go.golden
+// и <имя>.js.golden. Режим интерактивности задает суффикс имени: .hybrid.json, .alpine.json,
+// по умолчанию htmx. Эталоны обновляются через go test ./internal/converter -update
+func TestConvertGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "golden", "*", "*.json"))
+	if err != nil {
+		t.Fatalf("ошибка поиска фикстур: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("фикстуры в testdata/golden не найдены")
+	}
+
+	for _, fixture := range fixtures {
+		base := strings.TrimSuffix(fixture, ".json")
+		name := filepath.Base(filepath.Dir(fixture)) + "/" + filepath.Base(base)
+
+		t.Run(name, func(t *testing.T) {
+			result := convertFixture(t, fixture, fixtureInteractivity(base))
+			outputs := []string{result.TemplFile, result.GoController, result.HtmxJS}
+
+			for i, output := range goldenOutputs {
+				assertGolden(t, base+output.extension, output.name, outputs[i])
+			}
+		})
+	}
+}
+
+// TestGoldenBuilds собирает эталоны templ и Go вместе: каждая фикстура получает пакеты
+// templates и controllers во временном модуле, templ файл генерируется в Go тем же
+// генератором templ, что и в сборке проекта, затем модуль проходит go build и go vet.
+// Импорт пакета шаблонов в контроллере указывает на пакет своей фикстуры
+func TestGoldenBuilds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("сборка эталонов пропускается с -short")
+	}
+
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "golden", "*", "*.json"))
+	if err != nil {
+		t.Fatalf("ошибка поиска фикстур: %v", err)
+	}
+
+	dir := t.TempDir()
+	prepareBuildModule(t, dir)
+
+	for _, fixture := range fixtures {
+		base := strings.TrimSuffix(fixture, ".json")
+		pkg := strings.NewReplacer(".", "_", "-", "_").Replace(filepath.Base(filepath.Dir(fixture)) + "_" + filepath.Base(base))
+
+		templFile, err := os.ReadFile(base + ".templ.golden")
+		if err != nil {
+			t.Fatalf("ошибка чтения эталона: %v", err)
+		}
+		templGo, err := generateTemplGo(string(templFile))
+		if err != nil {
+			t.Errorf("%s: ошибка генерации templ: %v", base, err)
+			continue
+		}
+		writeModuleFile(t, filepath.Join(dir, pkg, "templates", "component_templ.go"), templGo)
+
+		// Компоненты из других файлов подставляются заглушками из testdata/golden/external
+		for _, stub := range externalStubs(t, string(templFile)) {
+			stubGo, err := generateTemplGo(stub.source)
+			if err != nil {
+				t.Fatalf("%s: ошибка генерации заглушки: %v", stub.name, err)
+			}
+			writeModuleFile(t, filepath.Join(dir, pkg, "templates", stub.name+"_templ.go"), stubGo)
+		}
+
+		controller, err := os.ReadFile(base + ".go.golden")
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ошибка чтения эталона: %v", err)
+		}
+		imports := strings.Replace(string(controller), `"react-to-templ/templates"`, fmt.Sprintf("%q", "react-to-templ/"+pkg+"/templates"), 1)
+		writeModuleFile(t, filepath.Join(dir, pkg, "controllers", "controller.go"), imports)
+	}
+
+	buildModule(t, dir)
+}
+
+// prepareBuildModule копирует go.mod и go.sum временного модуля из testdata/build и
+// пропускает тест, если модулей из go.mod нет в кеше: сборка не обращается к сети
+func prepareBuildModule(t *testing.T, dir string) {
+	t.Helper()
+	for _, name := range []string{"go.mod", "go.sum"} {
+		content, err := os.ReadFile(filepath.Join("testdata", "build", name))
+		if err != nil {
+			t.Fatalf("ошибка чтения %s: %v", name, err)
+		}
+		writeModuleFile(t, filepath.Join(dir, name), string(content))
+	}
+
+	if output, err := moduleCommand(dir, "go", "mod", "download").CombinedOutput(); err != nil {
+		t.Skipf("модули сборки недоступны в кеше: %v\n%s", err, output)
+	}
+}
+
+// buildModule запускает go build и go vet во временном модуле
+func buildModule(t *testing.T, dir string) {
+	t.Helper()
+	for _, command := range [][]string{{"go", "build", "./..."}, {"go", "vet", "./..."}} {
+		if output, err := moduleCommand(dir, command...).CombinedOutput(); err != nil {
+			t.Fatalf("%s: %v\n%s", strings.Join(command, " "), err, output)
+		}
+	}
+}
+
+// moduleCommand создает команду go для временного модуля: зависимости берутся только из
+// кеша модулей и проверяются по go.sum, go.mod и go.sum не изменяются
+func moduleCommand(dir string, command ...string) *exec.Cmd {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=readonly", "GOPROXY=off")
+	return cmd
+}
+
+// externalStub - заглушка компонента, объявленного вне фикстуры
+type externalStub struct {
+	name   string
+	source string
+}
+
+// externalStubs возвращает заглушки компонентов, которые шаблон вызывает, но не объявляет
+func externalStubs(t *testing.T, templFile string) []externalStub {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "golden", "external", "*.templ"))
+	if err != nil {
+		t.Fatalf("ошибка поиска заглушек: %v", err)
+	}
+
+	var stubs []externalStub
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ошибка чтения заглушки: %v", err)
+		}
+		match := regexp.MustCompile(`(?m)^templ (\w+)\(`).FindStringSubmatch(string(source))
+		if match == nil {
+			t.Fatalf("%s: заглушка не объявляет компонент", path)
+		}
+		component := match[1]
+		if strings.Contains(templFile, "@"+component+"(") && !strings.Contains(templFile, "templ "+component+"(") {
+			stubs = append(stubs, externalStub{name: strings.TrimSuffix(filepath.Base(path), ".templ"), source: string(source)})
+		}
+	}
+	return stubs
+}
+
+// generateTemplGo генерирует Go код из templ файла
+func generateTemplGo(templFile string) (string, error) {
+	template, err := parser.ParseString(templFile)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if _, err := generator.Generate(template, &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// writeModuleFile записывает файл временного модуля, создавая каталоги
+func writeModuleFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("ошибка создания каталога: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("ошибка записи %s: %v", path, err)
+	}
+}
+
+// fixtureInteractivity возвращает режим интерактивности по суффиксу имени фикстуры
+func fixtureInteractivity(base string) string {
+	switch filepath.Ext(base) {
+	case ".hybrid":
+		return config.InteractivityHybrid
+	case ".alpine":
+		return config.InteractivityAlpine
+	}
+	return config.InteractivityHtmx
+}
+
+// assertGolden сравнивает результат с эталонным файлом или перезаписывает его с флагом -update.
+// Пустому результату (компонент без контроллера или JavaScript) эталон не нужен: файла
+// быть не должно, чтобы пустой эталон не скрыл пропавший вывод
+func assertGolden(t *testing.T, path string, name string, actual string) {
+	t.Helper()
+
+	if *update {
+		if actual == "" {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				t.Fatalf("ошибка удаления эталона %s: %v", path, err)
+			}
+			return
+		}
+		if err := os.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatalf("ошибка записи эталона %s: %v", path, err)
+		}
+		return
+	}
+
+	if actual == "" {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("%s пуст, но эталон %s существует (удаляется флагом -update)", name, path)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ошибка чтения эталона %s (создается флагом -update): %v", path, err)
+	}
+	if string(expected) == actual {
+		return
+	}
+
+	line, want, got := firstDifference(string(expected), actual)
+	t.Errorf("%s отличается от эталона %s в строке %d:\n  ожидалось: %q\n  получено:  %q", name, path, line, want, got)
+}
+
+// firstDifference возвращает номер первой различающейся строки и обе ее версии
+func firstDifference(expected string, actual string) (int, string, string) {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var want, got string
+		if i < len(expectedLines) {
+			want = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			got = actualLines[i]
+		}
+		if i >= len(expectedLines) || i >= len(actualLines) || want != got {
+			return i + 1, want, got
+		}
+	}
+	return 0, "", ""
+}
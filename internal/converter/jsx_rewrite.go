@@ -0,0 +1,80 @@
+package converter
+
+import "react-to-templ-converter/internal/models"
+
+// jsxRewriter изменяет дерево JSX на месте до конвертации: выражения (атрибуты, {content},
+// условия) и элементы. Разметка в пропсах (слоты, render props) хранится JSON объектами
+// и обходится так же, как дочерние элементы
+type jsxRewriter struct {
+	// code возвращает новый код выражения
+	code func(code string) string
+	// element возвращает новый тип HTML элемента или компонента; атрибуты можно менять
+	element func(elementType string, props map[string]interface{}) string
+}
+
+// rewrite обходит элемент и его потомков
+func (r jsxRewriter) rewrite(jsx *models.JSXElement) {
+	if jsx == nil {
+		return
+	}
+	if jsx.Props == nil {
+		jsx.Props = make(map[string]interface{})
+	}
+	jsx.Type = r.rewriteElement(jsx.Type, jsx.Props)
+	for _, child := range jsx.Children {
+		r.rewrite(child)
+	}
+}
+
+// rewriteElement переписывает атрибуты элемента и возвращает его новый тип
+func (r jsxRewriter) rewriteElement(elementType string, props map[string]interface{}) string {
+	for name, value := range props {
+		// Код выражения {content} и условия {cond && <X />}
+		if elementType == "expression" && (name == "content" || name == "condition") {
+			if code, ok := value.(string); ok && r.code != nil {
+				props[name] = r.code(code)
+			}
+			continue
+		}
+		r.rewriteValue(value)
+	}
+
+	if r.element == nil || elementType == "text" || elementType == "expression" || elementType == "Fragment" || elementType == "React.Fragment" {
+		return elementType
+	}
+	return r.element(elementType, props)
+}
+
+// rewriteValue переписывает значение атрибута: выражение или разметку в пропсе
+func (r jsxRewriter) rewriteValue(value interface{}) {
+	node, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if code, ok := node["code"].(string); ok {
+		if r.code != nil {
+			node["code"] = r.code(code)
+		}
+		return
+	}
+	if node["type"] == "renderProp" {
+		r.rewriteValue(node["jsx"])
+		return
+	}
+
+	elementType, ok := node["type"].(string)
+	if !ok {
+		return
+	}
+	props, _ := node["props"].(map[string]interface{})
+	if props == nil {
+		props = make(map[string]interface{})
+		node["props"] = props
+	}
+	node["type"] = r.rewriteElement(elementType, props)
+	if children, ok := node["children"].([]interface{}); ok {
+		for _, child := range children {
+			r.rewriteValue(child)
+		}
+	}
+}
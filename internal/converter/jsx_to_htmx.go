@@ -5,14 +5,36 @@ import (
 	"react-to-templ-converter/internal/config"
 	"react-to-templ-converter/internal/models"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
 // JSXToHTMXConverter преобразует JSX элементы в HTML с атрибутами HTMX
 type JSXToHTMXConverter struct {
-	options *config.ConversionOptions
-	indent  int
-	debug   bool
+	options   *config.ConversionOptions
+	component *models.ReactComponent
+	indent    int
+	debug     bool
+
+	// Атрибуты, добавляемые к следующему конвертируемому элементу (x-show условного рендеринга)
+	pendingAttrs string
+
+	// Фрагменты, перерисовываемые отдельно, и параметры templ компонента для них
+	regions         *regionAnalysis
+	currentRegion   *models.JSXElement
+	templParams     string
+	templArgs       string
+	regionTemplates []string
+
+	// Счетчики экземпляров дочерних компонентов (для их id), вынесенной разметки пропсов
+	// и функций, вычисляющих стили; созданные templ css компоненты стилизованных компонентов
+	childIDs   map[string]int
+	slotCount  int
+	styleCount int
+	styledCSS  map[string]bool
+
+	// Тег родительского элемента: содержимое script и style выводится без разбора
+	parentTag string
 }
 
 // NewJSXToHTMXConverter создает новый конвертер JSX в HTMX
@@ -29,6 +51,23 @@ func (c *JSXToHTMXConverter) SetDebug(debug bool) {
 	c.debug = debug
 }
 
+// SetComponent устанавливает компонент, JSX которого конвертируется.
+// Нужен для генерации разметки, зависящей от хуков (эффекты и т.д.)
+func (c *JSXToHTMXConverter) SetComponent(component *models.ReactComponent) {
+	c.component = component
+	c.regions = nil
+	c.childIDs, c.slotCount, c.styleCount, c.styledCSS = nil, 0, 0, nil
+	if c.options.UseHtmx {
+		c.regions = findRenderRegions(component)
+	}
+}
+
+// isRoot проверяет, является ли элемент на этом уровне корневым элементом компонента
+// (корень фрагмента, вынесенного в отдельный templ компонент, не считается)
+func (c *JSXToHTMXConverter) isRoot(indent int) bool {
+	return indent == 1 && c.currentRegion == nil
+}
+
 // ConvertJSXToTempl преобразует JSX дерево в код templ
 func (c *JSXToHTMXConverter) ConvertJSXToTempl(jsx *models.JSXElement, indent int) string {
 	if jsx == nil {
@@ -40,54 +79,105 @@ func (c *JSXToHTMXConverter) ConvertJSXToTempl(jsx *models.JSXElement, indent in
 
 	var sb strings.Builder
 
-	// Fragment превращается в templ {<>...</>}
-	if jsx.Type == "Fragment" {
-		sb.WriteString(indentation + "{<>}\n")
-
-		// Обрабатываем дочерние элементы
+	// Фрагмент не создает элемента: дочерние узлы выводятся на его месте
+	if jsx.IsFragment() {
 		for _, child := range jsx.Children {
-			childHTML := c.ConvertJSXToTempl(child, indent+1)
-			sb.WriteString(childHTML)
+			sb.WriteString(c.ConvertJSXToTempl(child, indent))
 		}
-
-		sb.WriteString(indentation + "{</>}\n")
 		return sb.String()
 	}
 
+	// <ThemeContext.Provider value={...}> -> @ThemeContextProvider(...) { ... }
+	if strings.HasSuffix(jsx.Type, ".Provider") {
+		return c.convertContextProvider(jsx, indent)
+	}
+
 	// Проверяем, является ли тег HTML элементом (начинается с маленькой буквы)
 	isHTMLElement := len(jsx.Type) > 0 && jsx.Type[0] >= 'a' && jsx.Type[0] <= 'z'
 
 	// Если это пользовательский компонент (начинается с большой буквы), конвертируем в вызов templ
 	if !isHTMLElement && jsx.Type != "text" && jsx.Type != "expression" {
-		return c.convertComponent(jsx, indentation)
+		return c.convertComponent(jsx, indent)
 	}
 
 	// Если это текстовый узел
 	if jsx.Type == "text" {
 		if content, ok := jsx.Props["content"].(string); ok && content != "" {
-			sb.WriteString(indentation + content + "\n")
+			sb.WriteString(textNode(content, c.parentTag, indentation))
 		}
 		return sb.String()
 	}
 
 	// Если это выражение
 	if jsx.Type == "expression" {
+		// {cond && <X />} -> x-show или templ if
+		if _, ok := jsx.Props["condition"].(string); ok && len(jsx.Children) > 0 {
+			return c.convertConditional(jsx, indent)
+		}
+
 		if content, ok := jsx.Props["content"].(string); ok && content != "" {
-			// Преобразуем React выражение в Go
+			// Содержимое script и style: выводится только строковый литерал
+			if isRawTextElement(c.parentTag) {
+				if text := rawTextExpression(jsx); text != "" {
+					sb.WriteString(indentation + escapeRawText(text, c.parentTag) + "\n")
+				}
+				return sb.String()
+			}
+
+			// Выражение на клиентских состояниях вычисляет Alpine.js
+			if referencesClientState(c.component, content) {
+				return c.convertAlpineText(content, indentation)
+			}
+
+			// Вызов render prop: {renderItem(item)}
+			if call, ok := c.convertRenderPropCall(content, indentation); ok {
+				return call
+			}
+
+			// {children} и пропсы с разметкой выводятся как templ компоненты
+			if slot := c.slotReference(content); slot != "" {
+				return c.convertSlotExpression(slot, indentation)
+			}
+
+			// Преобразуем React выражение в Go; templ выводит строку, экранируя ее
 			goExpr := c.convertReactExpressionToGo(content)
-			sb.WriteString(indentation + "{ " + goExpr + " }\n")
+			sb.WriteString(indentation + "{ " + c.stringExpression(goExpr) + " }\n")
 		}
 		return sb.String()
 	}
 
+	// Элемент, зависящий от серверных состояний, выносится в отдельный templ компонент
+	if c.regions != nil && jsx != c.currentRegion {
+		if region := c.regions.find(jsx); region != nil {
+			return c.convertRegionCall(jsx, region, indent)
+		}
+	}
+
+	// dangerouslySetInnerHTML={{ __html: value }} -> @templ.Raw(value)
+	if value, ok := jsx.Props["dangerouslySetInnerHTML"]; ok {
+		if goExpr, ok := c.innerHTMLExpression(value); ok {
+			return c.convertInnerHTML(jsx, goExpr, c.convertElementAttributes(jsx), indentation)
+		}
+	}
+
+	// Выражения внутри script и style не выводятся - сообщаем об этом в шаблоне
+	sb.WriteString(droppedRawTextComments(jsx, indentation))
+
 	// Открывающий тег HTML элемента
 	sb.WriteString(indentation + "<" + jsx.Type)
 
 	// Атрибуты
 	sb.WriteString(c.convertElementAttributes(jsx))
 
+	// Эффекты, работающие только с DOM refs, выполняются скриптом после дочерних элементов
+	// корневого элемента, когда элементы с refs уже вставлены
+	refEffects := ""
+	if c.isRoot(indent) {
+		refEffects = c.convertRefEffects(indent + 1)
+	}
+
 	// Если нет дочерних элементов, закрываем тег сразу
-	if len(jsx.Children) == 0 {
+	if len(jsx.Children) == 0 && refEffects == "" {
 		// Для самозакрывающихся тегов
 		if isVoidElement(jsx.Type) {
 			sb.WriteString(" />\n")
@@ -102,10 +192,14 @@ func (c *JSXToHTMXConverter) ConvertJSXToTempl(jsx *models.JSXElement, indent in
 	sb.WriteString(">\n")
 
 	// Обрабатываем дочерние элементы
+	savedParent := c.parentTag
+	c.parentTag = jsx.Type
 	for _, child := range jsx.Children {
 		childHTML := c.ConvertJSXToTempl(child, indent+1)
 		sb.WriteString(childHTML)
 	}
+	c.parentTag = savedParent
+	sb.WriteString(refEffects)
 
 	// Закрывающий тег
 	sb.WriteString(indentation + "</" + jsx.Type + ">\n")
@@ -113,110 +207,373 @@ func (c *JSXToHTMXConverter) ConvertJSXToTempl(jsx *models.JSXElement, indent in
 	return sb.String()
 }
 
-// convertComponent преобразует пользовательский компонент в вызов templ
-func (c *JSXToHTMXConverter) convertComponent(jsx *models.JSXElement, indentation string) string {
+// convertComponent преобразует пользовательский компонент в вызов templ.
+// Дочерние элементы передаются через механизм children templ, разметка в пропсах -
+// полями templ.Component, а каждый экземпляр получает свой id, производный от id родителя.
+// Компоненты из других файлов проекта вызываются из своих пакетов с параметрами
+// по их объявлению (пропсы, состояние)
+func (c *JSXToHTMXConverter) convertComponent(jsx *models.JSXElement, indent int) string {
 	var sb strings.Builder
+	indentation := strings.Repeat("\t", indent)
 
-	// Имя компонента с маленькой буквы для templ
-	templComponentName := strings.ToLower(string(jsx.Type[0])) + jsx.Type[1:]
+	// Компонент, импортированный из другого файла проекта
+	ref := c.options.Components[jsx.Type]
+	name, packagePrefix := jsx.Type, ""
+	if ref != nil {
+		name = ref.Name
+		if ref.ImportPath != c.options.TemplatesImportPath() {
+			packagePrefix = ref.Package + "."
+		}
+	}
+
+	// templ компоненты экспортируются, вызов одинаков внутри пакета и из других пакетов
+	templComponentName := exportedName(name)
 
-	sb.WriteString(indentation + "{\n")
-	sb.WriteString(indentation + "\t// Вызов компонента " + jsx.Type + "\n")
+	var args []string
 
-	// Полный путь к компоненту (с учетом пакета)
-	packagePrefix := ""
-	if c.options.PackageName != "" && c.options.PackageName != "." {
-		packagePrefix = c.options.PackageName + "."
+	// key задает id экземпляра и в пропсы не передается, children передаются блоком
+	props := make(map[string]interface{})
+	var propNames []string
+	for _, propName := range jsx.PropNames() {
+		if propName != "key" && propName != "children" {
+			props[propName] = jsx.Props[propName]
+			propNames = append(propNames, propName)
+		}
 	}
 
-	sb.WriteString(indentation + "\t" + packagePrefix + templComponentName + "(")
+	sb.WriteString(fmt.Sprintf("%s// Вызов компонента %s\n", indentation, jsx.Type))
+
+	// Если есть пропсы, передаем их. Для известного компонента структура пропсов
+	// передается всегда, когда он их объявляет
+	if (ref == nil && len(props) > 0) || (ref != nil && len(ref.Props) > 0) {
+		var fields strings.Builder
+		fields.WriteString(packagePrefix + name + "Props{\n")
+
+		// Необъявленные пропсы и {...rest} передаются атрибутами через rest-пропс компонента
+		var rest *models.PropDefinition
+		if ref != nil {
+			rest = restProp(ref.Props)
+		}
+		attrs, dropped := c.componentAttrs(jsx, propNames, ref)
 
-	// Если есть пропсы, передаем их
-	if len(jsx.Props) > 0 {
-		sb.WriteString(packagePrefix + jsx.Type + "Props{\n")
+		for _, propName := range componentPropNames(append(propNames, defaultedProps(props, ref)...), ref) {
+			value, passed := props[propName]
 
-		// Передаем пропсы
-		for name, value := range jsx.Props {
-			propName := strings.ToUpper(string(name[0])) + name[1:]
+			// Непереданный пропс со значением по умолчанию: size = 'md'
+			if !passed {
+				definition := ref.FindProp(propName)
+				fields.WriteString(indentation + "\t" + exportedName(propName) + ": " + models.GoLiteral(definition.DefaultValue, models.PropFieldType(*definition)) + ",\n")
+				continue
+			}
+			if isSpreadProp(propName) {
+				if rest == nil {
+					sb.WriteString(fmt.Sprintf("%s// {...%s} не передается: у %s нет rest-пропса\n", indentation, spreadCode(value), name))
+				}
+				continue
+			}
 
-			if value == true {
-				// Boolean prop
-				sb.WriteString(indentation + "\t\t" + propName + ": true,\n")
-			} else if valueStr, ok := value.(string); ok {
-				// String prop
-				sb.WriteString(indentation + "\t\t" + propName + ": \"" + valueStr + "\",\n")
-			} else if valueExpr, ok := value.(map[string]interface{}); ok {
-				// Expression prop
-				if expr, ok := valueExpr["code"].(string); ok {
-					// Преобразуем React выражение в Go
-					goExpr := c.convertReactExpressionToGo(expr)
-					sb.WriteString(indentation + "\t\t" + propName + ": " + goExpr + ",\n")
+			var definition *models.PropDefinition
+			if ref != nil {
+				if definition = ref.FindProp(propName); definition == nil || definition.Rest {
+					if rest == nil {
+						sb.WriteString(fmt.Sprintf("%s// Пропс %s не объявлен в %sProps и не передается\n", indentation, propName, name))
+					}
+					continue
 				}
 			}
+
+			if goValue := c.componentPropValue(jsx.Type, propName, value, definition); goValue != "" {
+				fields.WriteString(indentation + "\t" + propFieldName(propName) + ": " + goValue + ",\n")
+			}
+		}
+
+		if attrs != "" {
+			fields.WriteString(indentation + "\t" + exportedName(rest.Name) + ": " + attrs + ",\n")
+		}
+		if dropped != "" {
+			sb.WriteString(fmt.Sprintf("%s// %s не передается: атрибуты %s заданы явно\n", indentation, dropped, name))
 		}
 
-		sb.WriteString(indentation + "\t}")
+		fields.WriteString(indentation + "}")
+		args = append(args, fields.String())
 	}
 
-	// Если используем HTMX, добавляем ID
+	// Если используем HTMX, передаем id экземпляра
+	childID := `""`
 	if c.options.UseHtmx {
-		if len(jsx.Props) > 0 {
-			sb.WriteString(", ")
+		childID = c.childComponentID(jsx)
+		args = append(args, childID)
+	}
+
+	// Компонент с состоянием получает состояние своего экземпляра из хранилища по id
+	// (загрузка передается в контексте), иначе каждая перерисовка родителя сбрасывала бы его к начальному
+	if ref != nil && ref.HasState {
+		args = append(args, fmt.Sprintf("%s%s(ctx, %s)", packagePrefix, stateLoaderName(name), childID))
+	}
+
+	sb.WriteString(fmt.Sprintf("%s@%s%s(%s)", indentation, packagePrefix, templComponentName, strings.Join(args, ", ")))
+
+	// Дочерние элементы передаются как children
+	if len(jsx.Children) == 0 {
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	sb.WriteString(" {\n")
+	for _, child := range jsx.Children {
+		sb.WriteString(c.ConvertJSXToTempl(child, indent+1))
+	}
+	sb.WriteString(indentation + "}\n")
+
+	return sb.String()
+}
+
+// componentPropValue возвращает значение пропса дочернего компонента на Go. Если объявление
+// пропса известно, значение приводится к его типу (templ.Component, int, срез, структура)
+func (c *JSXToHTMXConverter) componentPropValue(owner string, name string, value interface{}, definition *models.PropDefinition) string {
+	propType := ""
+	if definition != nil {
+		propType = definition.Type
+	}
+
+	// Render prop: renderItem={item => <li>{item}</li>}
+	if params, tree := renderPropValue(value); tree != nil {
+		return c.convertRenderPropTemplate(owner, name, params, tree, propType)
+	}
+
+	// Разметка в пропсе: header={<h1>...</h1>}
+	if slot := jsxPropValue(value); slot != nil {
+		return c.convertSlotTemplate(owner, name, slot, nil, nil)
+	}
+
+	// Пропс объявлен как разметка, а передается строка или выражение
+	if models.IsSlotType(propType) {
+		switch v := value.(type) {
+		case string:
+			return fmt.Sprintf("templ.Raw(templ.EscapeString(%q))", v)
+		case map[string]interface{}:
+			code, _ := v["code"].(string)
+			switch c.slotReference(code) {
+			case "":
+				return fmt.Sprintf("templ.Raw(templ.EscapeString(fmt.Sprint(%s)))", c.convertReactExpressionToGo(code))
+			case "children":
+				return "templ.GetChildren(ctx)"
+			default:
+				return "props." + exportedName(c.slotReference(code))
+			}
+		}
+	}
+
+	// Строки, числа, массивы, объекты и колбэки - литералами Go по типу пропса
+	return c.propLiteral(name, value, definition)
+}
+
+// convertContextProvider преобразует провайдер контекста в вызов templ обертки,
+// которая кладет значение в ctx дочерних компонентов
+func (c *JSXToHTMXConverter) convertContextProvider(jsx *models.JSXElement, indent int) string {
+	var sb strings.Builder
+	indentation := strings.Repeat("\t", indent)
+	contextName := strings.TrimSuffix(jsx.Type, ".Provider")
+
+	value := "nil"
+	switch v := jsx.Props["value"].(type) {
+	case string:
+		value = fmt.Sprintf("%q", v)
+	case bool:
+		value = fmt.Sprintf("%t", v)
+	case map[string]interface{}:
+		if code, ok := v["code"].(string); ok {
+			value = c.convertReactExpressionToGo(code)
 		}
-		sb.WriteString("id")
 	}
 
-	sb.WriteString(")\n")
+	sb.WriteString(fmt.Sprintf("%s@%sProvider(%s) {\n", indentation, contextName, value))
+	for _, child := range jsx.Children {
+		sb.WriteString(c.ConvertJSXToTempl(child, indent+1))
+	}
 	sb.WriteString(indentation + "}\n")
 
 	return sb.String()
 }
 
+// convertEffectTriggers генерирует скрытые элементы, запускающие эффекты компонента:
+// setInterval -> hx-trigger="every Ns", setTimeout -> hx-trigger="load delay:N",
+// EventSource/WebSocket -> расширение htmx sse. Элементы размещаются в оболочке
+// компонента рядом с корневым элементом, который они перерисовывают
+func (c *JSXToHTMXConverter) convertEffectTriggers(indent int) string {
+	if c.component == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	indentation := strings.Repeat("\t", indent)
+	target := fmt.Sprintf("{ \"#%s-\" + id }", c.componentName())
+
+	for i, effect := range c.component.Effects {
+		route := effectRoutePath(c.component, i)
+
+		switch effect.Kind {
+		case models.EffectKindInterval, models.EffectKindTimeout:
+			sb.WriteString(fmt.Sprintf("%s<div hx-post={ \"%s?id=\" + id } hx-trigger=\"%s\" hx-target=%s hx-swap=\"outerHTML\"></div>\n",
+				indentation, route, effectTrigger(effect), target))
+		case models.EffectKindSubscription:
+			sb.WriteString(fmt.Sprintf("%s<div hx-ext=\"sse\" sse-connect={ \"%s?id=\" + id } sse-swap=\"message\" hx-target=%s hx-swap=\"outerHTML\"></div>\n",
+				indentation, route, target))
+		}
+	}
+
+	return sb.String()
+}
+
+// componentName возвращает имя компонента, используемое в id корневого элемента
+func (c *JSXToHTMXConverter) componentName() string {
+	if c.options.ComponentName == "" && c.component != nil {
+		return c.component.Name
+	}
+	return c.options.ComponentName
+}
+
 // convertElementAttributes преобразует атрибуты JSX в атрибуты HTML+HTMX
 func (c *JSXToHTMXConverter) convertElementAttributes(jsx *models.JSXElement) string {
 	var sb strings.Builder
 
 	// Если используем HTMX и это корневой элемент (indent == 1), добавляем ID
-	if c.options.UseHtmx && c.indent == 1 {
+	if c.options.UseHtmx && c.isRoot(c.indent) {
 		componentName := c.options.ComponentName
-		sb.WriteString(fmt.Sprintf(" id=\"%s-{id}\"", componentName))
+		sb.WriteString(fmt.Sprintf(" id={ \"%s-\" + id }", componentName))
+	}
+
+	// Клиентские состояния объявляются в x-data корневого элемента, если x-data не вынесен в оболочку
+	if c.isRoot(c.indent) && c.component != nil && len(c.component.ClientState) > 0 &&
+		!(c.options.UseHtmx && hasClientStateShell(c.component)) {
+		sb.WriteString(fmt.Sprintf(" x-data=\"%s\"", alpineData(c.component)))
 	}
 
-	// Обычные атрибуты
-	for name, value := range jsx.Props {
-		// Преобразуем camelCase в kebab-case для HTML атрибутов
-		attrName := camelCaseToKebabCase(name)
+	if c.pendingAttrs != "" {
+		sb.WriteString(c.pendingAttrs)
+		c.pendingAttrs = ""
+	}
+
+	// Обычные атрибуты в порядке исходного кода
+	for _, name := range jsx.PropNames() {
+		value := jsx.Props[name]
+		// Имя пропса React -> имя атрибута HTML (className -> class, tabIndex -> tabindex)
+		attrName := models.HTMLAttributeName(name)
+
+		// Разметка из dangerouslySetInnerHTML выводится содержимым элемента
+		if name == "dangerouslySetInnerHTML" {
+			continue
+		}
+
+		// Стилизованный компонент: класс templ css компонента вместе с className.
+		// Пропсы, читаемые стилями, в HTML не выводятся
+		if name == styledMarkerProp {
+			sb.WriteString(c.styledClassAttribute(jsx))
+			continue
+		}
+		if _, styled := jsx.Props[styledMarkerProp]; styled && (name == "className" || c.isStyledParam(jsx, name)) {
+			continue
+		}
+
+		// Вариант управляемого select: selected?={ state.Choice == "a" }
+		if valueExpr, ok := value.(map[string]interface{}); ok && name == "selected" && valueExpr["option"] != nil {
+			sb.WriteString(c.selectedAttribute(valueExpr))
+			continue
+		}
+
+		// {...rest} -> { props.Rest... }
+		if isSpreadProp(name) {
+			sb.WriteString(c.spreadAttributes(jsx.Type, value))
+			continue
+		}
+
+		// style={{ color: 'red' }} -> строка CSS
+		if valueExpr, ok := value.(map[string]interface{}); ok && name == "style" {
+			if code, ok := valueExpr["code"].(string); ok && !referencesClientState(c.component, code) {
+				sb.WriteString(c.styleAttribute(jsx.Type, code))
+				continue
+			}
+		}
+
+		// className={clsx('btn', { active })} -> templ.Classes
+		if valueExpr, ok := value.(map[string]interface{}); ok && name == "className" {
+			if code, ok := valueExpr["code"].(string); ok && !referencesClientState(c.component, code) {
+				sb.WriteString(c.classAttribute(jsx.Type, code))
+				continue
+			}
+		}
+
+		// ref={inputRef} -> стабильный id элемента
+		if name == "ref" {
+			sb.WriteString(c.convertRefAttribute(value))
+			continue
+		}
 
-		// Обработка специальных атрибутов
-		if name == "className" {
-			attrName = "class"
-		} else if name == "htmlFor" {
-			attrName = "for"
+		// Сеттеры клиентских состояний в обработчике -> x-on с выражением Alpine.js
+		if alpineAttr, clientOnly := c.convertAlpineHandler(name, value); alpineAttr != "" {
+			sb.WriteString(alpineAttr)
+			if clientOnly {
+				continue
+			}
+		}
+
+		// Атрибут, зависящий от клиентских состояний -> x-bind
+		if bindAttr := c.convertAlpineBinding(attrName, value); bindAttr != "" {
+			sb.WriteString(bindAttr)
+			continue
+		}
+
+		// Обработчик меняет только состояния без серверного обработчика сеттера
+		if c.isNeedlessHandler(name, value) {
+			continue
 		}
 
 		// Добавляем HTMX атрибуты, если нужно
 		if c.options.UseHtmx {
+			// inputRef.current.focus() в обработчике -> hx-on с клиентским кодом
+			if refAttr, clientOnly := c.convertRefHandler(name, value); refAttr != "" {
+				sb.WriteString(refAttr)
+				if clientOnly {
+					continue
+				}
+			}
+
+			// Вызов колбэк-пропса -> запрос к URL действия родителя
+			if callbackAttr := c.convertCallbackPropEvent(name, value); callbackAttr != "" {
+				sb.WriteString(callbackAttr)
+				continue
+			}
+
 			// React обработчики событий -> HTMX атрибуты
-			htmxAttr := c.convertReactEventToHtmx(name, value)
+			htmxAttr := c.convertReactEventToHtmx(jsx, name, value)
 			if htmxAttr != "" {
 				sb.WriteString(htmxAttr)
 				continue
 			}
 		}
 
-		// Обычные атрибуты
+		// Обработчик события без серверного действия (меняет состояние, которое не
+		// выводится) атрибутом не выводится: в on* атрибутах templ ожидает
+		// templ.ComponentScript. Код, записанный строкой, остается атрибутом onclick
+		if isEventProp(name) {
+			if _, isCode := value.(string); !isCode {
+				continue
+			}
+			attrName = strings.ToLower(name)
+		}
+
+		// Обычные атрибуты экранируются по контексту значения (URL, style, текст)
 		if value == true {
 			// Boolean attribute
 			sb.WriteString(" " + attrName)
 		} else if valueStr, ok := value.(string); ok {
 			// String attribute
-			sb.WriteString(" " + attrName + "=\"" + valueStr + "\"")
+			sb.WriteString(constantAttribute(jsx.Type, attrName, valueStr))
 		} else if valueExpr, ok := value.(map[string]interface{}); ok {
 			// Expression attribute
 			if expr, ok := valueExpr["code"].(string); ok {
 				// Преобразуем React выражение в Go
-				goExpr := c.convertReactExpressionToGo(expr)
-				sb.WriteString(" " + attrName + "=\"{ " + goExpr + " }\"")
+				sb.WriteString(c.expressionAttribute(jsx.Type, attrName, expr))
 			}
 		}
 	}
@@ -224,8 +581,8 @@ func (c *JSXToHTMXConverter) convertElementAttributes(jsx *models.JSXElement) st
 	return sb.String()
 }
 
-// convertReactEventToHtmx преобразует React обработчик события в атрибуты HTMX
-func (c *JSXToHTMXConverter) convertReactEventToHtmx(name string, value interface{}) string {
+// convertReactEventToHtmx преобразует React обработчик события элемента в атрибуты HTMX
+func (c *JSXToHTMXConverter) convertReactEventToHtmx(jsx *models.JSXElement, name string, value interface{}) string {
 	// Обрабатываем только React обработчики событий
 	if !strings.HasPrefix(name, "on") || len(name) < 3 || name[2] < 'A' || name[2] > 'Z' {
 		return ""
@@ -234,37 +591,52 @@ func (c *JSXToHTMXConverter) convertReactEventToHtmx(name string, value interfac
 	var sb strings.Builder
 	componentName := c.options.ComponentName
 
+	// Ссылка на колбэк компонента (onClick={toggle}) - вызов его обработчика
+	if valueExpr, ok := value.(map[string]interface{}); ok {
+		if code, _ := valueExpr["code"].(string); c.isCallbackName(code) {
+			value = map[string]interface{}{"code": strings.TrimSpace(code) + "()"}
+		}
+	}
+
+	// dispatch({ type: '...' }) -> запрос к обработчику редьюсера
+	if valueExpr, ok := value.(map[string]interface{}); ok {
+		if expr, ok := valueExpr["code"].(string); ok {
+			if attrs := c.convertDispatchToHtmx(expr); attrs != "" {
+				switch name {
+				case "onClick", "onSubmit":
+				case "onChange":
+					sb.WriteString(" hx-trigger=\"change\"")
+				default:
+					sb.WriteString(fmt.Sprintf(" hx-trigger=\"%s\"", strings.ToLower(name[2:])))
+				}
+				sb.WriteString(attrs)
+				return sb.String()
+			}
+		}
+	}
+
 	switch name {
 	case "onClick":
 		// React onClick -> hx-post для изменения состояния
 		if valueExpr, ok := value.(map[string]interface{}); ok {
 			if expr, ok := valueExpr["code"].(string); ok {
 				// Пытаемся извлечь имя функции или вызываемого метода
-				setterMatch := regexp.MustCompile(`set(\w+)\(`).FindStringSubmatch(expr)
-				if len(setterMatch) > 1 {
-					stateName := setterMatch[1]
+				if setter := c.stateSetterName(expr); setter != "" {
+					stateName := strings.TrimPrefix(setter, "set")
 					statePath := strings.ToLower(stateName[:1]) + stateName[1:]
 
-					sb.WriteString(fmt.Sprintf(" hx-post=\"/api/%s/%s?id={id}\"", strings.ToLower(componentName), statePath))
-					sb.WriteString(fmt.Sprintf(" hx-target=\"#%s-{id}\"", componentName))
-					sb.WriteString(" hx-swap=\"outerHTML\"")
+					sb.WriteString(fmt.Sprintf(" hx-post={ \"/api/%s/%s?id=\" + id }", strings.ToLower(componentName), statePath))
+					sb.WriteString(c.setterSwapAttributes("set" + stateName))
 
-					// Пытаемся извлечь значение, которое передается в setter
-					valueMatch := regexp.MustCompile(`\((.*)\)`).FindStringSubmatch(expr)
-					if len(valueMatch) > 1 {
-						valueExpr := valueMatch[1]
-						// Если это не просто переменная, а выражение
-						if !regexp.MustCompile(`^\w+$`).MatchString(valueExpr) {
-							sb.WriteString(fmt.Sprintf(" hx-vals='{\"value\": %s}'", valueExpr))
-						}
-					}
+					// Значение, которое передается в setter, вычисляется при рендеринге
+					sb.WriteString(c.setterValueAttribute(expr, "set"+stateName))
 				} else {
 					// Обычный onClick -> hx-post с именем функции
 					funcMatch := regexp.MustCompile(`(\w+)\(`).FindStringSubmatch(expr)
 					if len(funcMatch) > 1 {
 						funcName := funcMatch[1]
-						sb.WriteString(fmt.Sprintf(" hx-post=\"/api/%s/%s?id={id}\"", strings.ToLower(componentName), strings.ToLower(funcName)))
-						sb.WriteString(fmt.Sprintf(" hx-target=\"#%s-{id}\"", componentName))
+						sb.WriteString(fmt.Sprintf(" hx-post={ \"/api/%s/%s?id=\" + id }", strings.ToLower(componentName), strings.ToLower(funcName)))
+						sb.WriteString(fmt.Sprintf(" hx-target={ \"#%s-\" + id }", componentName))
 						sb.WriteString(" hx-swap=\"outerHTML\"")
 					}
 				}
@@ -272,27 +644,27 @@ func (c *JSXToHTMXConverter) convertReactEventToHtmx(name string, value interfac
 		}
 
 	case "onChange":
-		// React onChange -> hx-trigger с keyup событием
-		sb.WriteString(" hx-trigger=\"keyup changed delay:500ms\"")
+		// React onChange -> hx-trigger: текст отправляется после паузы ввода,
+		// выбор в select, флажке и переключателе - сразу по событию change
+		sb.WriteString(fmt.Sprintf(" hx-trigger=\"%s\"", changeTrigger(jsx)))
 
 		if valueExpr, ok := value.(map[string]interface{}); ok {
 			if expr, ok := valueExpr["code"].(string); ok {
-				setterMatch := regexp.MustCompile(`set(\w+)\(`).FindStringSubmatch(expr)
-				if len(setterMatch) > 1 {
-					stateName := setterMatch[1]
+				if setter := c.stateSetterName(expr); setter != "" {
+					stateName := strings.TrimPrefix(setter, "set")
 					statePath := strings.ToLower(stateName[:1]) + stateName[1:]
 
-					sb.WriteString(fmt.Sprintf(" hx-post=\"/api/%s/%s?id={id}\"", strings.ToLower(componentName), statePath))
-					sb.WriteString(fmt.Sprintf(" hx-target=\"#%s-{id}\"", componentName))
-					sb.WriteString(" hx-swap=\"outerHTML\"")
+					sb.WriteString(fmt.Sprintf(" hx-post={ \"/api/%s/%s?id=\" + id }", strings.ToLower(componentName), statePath))
+					sb.WriteString(c.setterSwapAttributes("set" + stateName))
+					sb.WriteString(c.changeValueAttribute(expr, "set"+stateName))
 				}
 			}
 		}
 
 	case "onSubmit":
 		// React onSubmit -> hx-post для формы
-		sb.WriteString(fmt.Sprintf(" hx-post=\"/api/%s/submit?id={id}\"", strings.ToLower(componentName)))
-		sb.WriteString(fmt.Sprintf(" hx-target=\"#%s-{id}\"", componentName))
+		sb.WriteString(fmt.Sprintf(" hx-post={ \"/api/%s/submit?id=\" + id }", strings.ToLower(componentName)))
+		sb.WriteString(fmt.Sprintf(" hx-target={ \"#%s-\" + id }", componentName))
 		sb.WriteString(" hx-swap=\"outerHTML\"")
 
 	case "onFocus", "onBlur":
@@ -305,8 +677,8 @@ func (c *JSXToHTMXConverter) convertReactEventToHtmx(name string, value interfac
 				funcMatch := regexp.MustCompile(`(\w+)\(`).FindStringSubmatch(expr)
 				if len(funcMatch) > 1 {
 					funcName := funcMatch[1]
-					sb.WriteString(fmt.Sprintf(" hx-post=\"/api/%s/%s?id={id}\"", strings.ToLower(componentName), strings.ToLower(funcName)))
-					sb.WriteString(fmt.Sprintf(" hx-target=\"#%s-{id}\"", componentName))
+					sb.WriteString(fmt.Sprintf(" hx-post={ \"/api/%s/%s?id=\" + id }", strings.ToLower(componentName), strings.ToLower(funcName)))
+					sb.WriteString(fmt.Sprintf(" hx-target={ \"#%s-\" + id }", componentName))
 					sb.WriteString(" hx-swap=\"outerHTML\"")
 				}
 			}
@@ -316,6 +688,31 @@ func (c *JSXToHTMXConverter) convertReactEventToHtmx(name string, value interfac
 	return sb.String()
 }
 
+// stateSetterName возвращает первый сеттер состояния, который вызывает код обработчика,
+// или пустую строку. setTimeout и другие функции set* сеттерами не считаются
+func (c *JSXToHTMXConverter) stateSetterName(code string) string {
+	if c.component == nil {
+		return ""
+	}
+	if match := setterCallRegex(c.component).FindStringSubmatch(code); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// isCallbackName проверяет, что код - имя колбэка компонента (useCallback или функция)
+func (c *JSXToHTMXConverter) isCallbackName(code string) bool {
+	if c.component == nil {
+		return false
+	}
+	for _, callback := range c.component.Callbacks {
+		if callback.Name == strings.TrimSpace(code) {
+			return true
+		}
+	}
+	return false
+}
+
 // convertReactExpressionToGo преобразует React выражение в Go
 func (c *JSXToHTMXConverter) convertReactExpressionToGo(expr string) string {
 	// Это очень упрощенная версия, в реальности потребуется более сложный парсинг
@@ -342,10 +739,19 @@ func (c *JSXToHTMXConverter) convertReactExpressionToGo(expr string) string {
 func (c *JSXToHTMXConverter) convertReactExprToGoExpr(expr string) string {
 	expr = strings.TrimSpace(expr)
 
+	// Строки в одинарных кавычках -> строки Go
+	expr = quoteStringLiterals(expr)
+
+	// Состояния передаются в templ компонент структурой state, деструктурированные пропсы - props
+	expr = c.qualifyStateReferences(expr)
+	expr = c.qualifyPropReferences(expr)
+
 	// Заменяем некоторые общие выражения
 
 	// Доступ к пропсам
-	expr = regexp.MustCompile(`props\.(\w+)`).ReplaceAllString(expr, "props.$1")
+	expr = regexp.MustCompile(`props\.(\w+)`).ReplaceAllStringFunc(expr, func(match string) string {
+		return "props." + exportedName(match[len("props."):])
+	})
 
 	// Логические операторы
 	expr = strings.ReplaceAll(expr, "&&", "&&")
@@ -364,17 +770,162 @@ func (c *JSXToHTMXConverter) convertReactExprToGoExpr(expr string) string {
 			// Убираем обратные кавычки
 			s = s[1 : len(s)-1]
 
-			// Заменяем ${expr} на %v
-			s = regexp.MustCompile(`\$\{(.*?)\}`).ReplaceAllString(s, "%v")
+			// Заменяем ${expr} на %v, выражения становятся аргументами
+			var args []string
+			s = strings.ReplaceAll(s, "%", "%%")
+			s = regexp.MustCompile(`\$\{(.*?)\}`).ReplaceAllStringFunc(s, func(match string) string {
+				args = append(args, strings.TrimSpace(match[2:len(match)-1]))
+				return "%v"
+			})
+			if len(args) == 0 {
+				return strconv.Quote(strings.ReplaceAll(s, "%%", "%"))
+			}
 
 			// Создаем вызов fmt.Sprintf
-			return fmt.Sprintf("fmt.Sprintf(\"%s\")", s)
+			return fmt.Sprintf("fmt.Sprintf(%s, %s)", strconv.Quote(s), strings.Join(args, ", "))
 		})
 	}
 
 	return expr
 }
 
+// qualifyPropReferences заменяет обращения к деструктурированным пропсам
+// ({ title, active }) полями структуры пропсов: active -> props.Active.
+// Содержимое строк не меняется
+func (c *JSXToHTMXConverter) qualifyPropReferences(expr string) string {
+	if c.component == nil {
+		return expr
+	}
+
+	for _, prop := range c.component.Props {
+		if prop.Name == "children" || prop.Name == "props" {
+			continue
+		}
+		propRegex := regexp.MustCompile(`(^|[^\w.$])` + regexp.QuoteMeta(prop.Name) + `\b`)
+		expr = replaceOutsideStrings(expr, func(code string) string {
+			return propRegex.ReplaceAllString(code, "${1}props."+exportedName(prop.Name))
+		})
+	}
+	return expr
+}
+
+// quoteStringLiterals переводит строки JavaScript в одинарных кавычках в строки Go
+func quoteStringLiterals(expr string) string {
+	var sb strings.Builder
+	for _, segment := range splitStringLiterals(expr) {
+		if strings.HasPrefix(segment, "'") {
+			if value, ok := models.ParseJSLiteral(segment); ok {
+				sb.WriteString(strconv.Quote(value.(string)))
+				continue
+			}
+		}
+		sb.WriteString(segment)
+	}
+	return sb.String()
+}
+
+// replaceOutsideStrings применяет замену к коду вне строковых литералов ('...' и "...")
+func replaceOutsideStrings(expr string, replace func(code string) string) string {
+	var sb strings.Builder
+	for _, segment := range splitStringLiterals(expr) {
+		if strings.HasPrefix(segment, "'") || strings.HasPrefix(segment, `"`) {
+			sb.WriteString(segment)
+		} else {
+			sb.WriteString(replace(segment))
+		}
+	}
+	return sb.String()
+}
+
+// splitStringLiterals делит выражение на строковые литералы в кавычках и код между ними.
+// Шаблонные строки считаются кодом: подстановки ${} в них - выражения
+func splitStringLiterals(expr string) []string {
+	var segments []string
+	start := 0
+	for i := 0; i < len(expr); i++ {
+		quote := expr[i]
+		if quote != '\'' && quote != '"' {
+			continue
+		}
+
+		end := i + 1
+		for end < len(expr) && expr[end] != quote {
+			if expr[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(expr) {
+			break
+		}
+
+		segments = append(segments, expr[start:i], expr[i:end+1])
+		start, i = end+1, end
+	}
+	return append(segments, expr[start:])
+}
+
+// qualifyStateReferences заменяет обращения к состояниям компонента полями структуры
+// состояния: count -> state.Count, для редьюсеров todos.items -> state.Items.
+// Содержимое строк не меняется
+func (c *JSXToHTMXConverter) qualifyStateReferences(expr string) string {
+	if c.component == nil {
+		return expr
+	}
+
+	return replaceOutsideStrings(expr, func(code string) string {
+		for _, reducer := range c.component.Reducers {
+			fieldRegex := regexp.MustCompile(`(^|[^\w.$])` + regexp.QuoteMeta(reducer.Name) + `\.(\w+)`)
+			code = fieldRegex.ReplaceAllStringFunc(code, func(match string) string {
+				parts := fieldRegex.FindStringSubmatch(match)
+				return parts[1] + "state." + exportedName(parts[2])
+			})
+			if field, ok := scalarReducerField(reducer); ok {
+				code = regexp.MustCompile(`(^|[^\w.$])`+regexp.QuoteMeta(reducer.Name)+`\b`).ReplaceAllString(code, "${1}state."+exportedName(field.Name))
+			}
+		}
+
+		for _, state := range c.component.State {
+			code = regexp.MustCompile(`(^|[^\w.$])`+regexp.QuoteMeta(state.Name)+`\b`).ReplaceAllString(code, "${1}state."+exportedName(state.Name))
+		}
+		return code
+	})
+}
+
+// propFieldName возвращает поле структуры пропсов для пропса компонента: атрибуты
+// через дефис (data-id) становятся полями DataId
+func propFieldName(name string) string {
+	parts := strings.Split(name, "-")
+	for i, part := range parts {
+		parts[i] = exportedName(part)
+	}
+	return strings.Join(parts, "")
+}
+
+// changeTrigger возвращает событие htmx для onChange элемента: у select, флажков и
+// переключателей нет ввода с клавиатуры, их значение меняет событие change
+func changeTrigger(jsx *models.JSXElement) string {
+	inputType, _ := jsx.Props["type"].(string)
+	if jsx.Type == "select" || jsx.Type == "input" && (inputType == "checkbox" || inputType == "radio") {
+		return "change"
+	}
+	return "keyup changed delay:500ms"
+}
+
+// eventValueRegex - значение элемента из события: e.target.value, event.currentTarget.checked
+var eventValueRegex = regexp.MustCompile(`\b\w+\.(?:target|currentTarget)\.(value|checked)\b`)
+
+// changeValueAttribute передает в hx-vals значение, которое обработчик onChange передает
+// сеттеру. Элементы без name не отправляют свое значение, поэтому значение из события
+// (e.target.value, e.target.checked) вычисляется в браузере при запросе, остальные
+// аргументы сеттера - при рендеринге
+func (c *JSXToHTMXConverter) changeValueAttribute(code string, setter string) string {
+	if match := eventValueRegex.FindStringSubmatch(code); match != nil {
+		return fmt.Sprintf(" hx-vals=\"js:{value: this.%s}\"", match[1])
+	}
+	return c.setterValueAttribute(code, setter)
+}
+
 // Вспомогательные функции
 
 // camelCaseToKebabCase преобразует camelCase в kebab-case
@@ -0,0 +1,348 @@
+package converter
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+	"sort"
+	"strings"
+)
+
+// Расширения, с которыми разрешаются относительные импорты (как в парсере)
+var importCandidates = []string{"", ".tsx", ".ts", ".jsx", ".js", "/index.tsx", "/index.ts", "/index.jsx", "/index.js"}
+
+// projectFile - файл проекта с разобранным компонентом
+type projectFile struct {
+	path      string
+	component *models.ReactComponent
+	imports   map[string]string // Локальное имя компонента -> путь файла, из которого он импортирован
+	defaults  map[string]bool   // Локальные имена импортов по умолчанию
+	contexts  map[string]string // Имя контекста, читаемого useContext -> путь файла, в котором он создается
+}
+
+// projectLoader обходит граф относительных импортов, начиная с входного файла
+type projectLoader struct {
+	converter *ReactToTemplConverter
+	root      string // Каталог проекта: импорты за его пределами не загружаются
+	files     map[string]*projectFile
+	loading   map[string]bool
+	order     []*projectFile // Зависимости раньше зависящих от них файлов (только файлы с разметкой)
+	warnings  []string
+}
+
+// ConvertProject конвертирует входной файл вместе с компонентами, импортированными из него
+// по относительным путям (рекурсивно). Зависимости конвертируются раньше: их пропсы
+// и состояние используются при вызове из зависящих файлов. Пакеты шаблонов повторяют
+// структуру каталогов исходников относительно общего корня. Контексты из других файлов
+// читаются через <Name>Value пакета создающего модуля; модуль без разметки не конвертируется,
+// и для его контекстов подставляется значение по умолчанию из createContext. Импорты загружаются только
+// из каталога проекта: options.ProjectRoot, если он задан, иначе каталога входного файла.
+// На время конвертации каждого файла опции изменяются: генераторы используют тот же указатель
+func (c *ReactToTemplConverter) ConvertProject(entryPath string, options *config.ConversionOptions) (*models.ProjectConversionResult, error) {
+	entry, err := filepath.Abs(entryPath)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка определения пути %s: %w", entryPath, err)
+	}
+
+	projectRoot := options.ProjectRoot
+	if projectRoot == "" {
+		projectRoot = filepath.Dir(entry)
+	}
+	if projectRoot, err = filepath.Abs(projectRoot); err != nil {
+		return nil, fmt.Errorf("ошибка определения пути %s: %w", options.ProjectRoot, err)
+	}
+
+	loader := &projectLoader{
+		converter: c,
+		root:      projectRoot,
+		files:     make(map[string]*projectFile),
+		loading:   make(map[string]bool),
+	}
+	if err := loader.load(entry); err != nil {
+		return nil, err
+	}
+	if loader.files[entry].component.JSX == nil {
+		return nil, fmt.Errorf("в %s нет разметки компонента", entryPath)
+	}
+
+	saved := *options
+	defer func() { *options = saved }()
+
+	rootPackage := saved.PackageName
+	if rootPackage == "" || rootPackage == "." {
+		rootPackage = "templates"
+	}
+	root := loader.rootDir()
+
+	result := &models.ProjectConversionResult{Entry: entryPath, Warnings: loader.warnings}
+	refs := make(map[string]*models.ComponentRef)
+
+	for _, file := range loader.order {
+		packagePath := rootPackage
+		if rel, err := filepath.Rel(root, filepath.Dir(file.path)); err == nil && rel != "." {
+			packagePath += "/" + filepath.ToSlash(rel)
+		}
+
+		*options = saved
+		options.PackagePath = packagePath
+		options.PackageName = goPackageName(path.Base(packagePath))
+		options.SourcePath = file.path
+		options.ProjectRoot = root
+		options.ComponentName = strings.TrimSuffix(filepath.Base(file.path), filepath.Ext(file.path))
+		options.Components = make(map[string]*models.ComponentRef)
+		localNames := make([]string, 0, len(file.imports))
+		for localName := range file.imports {
+			localNames = append(localNames, localName)
+		}
+		sort.Strings(localNames)
+
+		for _, localName := range localNames {
+			dependency := file.imports[localName]
+			ref := refs[dependency]
+			if ref == nil {
+				continue
+			}
+			// Из файла конвертируется один компонент: именованный импорт другого имени не разрешается
+			if !file.defaults[localName] && localName != ref.Name {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Компонент %s не найден в %s", localName, dependency))
+				continue
+			}
+			options.Components[localName] = ref
+		}
+
+		options.Contexts = make(map[string]*models.ContextRef)
+		contextNames := make([]string, 0, len(file.contexts))
+		for name := range file.contexts {
+			contextNames = append(contextNames, name)
+		}
+		sort.Strings(contextNames)
+
+		for _, name := range contextNames {
+			dependency := file.contexts[name]
+			definition, ok := createdContext(loader.files[dependency].component, name)
+			if !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Контекст %s не создается в %s", name, dependency))
+				continue
+			}
+			contextRef := &models.ContextRef{Definition: definition, SourceFile: dependency}
+			if ref := refs[dependency]; ref != nil {
+				contextRef.Package = ref.Package
+				contextRef.ImportPath = ref.ImportPath
+			}
+			options.Contexts[name] = contextRef
+		}
+
+		converted, err := c.convertParsed(file.component, options)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка конвертации %s: %w", file.path, err)
+		}
+		converted.ComponentName = file.component.Name
+		converted.PackagePath = packagePath
+		// Конфигурация Tailwind создается одна на проект
+		converted.TailwindConfig = ""
+		result.Files = append(result.Files, converted)
+		result.Classes = models.MergeClasses(result.Classes, converted.Classes)
+
+		ref := &models.ComponentRef{
+			Name:       file.component.Name,
+			Package:    options.PackageName,
+			ImportPath: options.TemplatesImportPath(),
+			Props:      file.component.Props,
+			HasState:   len(file.component.State) > 0 || len(file.component.Reducers) > 0,
+			SourceFile: file.path,
+		}
+		refs[file.path] = ref
+	}
+
+	if saved.TailwindConfig {
+		result.TailwindConfig = models.TailwindConfigSnippet(result.Classes)
+	}
+
+	return result, nil
+}
+
+// load разбирает файл и рекурсивно загружает файлы компонентов, которые он использует в JSX
+func (l *projectLoader) load(filePath string) error {
+	if l.files[filePath] != nil {
+		return nil
+	}
+
+	code, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения %s: %w", filePath, err)
+	}
+
+	component, err := l.converter.parse(string(code), filePath)
+	if err != nil {
+		return fmt.Errorf("ошибка парсинга %s: %w", filePath, err)
+	}
+
+	file := &projectFile{
+		path:      filePath,
+		component: component,
+		imports:   make(map[string]string),
+		defaults:  make(map[string]bool),
+		contexts:  make(map[string]string),
+	}
+	l.files[filePath] = file
+	l.loading[filePath] = true
+
+	used := usedComponents(component.JSX, nil)
+	read := readContexts(component)
+	for _, imp := range component.Imports {
+		if !strings.HasPrefix(imp.Source, ".") {
+			continue
+		}
+
+		// Следуем только за импортами компонентов, которые используются в разметке,
+		// и контекстов, которые читаются через useContext
+		var names, contexts []string
+		for _, name := range append([]string{imp.Defaults}, imp.Named...) {
+			if name != "" && used[name] {
+				names = append(names, name)
+			}
+			if name != "" && read[name] {
+				contexts = append(contexts, name)
+			}
+		}
+		if len(names) == 0 && len(contexts) == 0 {
+			continue
+		}
+
+		if !insideDir(l.root, filepath.Join(filepath.Dir(filePath), filepath.FromSlash(imp.Source))) {
+			l.warnings = append(l.warnings, fmt.Sprintf("Импорт %s в %s ведет за пределы каталога проекта", imp.Source, filePath))
+			continue
+		}
+		dependency := resolveImport(filepath.Dir(filePath), imp.Source)
+		if dependency == "" {
+			l.warnings = append(l.warnings, fmt.Sprintf("Не удалось разрешить импорт %s в %s", imp.Source, filePath))
+			continue
+		}
+		if l.loading[dependency] {
+			l.warnings = append(l.warnings, fmt.Sprintf("Циклический импорт %s в %s", imp.Source, filePath))
+			continue
+		}
+
+		if err := l.load(dependency); err != nil {
+			l.warnings = append(l.warnings, err.Error())
+			continue
+		}
+		for _, name := range names {
+			file.imports[name] = dependency
+		}
+		for _, name := range contexts {
+			file.contexts[name] = dependency
+		}
+		if imp.Defaults != "" {
+			file.defaults[imp.Defaults] = true
+		}
+	}
+
+	l.loading[filePath] = false
+	// Модуль без разметки (например, только с createContext) не конвертируется
+	if component.JSX != nil {
+		l.order = append(l.order, file)
+	}
+	return nil
+}
+
+// rootDir возвращает общий каталог всех загруженных файлов
+func (l *projectLoader) rootDir() string {
+	root := ""
+	for _, file := range l.order {
+		dir := filepath.Dir(file.path)
+		if root == "" {
+			root = dir
+			continue
+		}
+		for root != filepath.Dir(root) && dir != root && !strings.HasPrefix(dir, root+string(filepath.Separator)) {
+			root = filepath.Dir(root)
+		}
+	}
+	return root
+}
+
+// resolveImport находит файл относительного импорта; пустая строка, если файла нет
+func resolveImport(dir string, source string) string {
+	base := filepath.Join(dir, filepath.FromSlash(source))
+	for _, candidate := range importCandidates {
+		if info, err := os.Stat(base + filepath.FromSlash(candidate)); err == nil && !info.IsDir() {
+			return base + filepath.FromSlash(candidate)
+		}
+	}
+	return ""
+}
+
+// insideDir проверяет, что путь (после очистки) находится внутри каталога
+func insideDir(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// readContexts собирает имена контекстов, которые компонент читает, но не создает
+func readContexts(component *models.ReactComponent) map[string]bool {
+	read := make(map[string]bool)
+	for _, context := range component.Contexts {
+		if context.Variable != "" {
+			read[context.Name] = true
+		}
+	}
+	for _, context := range component.Contexts {
+		if context.Created {
+			delete(read, context.Name)
+		}
+	}
+	return read
+}
+
+// createdContext возвращает объявление контекста name, созданного в модуле компонента
+func createdContext(component *models.ReactComponent, name string) (models.ContextDefinition, bool) {
+	for _, context := range component.Contexts {
+		if context.Created && context.Name == name {
+			return context, true
+		}
+	}
+	return models.ContextDefinition{}, false
+}
+
+// usedComponents собирает имена пользовательских компонентов, используемых в разметке
+func usedComponents(jsx *models.JSXElement, used map[string]bool) map[string]bool {
+	if used == nil {
+		used = make(map[string]bool)
+	}
+	if jsx == nil {
+		return used
+	}
+
+	if len(jsx.Type) > 0 && jsx.Type[0] >= 'A' && jsx.Type[0] <= 'Z' && !jsx.IsFragment() && !strings.Contains(jsx.Type, ".") {
+		used[jsx.Type] = true
+	}
+	for _, child := range jsx.Children {
+		usedComponents(child, used)
+	}
+	_, trees := slotTrees(jsx)
+	for _, tree := range trees {
+		usedComponents(tree, used)
+	}
+
+	return used
+}
+
+// goPackageName приводит имя каталога к имени Go пакета
+func goPackageName(dir string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(dir) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+
+	name := sb.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "pkg" + name
+	}
+	return name
+}
@@ -0,0 +1,156 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+)
+
+// convertProject конвертирует проект testdata/project
+func convertProject(t *testing.T) *models.ProjectConversionResult {
+	t.Helper()
+
+	options := config.NewDefaultOptions()
+	result, err := newTestConverter(options).ConvertProject(filepath.Join("testdata", "project", "Page.json"), options)
+	if err != nil {
+		t.Fatalf("ошибка конвертации проекта: %v", err)
+	}
+	if len(result.Warnings) > 0 {
+		t.Errorf("предупреждения конвертации: %v", result.Warnings)
+	}
+	return result
+}
+
+// TestConvertProjectBuilds конвертирует проект testdata/project, сохраняет его через
+// SaveToFiles во временный модуль и собирает: пакеты шаблонов и контроллеров разных
+// каталогов, компоненты с одинаковыми состояниями в одном пакете
+func TestConvertProjectBuilds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("сборка проекта пропускается с -short")
+	}
+
+	dir := t.TempDir()
+	prepareBuildModule(t, dir)
+
+	result := convertProject(t)
+	if err := result.SaveToFiles(dir); err != nil {
+		t.Fatalf("ошибка сохранения проекта: %v", err)
+	}
+
+	for _, path := range []string{
+		"templates/page.templ", "templates/counter.templ", "templates/ui/label.templ",
+		"controllers/page_controller.go", "controllers/counter_controller.go", "project.classes.txt",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+			t.Errorf("файл %s не сохранен: %v", path, err)
+		}
+	}
+
+	templFiles, err := filepath.Glob(filepath.Join(dir, "templates", "*.templ"))
+	if err != nil {
+		t.Fatalf("ошибка поиска templ файлов: %v", err)
+	}
+	nested, _ := filepath.Glob(filepath.Join(dir, "templates", "*", "*.templ"))
+	for _, path := range append(templFiles, nested...) {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ошибка чтения %s: %v", path, err)
+		}
+		templGo, err := generateTemplGo(string(source))
+		if err != nil {
+			t.Fatalf("%s: ошибка генерации templ: %v", path, err)
+		}
+		writeModuleFile(t, strings.TrimSuffix(path, ".templ")+"_templ.go", templGo)
+	}
+
+	buildModule(t, dir)
+}
+
+// TestConvertProjectScopesCSSModulesByPath проверяет, что одноименные CSS модули разных
+// каталогов (Card.module.css и ui/Card.module.css) получают разные имена классов
+func TestConvertProjectScopesCSSModulesByPath(t *testing.T) {
+	scoped := regexp.MustCompile(`Card_card__[0-9a-f]{5}`)
+
+	classes := make(map[string]string)
+	for _, file := range convertProject(t).Files {
+		class := scoped.FindString(file.Stylesheet)
+		if class == "" {
+			continue
+		}
+		if !strings.Contains(file.TemplFile, class) {
+			t.Errorf("%s: класс %s таблицы стилей не выведен в разметку", file.ComponentName, class)
+		}
+		classes[file.ComponentName] = class
+	}
+
+	if classes["Counter"] == "" || classes["Label"] == "" {
+		t.Fatalf("классы CSS модулей не найдены: %v", classes)
+	}
+	if classes["Counter"] == classes["Label"] {
+		t.Errorf("классы модулей Card.module.css и ui/Card.module.css совпадают: %s", classes["Counter"])
+	}
+}
+
+// TestConvertProjectRejectsImportsOutsideRoot проверяет, что импорт, ведущий за пределы
+// каталога проекта, не читается и возвращается предупреждением
+func TestConvertProjectRejectsImportsOutsideRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFile(t, filepath.Join(dir, "Secret.json"), `{"name":"Secret","jsx":{"type":"p","props":{},"children":[]}}`)
+	writeModuleFile(t, filepath.Join(dir, "app", "Page.json"), `{"name":"Page",
+"imports":[{"source":"../Secret.json","default":"Secret"}],
+"jsx":{"type":"main","props":{},"children":[{"type":"Secret","props":{},"children":[]}]}}`)
+
+	options := config.NewDefaultOptions()
+	options.ProjectRoot = filepath.Join(dir, "app")
+	result, err := newTestConverter(options).ConvertProject(filepath.Join(dir, "app", "Page.json"), options)
+	if err != nil {
+		t.Fatalf("ошибка конвертации проекта: %v", err)
+	}
+
+	if len(result.Files) != 1 {
+		t.Errorf("загружен файл за пределами проекта: %d файлов", len(result.Files))
+	}
+	if !strings.Contains(strings.Join(result.Warnings, "\n"), "за пределы каталога проекта") {
+		t.Errorf("нет предупреждения об импорте за пределами проекта: %v", result.Warnings)
+	}
+}
+
+// TestConvertProjectReadsContextsFromOtherModules проверяет, что контекст из другого файла
+// проекта читается через <Name>Value его пакета, а контекст модуля без разметки - через
+// значение по умолчанию из createContext с предупреждением
+func TestConvertProjectReadsContextsFromOtherModules(t *testing.T) {
+	for _, file := range convertProject(t).Files {
+		if file.ComponentName == "Counter" && !strings.Contains(file.TemplFile, "return ui.ThemeContextValue(ctx)") {
+			t.Errorf("контекст ThemeContext не читается из пакета ui:\n%s", file.TemplFile)
+		}
+	}
+
+	dir := t.TempDir()
+	writeModuleFile(t, filepath.Join(dir, "sizes.json"), `{"name":"sizes",
+"contexts":[{"name":"SizeContext","type":"number","defaultValue":2,"created":true}]}`)
+	writeModuleFile(t, filepath.Join(dir, "Box.json"), `{"name":"Box",
+"contexts":[{"name":"SizeContext","variable":"size","type":"number"}],
+"imports":[{"source":"./sizes.json","named":["SizeContext"]}],
+"jsx":{"type":"div","props":{},"children":[{"type":"expression","props":{"content":"size"},"children":[]}]}}`)
+
+	options := config.NewDefaultOptions()
+	result, err := newTestConverter(options).ConvertProject(filepath.Join(dir, "Box.json"), options)
+	if err != nil {
+		t.Fatalf("ошибка конвертации проекта: %v", err)
+	}
+
+	if len(result.Files) != 1 {
+		t.Fatalf("модуль без разметки сконвертирован: %d файлов", len(result.Files))
+	}
+	if !strings.Contains(result.Files[0].TemplFile, "return 2\n") {
+		t.Errorf("нет значения по умолчанию из createContext:\n%s", result.Files[0].TemplFile)
+	}
+	if !strings.Contains(strings.Join(result.Files[0].Warnings, "\n"), "значение по умолчанию из createContext") {
+		t.Errorf("нет предупреждения о значении по умолчанию: %v", result.Files[0].Warnings)
+	}
+}
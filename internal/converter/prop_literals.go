@@ -0,0 +1,168 @@
+package converter
+
+import (
+	"fmt"
+	"net/url"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strings"
+)
+
+// Вызов функции или ссылка на нее в обработчике: setCount(count + 1), onSelect, props.onSelect(item)
+var handlerCallRegex = regexp.MustCompile(`^(?:props\.)?(\w+)(?:\(([\s\S]*)\))?;?$`)
+
+// componentPropNames упорядочивает пропсы вызова компонента: объявленные известным
+// компонентом - в порядке объявления, остальные - в порядке исходного кода
+func componentPropNames(propNames []string, ref *models.ComponentRef) []string {
+	if ref == nil {
+		return propNames
+	}
+
+	passed := make(map[string]bool, len(propNames))
+	for _, name := range propNames {
+		passed[name] = true
+	}
+
+	var names []string
+	for _, prop := range ref.Props {
+		if passed[prop.Name] {
+			names = append(names, prop.Name)
+		}
+	}
+	for _, name := range propNames {
+		if ref.FindProp(name) == nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// defaultedProps возвращает пропсы компонента проекта со значениями по умолчанию,
+// которые не переданы при вызове: структура пропсов получает их явно
+func defaultedProps(passed map[string]interface{}, ref *models.ComponentRef) []string {
+	if ref == nil {
+		return nil
+	}
+	var names []string
+	for _, prop := range ref.Props {
+		if _, ok := passed[prop.Name]; !ok && prop.DefaultValue != nil && prop.Name != "children" && !prop.Rest {
+			names = append(names, prop.Name)
+		}
+	}
+	return names
+}
+
+// propLiteral возвращает значение пропса как литерал Go типа поля пропса: строки
+// экранируются, числа, булевы значения, массивы и объекты-литералы приводятся к типу,
+// колбэки становятся URL действий родителя. Остальные выражения переводятся в Go
+func (c *JSXToHTMXConverter) propLiteral(name string, value interface{}, definition *models.PropDefinition) string {
+	goType := ""
+	if definition != nil {
+		goType = models.PropFieldType(*definition)
+	}
+
+	switch v := value.(type) {
+	case bool, string:
+		return models.GoLiteral(v, goType)
+	case map[string]interface{}:
+		code, ok := v["code"].(string)
+		if !ok {
+			return ""
+		}
+
+		// Колбэк: объявлен функцией или обработчик события без объявленного типа
+		if isEventProp(name) && (definition == nil || goType == "string") || definition != nil && models.IsCallbackType(definition.Type) {
+			return c.callbackActionURL(code)
+		}
+
+		if literal, ok := models.ParseJSLiteral(code); ok {
+			return models.GoLiteral(literal, goType)
+		}
+		return c.convertReactExpressionToGo(code)
+	}
+
+	return ""
+}
+
+// callbackActionURL переводит колбэк, передаваемый дочернему компоненту, в URL действия
+// родителя: вызов сеттера - в обработчик сеттера (с литеральным значением в value),
+// useCallback - в его обработчик, проброшенный колбэк-пропс передается дальше как есть
+func (c *JSXToHTMXConverter) callbackActionURL(code string) string {
+	body := strings.TrimSpace(arrowPrefixRegex.ReplaceAllString(strings.TrimSpace(code), ""))
+	body = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(body, "{"), "}"))
+
+	match := handlerCallRegex.FindStringSubmatch(body)
+	if match == nil || c.component == nil {
+		return `""`
+	}
+	name, args := match[1], strings.TrimSpace(match[2])
+	base := "/api/" + strings.ToLower(c.componentName()) + "/"
+
+	for _, state := range c.component.State {
+		if state.Setter != name {
+			continue
+		}
+
+		query := ""
+		if literal, ok := models.ParseJSLiteral(args); ok && args != "" {
+			query = "value=" + url.QueryEscape(strings.Trim(models.GoLiteral(literal, "string"), `"`)) + "&"
+		}
+		return fmt.Sprintf("%q + id", base+setterRoute(name)+"?"+query+"id=")
+	}
+
+	for _, callback := range c.component.Callbacks {
+		if callback.Name == name {
+			return fmt.Sprintf("%q + id", base+strings.ToLower(name)+"?id=")
+		}
+	}
+
+	// Колбэк-пропс самого компонента передается дочернему без изменений
+	for _, prop := range c.component.Props {
+		if prop.Name == name && isCallbackProp(prop) {
+			return "props." + exportedName(name)
+		}
+	}
+
+	return `""`
+}
+
+// convertCallbackPropEvent переводит обработчик события, вызывающий колбэк-пропс
+// (onClick={() => onSelect(item)}), в запрос к URL действия, переданному родителем.
+// Аргумент вызова передается в value
+func (c *JSXToHTMXConverter) convertCallbackPropEvent(name string, value interface{}) string {
+	valueExpr, ok := value.(map[string]interface{})
+	if !ok || !isEventProp(name) || c.component == nil {
+		return ""
+	}
+	code, _ := valueExpr["code"].(string)
+	body := strings.TrimSpace(arrowPrefixRegex.ReplaceAllString(strings.TrimSpace(code), ""))
+
+	match := handlerCallRegex.FindStringSubmatch(body)
+	if match == nil {
+		return ""
+	}
+
+	for _, prop := range c.component.Props {
+		if prop.Name != match[1] || !isCallbackProp(prop) {
+			continue
+		}
+
+		var sb strings.Builder
+		if name != "onClick" && name != "onSubmit" {
+			sb.WriteString(fmt.Sprintf(" hx-trigger=\"%s\"", strings.ToLower(name[2:])))
+		}
+		sb.WriteString(fmt.Sprintf(" hx-post={ props.%s }", exportedName(prop.Name)))
+		if args := splitTopLevel(match[2], ','); len(args) == 1 && strings.TrimSpace(args[0]) != "" {
+			sb.WriteString(fmt.Sprintf(" hx-vals={ templ.JSONString(map[string]interface{}{\"value\": %s}) }", c.convertReactExprToGoExpr(args[0])))
+		}
+		// Родитель обновляет свою разметку сам (hx-swap-oob)
+		sb.WriteString(" hx-swap=\"none\"")
+		return sb.String()
+	}
+	return ""
+}
+
+// isCallbackProp проверяет, передается ли в пропс колбэк (URL действия родителя)
+func isCallbackProp(prop models.PropDefinition) bool {
+	return models.PropFieldType(prop) == "string" && (models.IsCallbackType(prop.Type) || isEventProp(prop.Name))
+}
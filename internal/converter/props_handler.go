@@ -0,0 +1,81 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"strings"
+)
+
+// generatePropsDecoding генерирует получение пропсов из тела запроса в обработчике New<Name>:
+// незаполненные поля получают значения по умолчанию (New<Name>Props), а запрос без
+// обязательных пропсов отклоняется с 400 и списком отсутствующих полей
+func (h *StateHandler) generatePropsDecoding(sb *strings.Builder, component *models.ReactComponent) {
+	indent := h.getIndentation(1)
+
+	sb.WriteString(fmt.Sprintf("%s// Получаем пропсы из запроса\n", indent))
+	sb.WriteString(fmt.Sprintf("%sbody, err := io.ReadAll(r.Body)\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Ошибка чтения пропсов\", http.StatusBadRequest)\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+
+	if len(models.RequiredProps(component)) > 0 {
+		sb.WriteString(fmt.Sprintf("%sif missing := missing%sProps(body); len(missing) > 0 {\n", indent, component.Name))
+		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Не переданы обязательные пропсы: \"+strings.Join(missing, \", \"), http.StatusBadRequest)\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	sb.WriteString(fmt.Sprintf("%s%s\n", indent, h.propsDeclaration(component)))
+	sb.WriteString(fmt.Sprintf("%sif len(bytes.TrimSpace(body)) > 0 {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s%sif err := json.Unmarshal(body, &props); err != nil {\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%shttp.Error(w, \"Ошибка декодирования пропсов\", http.StatusBadRequest)\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%sreturn\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s}\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+}
+
+// generateMissingProps генерирует функцию missing<Name>Props, которая возвращает
+// обязательные пропсы, отсутствующие в теле запроса. Ключи сравниваются без учета
+// регистра, как при декодировании в структуру пропсов
+func (h *StateHandler) generateMissingProps(sb *strings.Builder, component *models.ReactComponent) {
+	required := models.RequiredProps(component)
+	if len(required) == 0 {
+		return
+	}
+
+	indent := h.getIndentation(1)
+	quoted := make([]string, len(required))
+	for i, name := range required {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+
+	sb.WriteString(fmt.Sprintf("// missing%sProps возвращает обязательные пропсы компонента %s, которых нет в теле запроса\n", component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("func missing%sProps(body []byte) []string {\n", component.Name))
+	sb.WriteString(fmt.Sprintf("%svar fields map[string]json.RawMessage\n", indent))
+	sb.WriteString(fmt.Sprintf("%s_ = json.Unmarshal(body, &fields)\n\n", indent))
+	sb.WriteString(fmt.Sprintf("%svar missing []string\n", indent))
+	sb.WriteString(fmt.Sprintf("%sfor _, name := range []string{%s} {\n", indent, strings.Join(quoted, ", ")))
+	sb.WriteString(fmt.Sprintf("%s%sfound := false\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%sfor key, value := range fields {\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%sif strings.EqualFold(key, name) && string(value) != \"null\" {\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%s%sfound = true\n", indent, indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%s%sbreak\n", indent, indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%s}\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s}\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%sif !found {\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%smissing = append(missing, name)\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s}\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	sb.WriteString(fmt.Sprintf("%sreturn missing\n", indent))
+	sb.WriteString("}\n\n")
+}
+
+// propsDeclaration возвращает объявление переменной props в обработчике: пропсы со
+// значениями по умолчанию, если они заданы, иначе нулевое значение структуры
+func (h *StateHandler) propsDeclaration(component *models.ReactComponent) string {
+	if models.HasPropDefaults(component) {
+		return fmt.Sprintf("props := %s()", h.qualifiedName("New"+component.Name+"Props"))
+	}
+	return fmt.Sprintf("var props %s", h.qualifiedName(component.Name+"Props"))
+}
@@ -0,0 +1,553 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	reducerIdentRegex  = regexp.MustCompile(`"[^"]*"|\b[A-Za-z_][\w.]*`)
+	dispatchCallRegex  = regexp.MustCompile(`\b(\w+)\(\s*\{\s*type:\s*['"]([^'"]+)['"]\s*(?:,\s*payload:\s*([^}]+?))?\s*\}\s*\)`)
+	reducerReturnRegex = regexp.MustCompile(`^return\s+([\s\S]+?);?$`)
+	payloadIdentRegex  = regexp.MustCompile(`(^|[^\w.$])([A-Za-z_$][\w$]*)`)
+	handlerParamRegex  = regexp.MustCompile(`^\s*(?:async\s+)?(?:\(\s*([A-Za-z_$][\w$]*)\s*(?::[^)]*)?\)|([A-Za-z_$][\w$]*))\s*=>`)
+)
+
+// Глобальные функции JavaScript, которые может вызывать payload, вычисляемый в браузере
+var browserPayloadGlobals = map[string]bool{
+	"this": true, "Number": true, "String": true, "Boolean": true, "parseInt": true, "parseFloat": true,
+	"Math": true, "true": true, "false": true, "null": true, "undefined": true,
+}
+
+// generateReducerTypes генерирует тип действия для редьюсеров компонента
+func (h *StateHandler) generateReducerTypes(sb *strings.Builder, component *models.ReactComponent) {
+	if len(component.Reducers) == 0 {
+		return
+	}
+
+	indent := h.getIndentation(1)
+
+	sb.WriteString(fmt.Sprintf("// %sAction описывает действие редьюсера компонента %s ({type, payload})\n", component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("type %sAction struct {\n", component.Name))
+	sb.WriteString(fmt.Sprintf("%sType    string      `json:\"type\"`\n", indent))
+	sb.WriteString(fmt.Sprintf("%sPayload interface{} `json:\"payload,omitempty\"`\n", indent))
+	sb.WriteString("}\n\n")
+}
+
+// generateReducerHandlers генерирует функции reduce и обработчики dispatch для редьюсеров
+func (h *StateHandler) generateReducerHandlers(sb *strings.Builder, component *models.ReactComponent) {
+	for i, reducer := range component.Reducers {
+		h.generateReduceFunction(sb, component, reducer, i)
+		h.generateDispatchHandler(sb, component, reducer, i)
+	}
+}
+
+// generateReduceFunction переводит функцию-редьюсер в Go: ветки switch по action.type
+// становятся присваиваниями полям состояния. Выражения читают поля из копии prev, чтобы
+// присваивание одного поля не меняло значение, которое читает следующее. Payload
+// неподходящего типа, неизвестное и непереведенное действие возвращаются как ошибка
+func (h *StateHandler) generateReduceFunction(sb *strings.Builder, component *models.ReactComponent, reducer models.ReducerDefinition, index int) {
+	indent := h.getIndentation(1)
+	indent2 := h.getIndentation(2)
+	indent3 := h.getIndentation(3)
+	funcName := reducerFuncName(component, index)
+
+	sb.WriteString(fmt.Sprintf("// %s переводит редьюсер %s: возвращает новое состояние для действия\n", funcName, reducer.Reducer))
+	sb.WriteString(fmt.Sprintf("func %s(state %sState, action %sAction) (%sState, error) {\n", funcName, component.Name, component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("%sswitch action.Type {\n", indent))
+
+	if len(reducer.Actions) == 0 {
+		sb.WriteString(fmt.Sprintf("%s// TODO: Действия редьюсера %s не найдены\n", indent, reducer.Reducer))
+	}
+
+	for _, action := range reducer.Actions {
+		sb.WriteString(fmt.Sprintf("%scase \"%s\":\n", indent, action.Type))
+
+		translated, ok := h.translateReducerAction(action, reducer)
+		if !ok {
+			// Непереведенное действие отклоняется, а не молча оставляет состояние прежним
+			sb.WriteString(fmt.Sprintf("%s// TODO: Переведите обработку действия \"%s\":\n", indent2, action.Type))
+			for _, line := range strings.Split(action.Body, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					sb.WriteString(fmt.Sprintf("%s// %s\n", indent2, line))
+				}
+			}
+			sb.WriteString(fmt.Sprintf("%sreturn state, fmt.Errorf(\"действие %%q не переведено\", action.Type)\n", indent2))
+			continue
+		}
+
+		if translated.payloadType != "" {
+			sb.WriteString(fmt.Sprintf("%spayload, ok := action.Payload.(%s)\n", indent2, translated.payloadType))
+			sb.WriteString(fmt.Sprintf("%sif !ok {\n", indent2))
+			sb.WriteString(fmt.Sprintf("%sreturn state, fmt.Errorf(\"действие %%q: ожидается payload типа %s\", action.Type)\n", indent3, translated.payloadType))
+			sb.WriteString(fmt.Sprintf("%s}\n", indent2))
+		}
+		if translated.readsState {
+			sb.WriteString(fmt.Sprintf("%sprev := state\n", indent2))
+		}
+		for _, statement := range translated.statements {
+			sb.WriteString(fmt.Sprintf("%s%s\n", indent2, statement))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("%sdefault:\n", indent))
+	sb.WriteString(fmt.Sprintf("%sreturn state, fmt.Errorf(\"неизвестное действие %%q\", action.Type)\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+	sb.WriteString(fmt.Sprintf("%sreturn state, nil\n", indent))
+	sb.WriteString("}\n\n")
+}
+
+// generateDispatchHandler генерирует обработчик dispatch, принимающий {type, payload}
+// как JSON или как поля формы (hx-vals)
+func (h *StateHandler) generateDispatchHandler(sb *strings.Builder, component *models.ReactComponent, reducer models.ReducerDefinition, index int) {
+	indent := h.getIndentation(1)
+	indent2 := h.getIndentation(2)
+	indent3 := h.getIndentation(3)
+	handlerName := dispatchHandlerName(component, reducer)
+
+	sb.WriteString(fmt.Sprintf("// %s обрабатывает действие редьюсера %s ({type, payload})\n", handlerName, reducer.Reducer))
+	sb.WriteString(fmt.Sprintf("func %s(w http.ResponseWriter, r *http.Request) {\n", handlerName))
+	sb.WriteString(fmt.Sprintf("%s// Получаем ID компонента из запроса\n", indent))
+	sb.WriteString(fmt.Sprintf("%sid := r.URL.Query().Get(\"id\")\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif id == \"\" {\n", indent))
+	sb.WriteString(fmt.Sprintf("%shttp.Error(w, \"ID компонента не указан\", http.StatusBadRequest)\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sreturn\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+
+	h.generateStateLoading(sb, component)
+
+	sb.WriteString(fmt.Sprintf("%s// Получаем действие из запроса: JSON {type, payload} или поля формы\n", indent))
+	sb.WriteString(fmt.Sprintf("%svar action %sAction\n", indent, component.Name))
+	sb.WriteString(fmt.Sprintf("%sif strings.HasPrefix(r.Header.Get(\"Content-Type\"), \"application/json\") {\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif err := json.NewDecoder(r.Body).Decode(&action); err != nil {\n", indent2))
+	sb.WriteString(fmt.Sprintf("%shttp.Error(w, \"Ошибка декодирования действия\", http.StatusBadRequest)\n", indent3))
+	sb.WriteString(fmt.Sprintf("%sreturn\n", indent3))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s} else {\n", indent))
+	sb.WriteString(fmt.Sprintf("%saction.Type = r.FormValue(\"type\")\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sif payload := r.FormValue(\"payload\"); payload != \"\" {\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sif err := json.Unmarshal([]byte(payload), &action.Payload); err != nil {\n", indent3))
+	sb.WriteString(fmt.Sprintf("%s%saction.Payload = payload\n", indent3, indent))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent3))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif action.Type == \"\" {\n", indent))
+	sb.WriteString(fmt.Sprintf("%shttp.Error(w, \"Тип действия не указан\", http.StatusBadRequest)\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sreturn\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+
+	// В Redis состояние хранится по значению, в памяти и БД - по указателю
+	funcName := reducerFuncName(component, index)
+	switch h.options.StatePersistence {
+	case "redis", "database":
+		current := "*state"
+		if h.options.StatePersistence == "redis" {
+			current = "state"
+		}
+		sb.WriteString(fmt.Sprintf("%snext, err := %s(%s, action)\n", indent, funcName, current))
+		generateReduceError(sb, indent, indent2)
+		h.generateStateSaving(sb, component, []string{current + " = next"})
+
+	default:
+		// Редьюсер вызывается под блокировкой, ошибка проверяется после ее снятия
+		h.generateStateSaving(sb, component, []string{
+			fmt.Sprintf("next, err := %s(*state, action)", funcName),
+			"if err == nil {",
+			indent + "*state = next",
+			"}",
+		})
+		generateReduceError(sb, indent, indent2)
+	}
+
+	h.generateRender(sb, component)
+	sb.WriteString("}\n\n")
+}
+
+// generateReduceError генерирует ответ 400 на действие, которое редьюсер не принял
+func generateReduceError(sb *strings.Builder, indent string, inner string) {
+	sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
+	sb.WriteString(fmt.Sprintf("%shttp.Error(w, err.Error(), http.StatusBadRequest)\n", inner))
+	sb.WriteString(fmt.Sprintf("%sreturn\n", inner))
+	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+}
+
+// reducerAction - переведенная ветка редьюсера: присваивания полям state, тип payload
+// (пустой, если payload не читается) и признак чтения старого состояния через prev
+type reducerAction struct {
+	statements  []string
+	payloadType string
+	readsState  bool
+}
+
+// translateReducerAction переводит ветку редьюсера вида
+// return { ...state, count: state.count + action.payload } в присваивания Go
+func (h *StateHandler) translateReducerAction(action models.ActionDefinition, reducer models.ReducerDefinition) (reducerAction, bool) {
+	var translated reducerAction
+
+	body := strings.TrimSpace(action.Body)
+	if strings.HasPrefix(body, "{") && strings.HasSuffix(body, "}") {
+		body = strings.TrimSpace(body[1 : len(body)-1])
+	}
+
+	match := reducerReturnRegex.FindStringSubmatch(body)
+	if match == nil {
+		return translated, false
+	}
+
+	result := strings.TrimSpace(match[1])
+	if strings.HasPrefix(result, "(") && strings.HasSuffix(result, ")") {
+		result = strings.TrimSpace(result[1 : len(result)-1])
+	}
+
+	// return state; - состояние не меняется
+	if result == reducer.StateParam {
+		return translated, true
+	}
+
+	// Скалярное состояние: return state + 1;
+	if !strings.HasPrefix(result, "{") {
+		field, ok := scalarReducerField(reducer)
+		if !ok {
+			return translated, false
+		}
+		if !h.translateReducerAssignment(&translated, field, result, reducer) {
+			return translated, false
+		}
+		return translated, true
+	}
+
+	for _, part := range splitTopLevel(result[1:len(result)-1], ',') {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "..."+reducer.StateParam {
+			continue
+		}
+
+		colon := strings.Index(part, ":")
+		if colon < 0 {
+			return translated, false
+		}
+
+		field := findReducerField(reducer, strings.TrimSpace(part[:colon]))
+		if field == nil {
+			return translated, false
+		}
+
+		if !h.translateReducerAssignment(&translated, *field, part[colon+1:], reducer) {
+			return translated, false
+		}
+	}
+
+	return translated, true
+}
+
+// translateReducerAssignment добавляет в ветку присваивание полю field значения expr.
+// Все поля одной ветки должны ожидать payload одного типа
+func (h *StateHandler) translateReducerAssignment(translated *reducerAction, field models.ReducerField, expr string, reducer models.ReducerDefinition) bool {
+	goType := h.convertTypeToGo(field.Type, field.InitialValue)
+	goExpr, readsPayload, ok := h.translateReducerExpression(expr, reducer)
+	if !ok {
+		return false
+	}
+
+	if readsPayload {
+		if translated.payloadType != "" && translated.payloadType != goType {
+			return false
+		}
+		translated.payloadType = goType
+	}
+	if strings.Contains(goExpr, "prev.") {
+		translated.readsState = true
+	}
+
+	translated.statements = append(translated.statements, fmt.Sprintf("state.%s = %s", exportedName(field.Name), goExpr))
+	return true
+}
+
+// translateReducerExpression переводит выражение редьюсера в Go: state.count -> prev.Count,
+// action.payload -> payload (значение, проверенное при извлечении из action.Payload).
+// Возвращает также признак чтения payload
+func (h *StateHandler) translateReducerExpression(expr string, reducer models.ReducerDefinition) (string, bool, bool) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" || !simpleGoExprRegex.MatchString(expr) {
+		return "", false, false
+	}
+
+	expr = strings.ReplaceAll(expr, "===", "==")
+	expr = strings.ReplaceAll(expr, "!==", "!=")
+	expr = stringLiteralsRegex.ReplaceAllString(expr, `"$1"`)
+
+	known := true
+	readsPayload := false
+	expr = reducerIdentRegex.ReplaceAllStringFunc(expr, func(ident string) string {
+		if strings.HasPrefix(ident, "\"") {
+			return ident
+		}
+
+		switch ident {
+		case "true", "false":
+			return ident
+		case "null", "undefined":
+			return "nil"
+		case reducer.StateParam:
+			if field, ok := scalarReducerField(reducer); ok {
+				return "prev." + exportedName(field.Name)
+			}
+		case reducer.ActionParam + ".payload":
+			readsPayload = true
+			return "payload"
+		}
+
+		if fieldName, ok := strings.CutPrefix(ident, reducer.StateParam+"."); ok {
+			if field := findReducerField(reducer, fieldName); field != nil {
+				return "prev." + exportedName(field.Name)
+			}
+		}
+
+		known = false
+		return ident
+	})
+
+	return expr, readsPayload, known
+}
+
+// convertDispatchToHtmx преобразует вызов dispatch({type, payload}) в обработчике события
+// в HTMX атрибуты. Возвращает пустую строку, если выражение не вызывает dispatch
+func (c *JSXToHTMXConverter) convertDispatchToHtmx(expr string) string {
+	if c.component == nil {
+		return ""
+	}
+
+	for _, match := range dispatchCallRegex.FindAllStringSubmatch(expr, -1) {
+		for _, reducer := range c.component.Reducers {
+			if reducer.Dispatch != match[1] {
+				continue
+			}
+
+			var sb strings.Builder
+			sb.WriteString(fmt.Sprintf(" hx-post={ \"/api/%s/%s?id=\" + id }", strings.ToLower(c.component.Name), reducer.Dispatch))
+			sb.WriteString(fmt.Sprintf(" hx-target={ \"#%s-\" + id }", c.componentName()))
+			sb.WriteString(" hx-swap=\"outerHTML\"")
+
+			// payload вычисляется при рендеринге: литерал передается как есть, выражение над
+			// состояниями и пропсами переводится в Go. Остальное не передается (см. dispatchWarnings)
+			// payload из данных события (Number(e.target.value)) вычисляется в браузере при запросе
+			payload := strings.TrimSpace(match[3])
+			if payload != "" && !isRenderPayload(c.component, payload) {
+				if js, ok := eventPayload(expr, payload); ok {
+					values := fmt.Sprintf("js:{type: %s, payload: %s}", strconv.Quote(match[2]), js)
+					sb.WriteString(fmt.Sprintf(" hx-vals={ %s }", strconv.Quote(values)))
+					return sb.String()
+				}
+			}
+
+			values := fmt.Sprintf("\"type\": %s", strconv.Quote(match[2]))
+			if payload != "" && isRenderPayload(c.component, payload) {
+				if literal, ok := models.ParseJSLiteral(payload); ok {
+					values += ", \"payload\": " + models.GoLiteral(literal, "")
+				} else {
+					values += ", \"payload\": " + c.convertReactExprToGoExpr(payload)
+				}
+			}
+			sb.WriteString(fmt.Sprintf(" hx-vals={ templ.JSONString(map[string]interface{}{%s}) }", values))
+
+			return sb.String()
+		}
+	}
+
+	return ""
+}
+
+// isRenderPayload проверяет, что payload вызова dispatch вычисляется при рендеринге:
+// скалярный литерал или выражение, которое читает только состояния, поля редьюсеров и
+// пропсы. Данные события (e.target.value) и локальные переменные шаблону недоступны
+func isRenderPayload(component *models.ReactComponent, payload string) bool {
+	if literal, ok := models.ParseJSLiteral(payload); ok {
+		switch literal.(type) {
+		case string, float64, bool, nil:
+			return true
+		}
+		return false
+	}
+
+	known := make(map[string]bool)
+	for _, name := range []string{"props", "true", "false", "null", "undefined"} {
+		known[name] = true
+	}
+	for _, state := range component.State {
+		known[state.Name] = true
+	}
+	for _, reducer := range component.Reducers {
+		known[reducer.Name] = true
+	}
+	for _, prop := range component.Props {
+		known[prop.Name] = true
+	}
+
+	renderable := !strings.ContainsAny(payload, "`{}[]=")
+	replaceOutsideStrings(payload, func(code string) string {
+		for _, match := range payloadIdentRegex.FindAllStringSubmatch(code, -1) {
+			if !known[match[2]] {
+				renderable = false
+			}
+		}
+		return code
+	})
+	return renderable
+}
+
+// eventPayload переводит payload, который читает значение элемента из события обработчика
+// (Number(e.target.value)), в выражение JavaScript для hx-vals="js:{...}": e.target и
+// e.currentTarget заменяются на this - элемент, отправляющий запрос. Другие данные события
+// и переменные компонента в браузере недоступны
+func eventPayload(handler string, payload string) (string, bool) {
+	match := handlerParamRegex.FindStringSubmatch(handler)
+	if match == nil || strings.ContainsAny(payload, "`=") {
+		return "", false
+	}
+	param := match[1] + match[2]
+
+	targetRegex := regexp.MustCompile(`(^|[^\w.$])` + regexp.QuoteMeta(param) + `\.(?:target|currentTarget)\b`)
+	js := replaceOutsideStrings(payload, func(code string) string {
+		return targetRegex.ReplaceAllString(code, "${1}this")
+	})
+	if js == payload {
+		return "", false
+	}
+
+	browser := true
+	replaceOutsideStrings(js, func(code string) string {
+		for _, ident := range payloadIdentRegex.FindAllStringSubmatch(code, -1) {
+			if !browserPayloadGlobals[ident[2]] {
+				browser = false
+			}
+		}
+		return code
+	})
+	return js, browser
+}
+
+// dispatchWarnings сообщает о вызовах dispatch, payload которых не передается в hx-vals:
+// действие приходит в обработчик без payload, и ветка, которая его читает, отвечает ошибкой 400
+func dispatchWarnings(component *models.ReactComponent, jsx *models.JSXElement) []string {
+	if jsx == nil || len(component.Reducers) == 0 {
+		return nil
+	}
+
+	var warnings []string
+	for _, name := range jsx.PropNames() {
+		valueExpr, ok := jsx.Props[name].(map[string]interface{})
+		if !ok || !isEventProp(name) {
+			continue
+		}
+		code, _ := valueExpr["code"].(string)
+		for _, match := range dispatchCallRegex.FindAllStringSubmatch(resolveHandlerCode(code, component), -1) {
+			for _, reducer := range component.Reducers {
+				payload := strings.TrimSpace(match[3])
+				if reducer.Dispatch != match[1] || payload == "" || isRenderPayload(component, payload) {
+					continue
+				}
+				if _, ok := eventPayload(resolveHandlerCode(code, component), payload); ok {
+					continue
+				}
+				warnings = append(warnings, fmt.Sprintf("Payload действия \"%s\" (%s) не передается в %s: он читает значения, недоступные при рендеринге. Передайте его в hx-vals вручную, до этого ветка, читающая payload, отвечает ошибкой 400", match[2], payload, dispatchHandlerName(component, reducer)))
+			}
+		}
+	}
+	for _, child := range jsx.Children {
+		warnings = append(warnings, dispatchWarnings(component, child)...)
+	}
+	_, trees := slotTrees(jsx)
+	for _, tree := range trees {
+		warnings = append(warnings, dispatchWarnings(component, tree)...)
+	}
+	return warnings
+}
+
+// dispatchHandlerName возвращает имя обработчика dispatch: с именем компонента,
+// чтобы обработчики компонентов одного пакета не совпадали
+func dispatchHandlerName(component *models.ReactComponent, reducer models.ReducerDefinition) string {
+	return component.Name + exportedName(reducer.Dispatch)
+}
+
+// reducerWarnings сообщает о действиях редьюсеров, которые не удалось перевести в Go:
+// обработчик dispatch отвечает на них ошибкой 400
+func (h *StateHandler) reducerWarnings(component *models.ReactComponent) []string {
+	var warnings []string
+	for i, reducer := range component.Reducers {
+		if len(reducer.Actions) == 0 {
+			warnings = append(warnings, fmt.Sprintf("Действия редьюсера %s не найдены: %s отклоняет любое действие", reducer.Reducer, reducerFuncName(component, i)))
+			continue
+		}
+		for _, action := range reducer.Actions {
+			if _, ok := h.translateReducerAction(action, reducer); !ok {
+				warnings = append(warnings, fmt.Sprintf("Действие \"%s\" редьюсера %s не переведено: допишите ветку в %s, до этого %s отвечает на него ошибкой 400", action.Type, reducer.Reducer, reducerFuncName(component, i), dispatchHandlerName(component, reducer)))
+			}
+		}
+	}
+	return warnings
+}
+
+// reducerFuncName возвращает имя Go функции reduce для редьюсера
+func reducerFuncName(component *models.ReactComponent, index int) string {
+	if len(component.Reducers) > 1 {
+		return "reduce" + component.Name + exportedName(component.Reducers[index].Name)
+	}
+	return "reduce" + component.Name
+}
+
+// scalarReducerField возвращает единственное поле скалярного состояния редьюсера
+func scalarReducerField(reducer models.ReducerDefinition) (models.ReducerField, bool) {
+	if len(reducer.Fields) == 1 && reducer.Fields[0].Name == reducer.Name {
+		return reducer.Fields[0], true
+	}
+	return models.ReducerField{}, false
+}
+
+// findReducerField ищет поле состояния редьюсера по имени
+func findReducerField(reducer models.ReducerDefinition, name string) *models.ReducerField {
+	for i := range reducer.Fields {
+		if reducer.Fields[i].Name == name {
+			return &reducer.Fields[i]
+		}
+	}
+	return nil
+}
+
+// exportedName делает первую букву имени заглавной
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// splitTopLevel разбивает строку по разделителю, не заходя внутрь скобок и строк
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+	depth := 0
+	var quote rune
+	start := 0
+
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
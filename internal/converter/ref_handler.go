@@ -0,0 +1,262 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	refCallRegex     = regexp.MustCompile(`\b(\w+)\.current\??\.(focus|blur|select|click|scrollIntoView)\(([^()]*)\)`)
+	arrowPrefixRegex = regexp.MustCompile(`^\(?[\w\s,]*\)?\s*=>\s*`)
+	refNameRegex     = regexp.MustCompile(`^\w+$`)
+)
+
+// refOperation описывает императивный вызов через DOM ref: inputRef.current.focus()
+type refOperation struct {
+	Ref    string
+	Method string
+	Args   string
+}
+
+// isDOMRef проверяет, привязан ли ref к элементу через ref={name}
+func isDOMRef(component *models.ReactComponent, name string) bool {
+	return jsxHasRef(component.JSX, name)
+}
+
+// jsxHasRef рекурсивно ищет атрибут ref={name} в JSX
+func jsxHasRef(jsx *models.JSXElement, name string) bool {
+	if jsx == nil {
+		return false
+	}
+
+	if value, ok := jsx.Props["ref"].(map[string]interface{}); ok {
+		if code, ok := value["code"].(string); ok && strings.TrimSpace(code) == name {
+			return true
+		}
+	}
+
+	for _, child := range jsx.Children {
+		if jsxHasRef(child, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// instanceRefs возвращает refs, используемые как изменяемые переменные экземпляра (не DOM)
+func instanceRefs(component *models.ReactComponent) []models.RefDefinition {
+	var refs []models.RefDefinition
+	for _, ref := range component.Refs {
+		if !isDOMRef(component, ref.Name) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// refElementID возвращает стабильный id элемента с DOM ref (без id экземпляра)
+func refElementID(componentName string, refName string) string {
+	return fmt.Sprintf("%s-%s", componentName, refName)
+}
+
+// findRefOperations находит императивные вызовы через DOM refs компонента
+func findRefOperations(code string, component *models.ReactComponent) []refOperation {
+	var operations []refOperation
+
+	for _, match := range refCallRegex.FindAllStringSubmatch(code, -1) {
+		if !isDOMRef(component, match[1]) {
+			continue
+		}
+		operations = append(operations, refOperation{Ref: match[1], Method: match[2], Args: strings.TrimSpace(match[3])})
+	}
+
+	return operations
+}
+
+// isRefOnlyCode проверяет, состоит ли код только из вызовов через refs
+// (такой обработчик или эффект целиком выполняется на клиенте)
+func isRefOnlyCode(code string) bool {
+	code = strings.TrimSpace(arrowPrefixRegex.ReplaceAllString(strings.TrimSpace(code), ""))
+	if !refCallRegex.MatchString(code) {
+		return false
+	}
+
+	rest := refCallRegex.ReplaceAllString(code, "")
+	return strings.Trim(rest, " \t\r\n;{}") == ""
+}
+
+// isClientCallback проверяет, выполняется ли колбэк целиком на клиенте
+// (только DOM refs или только сеттеры клиентских состояний)
+func isClientCallback(component *models.ReactComponent, callback models.CallbackDefinition) bool {
+	if isRefOnlyCode(callback.Body) {
+		return true
+	}
+	expr, clientOnly := translateAlpineHandler(callback.Body, component)
+	return expr != "" && clientOnly
+}
+
+// markDOMEffects помечает эффекты, которые только обращаются к DOM refs
+// (например, фокус при монтировании): они выполняются на клиенте без обработчика
+func markDOMEffects(component *models.ReactComponent) {
+	for i, effect := range component.Effects {
+		if (effect.Kind == "" || effect.Kind == models.EffectKindGeneric) && isRefOnlyCode(effect.Body) &&
+			len(findRefOperations(effect.Body, component)) > 0 {
+			component.Effects[i].Kind = models.EffectKindDOM
+		}
+	}
+}
+
+// resolveHandlerCode возвращает код обработчика события; если обработчик ссылается
+// на колбэк компонента (onClick={handleFocus}), возвращается тело колбэка
+func resolveHandlerCode(code string, component *models.ReactComponent) string {
+	name := strings.TrimSpace(arrowPrefixRegex.ReplaceAllString(strings.TrimSpace(code), ""))
+	name = strings.TrimSuffix(name, "()")
+
+	for _, callback := range component.Callbacks {
+		if callback.Name == name {
+			return callback.Body
+		}
+	}
+
+	return code
+}
+
+// refDataAttribute возвращает data-атрибут, в котором элементу с обработчиком или скрипту
+// передается точный id элемента с ref: inputRef -> data-ref-input-ref
+func refDataAttribute(refName string) string {
+	return "data-ref-" + camelCaseToKebabCase(refName)
+}
+
+// refOperationsJS формирует JavaScript для вызовов через refs. Элемент ищется по точному id
+// из data-атрибута элемента owner (this для обработчика, script для скрипта эффекта)
+func refOperationsJS(operations []refOperation, owner string) []string {
+	var statements []string
+	for _, op := range operations {
+		statements = append(statements, fmt.Sprintf("document.getElementById(%s.dataset.ref%s)?.%s(%s);",
+			owner, exportedName(op.Ref), op.Method, op.Args))
+	}
+	return statements
+}
+
+// refIDAttributes выводит data-атрибуты с точными id элементов, к которым обращаются вызовы
+func (c *JSXToHTMXConverter) refIDAttributes(operations []refOperation) string {
+	var sb strings.Builder
+	seen := make(map[string]bool)
+	for _, op := range operations {
+		if seen[op.Ref] {
+			continue
+		}
+		seen[op.Ref] = true
+		sb.WriteString(c.refIDAttribute(refDataAttribute(op.Ref), refElementID(c.componentName(), op.Ref)))
+	}
+	return sb.String()
+}
+
+// refIDAttribute выводит атрибут с id элемента компонента: в режиме HTMX id включает id экземпляра
+func (c *JSXToHTMXConverter) refIDAttribute(attrName string, elementID string) string {
+	if c.options.UseHtmx {
+		return fmt.Sprintf(" %s={ \"%s-\" + id }", attrName, elementID)
+	}
+	return fmt.Sprintf(" %s=\"%s\"", attrName, elementID)
+}
+
+// convertRefAttribute преобразует ref={inputRef} в стабильный id элемента
+func (c *JSXToHTMXConverter) convertRefAttribute(value interface{}) string {
+	valueExpr, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	refName, _ := valueExpr["code"].(string)
+	refName = strings.TrimSpace(refName)
+	if !refNameRegex.MatchString(refName) {
+		return ""
+	}
+
+	return c.refIDAttribute("id", refElementID(c.componentName(), refName))
+}
+
+// convertRefHandler преобразует вызовы через refs в обработчике события в hx-on атрибут
+// с постоянным кодом: точные id элементов передаются data-атрибутами элемента.
+// Возвращает атрибуты и признак того, что обработчик целиком клиентский
+func (c *JSXToHTMXConverter) convertRefHandler(name string, value interface{}) (string, bool) {
+	if c.component == nil {
+		return "", false
+	}
+
+	valueExpr, ok := value.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	code, ok := valueExpr["code"].(string)
+	if !ok {
+		return "", false
+	}
+
+	code = resolveHandlerCode(code, c.component)
+	operations := findRefOperations(code, c.component)
+	if len(operations) == 0 {
+		return "", false
+	}
+
+	event := strings.ToLower(name[2:])
+	script := strings.Join(refOperationsJS(operations, "this"), " ")
+	handler := fmt.Sprintf(" hx-on:%s=\"%s\"", event, script)
+	if strings.Contains(script, `"`) {
+		// templ разбирает выражение в hx-on как templ.ComponentScript
+		handler = fmt.Sprintf(" hx-on:%s={ templ.JSUnsafeFuncCall(%s) }", event, strconv.Quote(script))
+	}
+	return c.refIDAttributes(operations) + handler, isRefOnlyCode(code)
+}
+
+// convertRefEffects генерирует скрипт для эффектов, работающих только с DOM refs.
+// Скрипт размещается в конце корневого элемента и выполняется при каждой вставке
+// компонента; эффекты без зависимостей выполняются только при первой вставке экземпляра
+func (c *JSXToHTMXConverter) convertRefEffects(indent int) string {
+	if c.component == nil {
+		return ""
+	}
+
+	var mount, update []refOperation
+	for _, effect := range c.component.Effects {
+		if effect.Kind != models.EffectKindDOM {
+			continue
+		}
+		if len(effect.Dependencies) == 0 {
+			mount = append(mount, findRefOperations(effect.Body, c.component)...)
+		} else {
+			update = append(update, findRefOperations(effect.Body, c.component)...)
+		}
+	}
+
+	if len(mount) == 0 && len(update) == 0 {
+		return ""
+	}
+
+	indentation := strings.Repeat("\t", indent)
+	var sb strings.Builder
+	sb.WriteString(indentation + "<script")
+	sb.WriteString(c.refIDAttribute("data-component", c.componentName()))
+	sb.WriteString(c.refIDAttributes(append(append([]refOperation{}, mount...), update...)))
+	sb.WriteString(">\n")
+	sb.WriteString(indentation + "\t(function(script) {\n")
+	if len(mount) > 0 {
+		sb.WriteString(indentation + "\t\tvar mounted = window.mountedComponents = window.mountedComponents || {};\n")
+		sb.WriteString(indentation + "\t\tif (!mounted[script.dataset.component]) {\n")
+		sb.WriteString(indentation + "\t\t\tmounted[script.dataset.component] = true;\n")
+		for _, statement := range refOperationsJS(mount, "script") {
+			sb.WriteString(indentation + "\t\t\t" + statement + "\n")
+		}
+		sb.WriteString(indentation + "\t\t}\n")
+	}
+	for _, statement := range refOperationsJS(update, "script") {
+		sb.WriteString(indentation + "\t\t" + statement + "\n")
+	}
+	sb.WriteString(indentation + "\t})(document.currentScript);\n")
+	sb.WriteString(indentation + "</script>\n")
+	return sb.String()
+}
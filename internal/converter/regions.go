@@ -0,0 +1,439 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strings"
+)
+
+// renderRegion описывает независимо обновляемый фрагмент разметки: HTML элемент,
+// который читает серверные состояния. Фрагмент выносится в отдельный templ
+// компонент со стабильным id, и сеттеры перерисовывают только его
+type renderRegion struct {
+	Index   int // Номер фрагмента в порядке документа (с единицы)
+	Element *models.JSXElement
+	Parent  *renderRegion // Ближайший объемлющий фрагмент
+	States  []string      // Состояния, которые фрагмент читает напрямую
+	Writes  []string      // Состояния, которые элемент фрагмента меняет сам (поле ввода)
+	Ref     string        // DOM ref элемента: его id становится id фрагмента
+}
+
+// regionAnalysis содержит фрагменты компонента и состояния, при изменении
+// которых нужно перерисовывать компонент целиком (их читает корневой элемент)
+type regionAnalysis struct {
+	Regions   []*renderRegion
+	Whole     map[string]bool
+	component *models.ReactComponent
+}
+
+// findRenderRegions находит фрагменты разметки, зависящие от серверных состояний.
+// Чтение состояния принадлежит ближайшему HTML элементу; элемент становится
+// фрагментом, если он не корневой и у него нет своего id. Элемент с DOM ref
+// становится фрагментом с id ref
+func findRenderRegions(component *models.ReactComponent) *regionAnalysis {
+	analysis := &regionAnalysis{Whole: make(map[string]bool), component: component}
+	if component == nil || component.JSX == nil || len(component.State) == 0 {
+		return analysis
+	}
+
+	var walk func(jsx *models.JSXElement, region *renderRegion)
+	walk = func(jsx *models.JSXElement, region *renderRegion) {
+		if isHTMLTag(jsx.Type) || jsx == component.JSX {
+			reads := ownedStateReads(component, jsx)
+			writes := writtenStates(component, jsx)
+			switch {
+			case len(reads) == 0:
+			case jsx != component.JSX && canBeRegion(jsx):
+				region = &renderRegion{Index: len(analysis.Regions) + 1, Element: jsx, Parent: region, States: reads, Writes: writes}
+				region.Ref, _ = refName(jsx)
+				analysis.Regions = append(analysis.Regions, region)
+			default:
+				// Элемент не может быть фрагментом: перерисовка объемлющего фрагмента или
+				// всего компонента сбросила бы фокус поля ввода, которое меняет свое состояние
+				if jsx != component.JSX {
+					reads = subtractStrings(reads, writes)
+				}
+				if jsx != component.JSX && region != nil {
+					region.States = appendUnique(region.States, reads...)
+					break
+				}
+				for _, name := range reads {
+					analysis.Whole[name] = true
+				}
+			}
+		}
+
+		for _, child := range jsx.Children {
+			walk(child, region)
+		}
+	}
+	walk(component.JSX, nil)
+
+	return analysis
+}
+
+// ownedStateReads возвращает состояния, которые читает элемент и его дочерние узлы,
+// не являющиеся HTML элементами (выражения, вызовы компонентов, фрагменты),
+// а также варианты select
+func ownedStateReads(component *models.ReactComponent, jsx *models.JSXElement) []string {
+	reads := directStateReads(component, jsx)
+	for _, child := range jsx.Children {
+		if !isHTMLTag(child.Type) || isSelectOption(child) {
+			reads = appendUnique(reads, ownedStateReads(component, child)...)
+		}
+	}
+	return reads
+}
+
+// directStateReads возвращает серверные состояния, которые узел читает в своих атрибутах
+// или содержимом выражения, включая аргументы сеттеров в обработчиках: значение
+// setCount(count + 1) вычисляется при рендеринге и передается в hx-vals
+func directStateReads(component *models.ReactComponent, jsx *models.JSXElement) []string {
+	var reads []string
+	for _, state := range component.State {
+		// Неизменяемое состояние не перерисовывается: фрагмент ему не нужен
+		if state.Kind == models.StateKindReadOnly {
+			continue
+		}
+
+		// Разметку из пропсов рендерит дочерний компонент: ее чтения принадлежат элементу
+		_, trees := slotTrees(jsx)
+		slotReads := false
+		for _, tree := range trees {
+			slotReads = slotReads || jsxReadsState(tree, state.Name)
+		}
+		if slotReads {
+			reads = append(reads, state.Name)
+			continue
+		}
+
+		for _, prop := range jsx.PropNames() {
+			value := jsx.Props[prop]
+			code := ""
+			switch v := value.(type) {
+			case string:
+				// Условный рендеринг читает только условие: содержимое - дочерние узлы
+				_, conditional := jsx.Props["condition"]
+				if jsx.Type == "expression" && (prop == "condition" || prop == "content" && !conditional) {
+					code = v
+				}
+			case map[string]interface{}:
+				code, _ = v["code"].(string)
+				if isEventProp(prop) {
+					code = setterArguments(resolveHandlerCode(code, component), component)
+				}
+			}
+			if code != "" && readsState(code, state.Name) {
+				reads = append(reads, state.Name)
+				break
+			}
+		}
+	}
+
+	return reads
+}
+
+// setterArguments возвращает аргументы вызовов сеттеров в обработчике, которые
+// передаются в hx-vals значением на момент рендеринга (без функциональных обновлений)
+func setterArguments(code string, component *models.ReactComponent) string {
+	var args []string
+	for _, match := range setterCallRegex(component).FindAllStringSubmatch(code, -1) {
+		if !strings.Contains(match[2], "=>") {
+			args = append(args, match[2])
+		}
+	}
+	return strings.Join(args, "; ")
+}
+
+// writtenStates возвращает серверные состояния, которые элемент меняет своими обработчиками
+func writtenStates(component *models.ReactComponent, jsx *models.JSXElement) []string {
+	var written []string
+	for _, prop := range jsx.PropNames() {
+		valueExpr, ok := jsx.Props[prop].(map[string]interface{})
+		if !ok || !isEventProp(prop) {
+			continue
+		}
+		code, _ := valueExpr["code"].(string)
+		code = resolveHandlerCode(code, component)
+		for _, state := range component.State {
+			if callsSetter(code, state.Setter) {
+				written = appendUnique(written, state.Name)
+			}
+		}
+	}
+	return written
+}
+
+// refName возвращает имя DOM ref элемента (ref={inputRef})
+func refName(jsx *models.JSXElement) (string, bool) {
+	valueExpr, ok := jsx.Props["ref"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	name, _ := valueExpr["code"].(string)
+	name = strings.TrimSpace(name)
+	return name, refNameRegex.MatchString(name)
+}
+
+// canBeRegion проверяет, можно ли вынести элемент во фрагмент со стабильным id:
+// свой id элемента одинаков у всех экземпляров, id элемента с ref включает id экземпляра.
+// Варианты select перерисовываются вместе с select
+func canBeRegion(jsx *models.JSXElement) bool {
+	if isSelectOption(jsx) {
+		return false
+	}
+	if _, hasID := jsx.Props["id"]; hasID {
+		return false
+	}
+	if _, hasRef := jsx.Props["ref"]; hasRef {
+		_, ok := refName(jsx)
+		return ok
+	}
+	return true
+}
+
+// changedStates возвращает состояние и производные состояния, которые пересчитываются
+// при его изменении (см. withDerivedUpdates)
+func changedStates(component *models.ReactComponent, name string) []string {
+	changed := []string{name}
+	for _, statement := range withDerivedUpdates(component, []string{"state." + exportedName(name) + " = newValue"}) {
+		if match := stateAssignmentRegex.FindStringSubmatch(statement); match != nil {
+			for _, state := range component.State {
+				if exportedName(state.Name) == match[1] {
+					changed = appendUnique(changed, state.Name)
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// regionsForState возвращает фрагменты, которые нужно перерисовать при изменении
+// состояния и пересчитанных из него производных состояний (без вложенных в другие
+// такие же фрагменты), и признак того, что перерисовать нужно весь компонент
+func (a *regionAnalysis) regionsForState(name string) ([]*renderRegion, bool) {
+	changed := changedStates(a.component, name)
+	for _, state := range changed {
+		if a.Whole[state] {
+			return nil, true
+		}
+	}
+
+	affected := make(map[*renderRegion]bool)
+	for _, region := range a.Regions {
+		for _, state := range changed {
+			if containsString(region.States, state) {
+				affected[region] = true
+			}
+		}
+	}
+
+	var regions []*renderRegion
+	for _, region := range a.Regions {
+		if !affected[region] {
+			continue
+		}
+		nested := false
+		for parent := region.Parent; parent != nil; parent = parent.Parent {
+			if affected[parent] {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			regions = append(regions, region)
+		}
+	}
+
+	return regions, false
+}
+
+// find возвращает фрагмент для JSX элемента
+func (a *regionAnalysis) find(jsx *models.JSXElement) *renderRegion {
+	for _, region := range a.Regions {
+		if region.Element == jsx {
+			return region
+		}
+	}
+	return nil
+}
+
+// regionFuncName возвращает имя templ компонента фрагмента. Имя экспортируется:
+// контроллеры рендерят фрагменты из своего пакета
+func regionFuncName(component *models.ReactComponent, index int) string {
+	return fmt.Sprintf("%sRegion%d", exportedName(component.Name), index)
+}
+
+// regionElementID возвращает префикс стабильного id фрагмента: id элемента с ref
+// или номер фрагмента
+func regionElementID(componentName string, region *renderRegion) string {
+	if region.Ref != "" {
+		return refElementID(componentName, region.Ref)
+	}
+	return fmt.Sprintf("%s-r%d", componentName, region.Index)
+}
+
+// instanceIDPattern возвращает регулярное выражение JavaScript, которое выделяет id
+// экземпляра из id корневого элемента компонента и не совпадает с id фрагментов и
+// элементов с DOM ref (см. regionElementID)
+func instanceIDPattern(component *models.ReactComponent) string {
+	prefixes := []string{`r\d+`}
+	for _, ref := range component.Refs {
+		if isDOMRef(component, ref.Name) {
+			prefixes = append(prefixes, regexp.QuoteMeta(ref.Name))
+		}
+	}
+	return fmt.Sprintf(`/^%s-(?!(?:%s)-)(.+)$/`, regexp.QuoteMeta(component.Name), strings.Join(prefixes, "|"))
+}
+
+// subtractStrings возвращает значения, которых нет в exclude
+func subtractStrings(values []string, exclude []string) []string {
+	var result []string
+	for _, value := range values {
+		if !containsString(exclude, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// isHTMLTag проверяет, является ли тип узла HTML элементом (с маленькой буквы)
+func isHTMLTag(tag string) bool {
+	return tag != "expression" && tag != "text" && len(tag) > 0 && tag[0] >= 'a' && tag[0] <= 'z'
+}
+
+// containsString проверяет, содержит ли срез строку
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUnique добавляет в срез значения, которых в нем еще нет
+func appendUnique(values []string, items ...string) []string {
+	for _, item := range items {
+		if !containsString(values, item) {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// convertRegionCall заменяет элемент фрагмента вызовом его templ компонента,
+// а сам фрагмент генерирует отдельным компонентом (см. RegionTemplates)
+func (c *JSXToHTMXConverter) convertRegionCall(jsx *models.JSXElement, region *renderRegion, indent int) string {
+	indentation := strings.Repeat("\t", indent)
+	name := regionFuncName(c.component, region.Index)
+
+	// Фрагмент конвертируется с отступом корня, но без атрибутов корневого элемента
+	savedRegion, savedIndent, savedPending := c.currentRegion, c.indent, c.pendingAttrs
+	c.currentRegion = jsx
+	c.pendingAttrs = " if oob { hx-swap-oob=\"true\" }" + savedPending
+	if region.Ref == "" {
+		// Элементу с ref id задает сам ref
+		c.pendingAttrs = fmt.Sprintf(" id={ \"%s-\" + id }", regionElementID(c.componentName(), region)) + c.pendingAttrs
+	}
+	body := c.ConvertJSXToTempl(jsx, 1)
+	c.currentRegion, c.indent, c.pendingAttrs = savedRegion, savedIndent, ""
+
+	params := "oob bool"
+	if c.templParams != "" {
+		params = c.templParams + ", " + params
+	}
+	c.regionTemplates = append(c.regionTemplates,
+		fmt.Sprintf("// %s - фрагмент %s, перерисовываемый отдельно (hx-swap-oob)\ntempl %s(%s) {\n%s}\n\n",
+			name, c.componentName(), name, params, body))
+
+	args := "false"
+	if c.templArgs != "" {
+		args = c.templArgs + ", " + args
+	}
+	return fmt.Sprintf("%s@%s(%s)\n", indentation, name, args)
+}
+
+// SetTemplSignature задает параметры templ компонента и аргументы его вызова:
+// фрагменты принимают те же параметры, что и сам компонент
+func (c *JSXToHTMXConverter) SetTemplSignature(params string, args string) {
+	c.templParams = params
+	c.templArgs = args
+}
+
+// RegionTemplates возвращает templ компоненты фрагментов, созданных при последней конвертации
+func (c *JSXToHTMXConverter) RegionTemplates() string {
+	templates := strings.Join(c.regionTemplates, "")
+	c.regionTemplates = nil
+	return templates
+}
+
+// setterSwapAttributes возвращает hx-target и hx-swap для вызова сеттера: если состояние
+// читает только часть разметки, ответ содержит фрагменты с hx-swap-oob
+func (c *JSXToHTMXConverter) setterSwapAttributes(setter string) string {
+	if c.component != nil && c.regions != nil {
+		if state := findStateBySetter(c.component, setter); state != nil {
+			if _, whole := c.regions.regionsForState(state.Name); !whole {
+				return " hx-swap=\"none\""
+			}
+		}
+	}
+
+	return fmt.Sprintf(" hx-target={ \"#%s-\" + id } hx-swap=\"outerHTML\"", c.options.ComponentName)
+}
+
+// generateSetterRender генерирует ответ обработчика сеттера: если состояние читает
+// только часть разметки, рендерятся лишь зависящие от него фрагменты с hx-swap-oob,
+// иначе компонент целиком
+func (h *StateHandler) generateSetterRender(sb *strings.Builder, component *models.ReactComponent, state models.StateDefinition) {
+	regions, whole := findRenderRegions(component).regionsForState(state.Name)
+	if whole {
+		h.generateRender(sb, component)
+		return
+	}
+
+	indent := h.getIndentation(1)
+
+	if len(regions) == 0 {
+		// Состояние читает только элемент, который его меняет (поле ввода)
+		sb.WriteString(fmt.Sprintf("%s// Разметка не зависит от %s: перерисовка не нужна, фокус поля ввода сохраняется\n", indent, state.Name))
+		sb.WriteString(fmt.Sprintf("%sw.WriteHeader(http.StatusNoContent)\n", indent))
+		return
+	}
+
+	if len(component.Props) > 0 {
+		sb.WriteString(fmt.Sprintf("%s// Получаем пропсы, переданные при создании экземпляра\n", indent))
+		sb.WriteString(fmt.Sprintf("%sprops := load%sProps(id)\n\n", indent, component.Name))
+	}
+
+	var own, others []*renderRegion
+	for _, region := range regions {
+		if containsString(region.Writes, state.Name) {
+			own = append(own, region)
+		} else {
+			others = append(others, region)
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("%s// Рендерим только фрагменты, зависящие от %s (hx-swap-oob)\n", indent, state.Name))
+	args := h.templArgs(component, len(component.Props) > 0, h.generateRenderedState(sb, component, 1))
+	if len(others) == 0 {
+		sb.WriteString(fmt.Sprintf("%svar regions []templ.Component\n", indent))
+	} else {
+		sb.WriteString(fmt.Sprintf("%sregions := []templ.Component{\n", indent))
+		for _, region := range others {
+			sb.WriteString(fmt.Sprintf("%s%s%s(%s, true),\n", indent, indent, h.qualifiedName(regionFuncName(component, region.Index)), args))
+		}
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	// Элемент, который сам меняет состояние (поле ввода), не перерисовывается в ответ
+	// на свой запрос, чтобы сохранить фокус, но обновляется при изменении из других мест
+	for _, region := range own {
+		sb.WriteString(fmt.Sprintf("%sif r.Header.Get(\"HX-Trigger\") != \"%s-\"+id {\n", indent, regionElementID(component.Name, region)))
+		sb.WriteString(fmt.Sprintf("%s%sregions = append(regions, %s(%s, true))\n", indent, indent, h.qualifiedName(regionFuncName(component, region.Index)), args))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+	sb.WriteString(fmt.Sprintf("%stempl.Handler(templ.Join(regions...)).ServeHTTP(w, r)\n", indent))
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+	"react-to-templ-converter/internal/rendercheck"
+)
+
+// checkRendering сверяет рендеринг исходного компонента и сгенерированного templ
+// компонента на фикстуре из опций. Исходный компонент рендерит парсер через
+// react-dom/server, если он это поддерживает, templ компонент - временная сборка Go
+func (c *ReactToTemplConverter) checkRendering(reactCode string, component *models.ReactComponent, result *models.ConversionResult, options *config.ConversionOptions) *models.RenderCheck {
+	check := &models.RenderCheck{}
+	fixture := options.RenderFixture
+
+	renderer, ok := c.parser.(interface {
+		RenderComponent(code string, filePath string, componentName string, fixture *models.RenderFixture) (string, error)
+	})
+	if !ok {
+		return failedCheck(check, "Парсер не поддерживает рендеринг React: сверка недоступна")
+	}
+
+	reactHTML, err := renderer.RenderComponent(reactCode, options.SourcePath, component.Name, fixture)
+	if err != nil {
+		return failedCheck(check, fmt.Sprintf("Ошибка рендеринга React: %v", err))
+	}
+	check.ReactHTML = reactHTML
+
+	templHTML, err := rendercheck.RenderTempl(result.TemplFile, component.Name, fixture.Props, fixtureState(component, fixture))
+	if err != nil {
+		return failedCheck(check, fmt.Sprintf("Ошибка рендеринга templ: %v", err))
+	}
+	check.TemplHTML = templHTML
+
+	check.Diagnostics = rendercheck.Compare(reactHTML, templHTML)
+	check.Equivalent = len(check.Diagnostics) == 0
+	return check
+}
+
+// failedCheck завершает сверку диагностикой об ошибке рендеринга
+func failedCheck(check *models.RenderCheck, message string) *models.RenderCheck {
+	check.Diagnostics = append(check.Diagnostics, models.RenderDiagnostic{Kind: models.RenderDiffError, Message: message})
+	return check
+}
+
+// fixtureState возвращает значения серверных состояний для templ компонента: из фикстуры,
+// иначе начальные значения, которые задает обработчик New<Name> (строки, числа, булевы)
+func fixtureState(component *models.ReactComponent, fixture *models.RenderFixture) map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, state := range component.State {
+		if value, ok := fixture.State[state.Name]; ok {
+			values[state.Name] = value
+			continue
+		}
+		switch state.InitialValue.(type) {
+		case string, bool, float64:
+			values[state.Name] = state.InitialValue
+		}
+	}
+	return values
+}
@@ -0,0 +1,200 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/config"
+	"react-to-templ-converter/internal/models"
+	"strconv"
+	"strings"
+)
+
+// Префикс ключей, под которыми парсер передает атрибуты {...expr} элемента
+const spreadPropPrefix = "__spread__"
+
+// Имя rest-пропса, добавляемого компоненту, который передает элементу все пропсы ({...props})
+const attrsPropName = "attrs"
+
+// isSpreadProp проверяет, является ли атрибут JSX распространением {...expr}
+func isSpreadProp(name string) bool {
+	return strings.HasPrefix(name, spreadPropPrefix)
+}
+
+// spreadCode возвращает код выражения распространяемого атрибута
+func spreadCode(value interface{}) string {
+	valueExpr, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	code, _ := valueExpr["code"].(string)
+	return strings.TrimSpace(code)
+}
+
+// restProp возвращает rest-пропс компонента ({ title, ...rest }) или nil
+func restProp(props []models.PropDefinition) *models.PropDefinition {
+	for i := range props {
+		if props[i].Rest {
+			return &props[i]
+		}
+	}
+	return nil
+}
+
+// addAttrsProp добавляет компоненту rest-пропс attrs, если его разметка распространяет
+// на HTML элемент весь объект пропсов (<div {...props}>), а rest-параметра у компонента нет
+func addAttrsProp(component *models.ReactComponent) {
+	if component.JSX == nil || restProp(component.Props) != nil || !spreadsProps(component.JSX) {
+		return
+	}
+	component.Props = append(component.Props, models.PropDefinition{
+		Name: attrsPropName,
+		Type: "object",
+		Rest: true,
+	})
+}
+
+// spreadsProps проверяет, распространяется ли объект props на HTML элемент дерева
+func spreadsProps(jsx *models.JSXElement) bool {
+	for name, value := range jsx.Props {
+		if isSpreadProp(name) && spreadCode(value) == "props" {
+			return true
+		}
+	}
+	for _, child := range jsx.Children {
+		if spreadsProps(child) {
+			return true
+		}
+	}
+	_, trees := slotTrees(jsx)
+	for _, tree := range trees {
+		if spreadsProps(tree) {
+			return true
+		}
+	}
+	return false
+}
+
+// spreadAttributes переводит {...expr} HTML элемента в атрибуты: объект из литералов -
+// в постоянные атрибуты, rest-пропс компонента - в { props.Rest... }, остальные
+// выражения - в распространение templ.Attributes
+func (c *JSXToHTMXConverter) spreadAttributes(tag string, value interface{}) string {
+	code := spreadCode(value)
+	if code == "" {
+		return ""
+	}
+
+	if literal, ok := models.ParseJSLiteral(code); ok {
+		keys, values := literalObjectEntries(literal)
+		var sb strings.Builder
+		for _, key := range keys {
+			attr, _ := literalAttribute(tag, models.HTMLAttributeName(key), values[key])
+			sb.WriteString(attr)
+		}
+		return sb.String()
+	}
+
+	if field := c.restPropField(code); field != "" {
+		return fmt.Sprintf(" { props.%s... }", field)
+	}
+	return fmt.Sprintf(" { templ.Attributes(%s)... }", c.convertReactExprToGoExpr(code))
+}
+
+// restPropField возвращает поле rest-пропса, на которое ссылается выражение:
+// rest-параметр компонента ({...rest}) или весь объект пропсов ({...props})
+func (c *JSXToHTMXConverter) restPropField(code string) string {
+	if c.component == nil {
+		return ""
+	}
+	rest := restProp(c.component.Props)
+	if rest == nil {
+		return ""
+	}
+	if code == rest.Name || code == "props" || code == "props."+rest.Name {
+		return exportedName(rest.Name)
+	}
+	return ""
+}
+
+// literalObjectEntries возвращает ключи и значения объектного литерала в порядке исходного кода
+func literalObjectEntries(literal interface{}) ([]string, map[string]interface{}) {
+	if object, ok := literal.(*models.JSObject); ok {
+		return object.Keys, object.Values
+	}
+	return nil, nil
+}
+
+// componentAttrs собирает атрибуты, передаваемые дочернему компоненту с rest-пропсом:
+// пропсы, не объявленные компонентом, или rest-пропс родителя ({...rest}) целиком.
+// Возвращает выражение templ.Attributes или пустую строку; dropped - распространение
+// rest-пропса родителя, не переданное из-за явных атрибутов
+func (c *JSXToHTMXConverter) componentAttrs(jsx *models.JSXElement, propNames []string, ref *models.ComponentRef) (attrs string, dropped string) {
+	if ref == nil || restProp(ref.Props) == nil {
+		return "", ""
+	}
+
+	var entries []string
+	forwarded := ""
+	for _, name := range propNames {
+		value := jsx.Props[name]
+		if isSpreadProp(name) {
+			if field := c.restPropField(spreadCode(value)); field != "" {
+				forwarded = "props." + field
+			}
+			continue
+		}
+		if ref.FindProp(name) != nil {
+			continue
+		}
+
+		key := strconv.Quote(models.HTMLAttributeName(name))
+		switch v := value.(type) {
+		case bool:
+			entries = append(entries, fmt.Sprintf("%s: %t", key, v))
+		case string:
+			entries = append(entries, fmt.Sprintf("%s: %s", key, strconv.Quote(v)))
+		case map[string]interface{}:
+			code, _ := v["code"].(string)
+			if literal, ok := models.ParseJSLiteral(code); ok {
+				entries = append(entries, fmt.Sprintf("%s: %s", key, models.GoLiteral(literal, "string")))
+			} else {
+				entries = append(entries, fmt.Sprintf("%s: %s", key, c.stringExpression(c.convertReactExprToGoExpr(code))))
+			}
+		}
+	}
+
+	if len(entries) == 0 {
+		return forwarded, ""
+	}
+	return "templ.Attributes{" + strings.Join(entries, ", ") + "}", forwarded
+}
+
+// spreadWarnings сообщает о {...expr}, переданных компонентам без rest-пропса: компоненту
+// не из проекта или не объявляющему rest-параметр атрибуты не передаются
+func spreadWarnings(jsx *models.JSXElement, options *config.ConversionOptions) []string {
+	if jsx == nil {
+		return nil
+	}
+
+	var warnings []string
+	if isComponentType(jsx.Type) && !jsx.IsFragment() {
+		ref := options.Components[jsx.Type]
+		for _, name := range jsx.PropNames() {
+			if !isSpreadProp(name) || (ref != nil && restProp(ref.Props) != nil) {
+				continue
+			}
+			reason := "компонент не найден среди файлов проекта"
+			if ref != nil {
+				reason = "компонент не объявляет rest-пропс"
+			}
+			warnings = append(warnings, fmt.Sprintf("{...%s} не передается в %s: %s", spreadCode(jsx.Props[name]), jsx.Type, reason))
+		}
+	}
+
+	for _, child := range jsx.Children {
+		warnings = append(warnings, spreadWarnings(child, options)...)
+	}
+	_, trees := slotTrees(jsx)
+	for _, tree := range trees {
+		warnings = append(warnings, spreadWarnings(tree, options)...)
+	}
+	return warnings
+}
@@ -2,6 +2,7 @@ package converter
 
 import (
 	"fmt"
+	"net/url"
 	"react-to-templ-converter/internal/config"
 	"react-to-templ-converter/internal/models"
 	"strings"
@@ -49,26 +50,20 @@ func (h *StateHandler) SetIndentation(style string, size int) {
 
 // GenerateStateStructs генерирует структуры Go для хранения состояний компонента
 func (h *StateHandler) GenerateStateStructs(component *models.ReactComponent) string {
-	if len(component.State) == 0 {
+	if len(component.State) == 0 && len(component.Reducers) == 0 {
 		return ""
 	}
 
 	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("// %sState определяет состояние компонента %s\n", component.Name, component.Name))
-	sb.WriteString(fmt.Sprintf("type %sState struct {\n", component.Name))
-
 	indent := h.getIndentation(1)
 
-	// Поля для состояний
-	for _, state := range component.State {
-		goType := h.convertTypeToGo(state.Type, state.InitialValue)
-
-		fieldName := strings.ToUpper(string(state.Name[0])) + state.Name[1:]
-		sb.WriteString(fmt.Sprintf("%s%s %s\n", indent, fieldName, goType))
+	// Структура состояния объявлена рядом с templ шаблоном, который ее принимает
+	if h.options.PackageName != "" && h.options.PackageName != "." {
+		sb.WriteString(fmt.Sprintf("// %sState определяет состояние компонента %s (объявлено в пакете шаблонов)\n", component.Name, component.Name))
+		sb.WriteString(fmt.Sprintf("type %sState = %s\n\n", component.Name, h.qualifiedName(component.Name+"State")))
 	}
 
-	sb.WriteString("}\n\n")
+	h.generateReducerTypes(&sb, component)
 
 	// Глобальное хранилище состояний
 	sb.WriteString("var (\n")
@@ -88,58 +83,149 @@ func (h *StateHandler) GenerateStateStructs(component *models.ReactComponent) st
 		sb.WriteString(fmt.Sprintf("%s%sMutex sync.RWMutex\n", indent, strings.ToLower(component.Name)))
 	}
 
+	// Пропсы экземпляров хранятся в памяти: обработчики перерисовывают компонент с ними
+	if len(component.Props) > 0 {
+		sb.WriteString(fmt.Sprintf("\n%s%sProps = make(map[string]%s)\n", indent, strings.ToLower(component.Name), h.qualifiedName(component.Name+"Props")))
+		sb.WriteString(fmt.Sprintf("%s%sPropsMutex sync.RWMutex\n", indent, strings.ToLower(component.Name)))
+	}
+
 	sb.WriteString(")\n\n")
 
 	return sb.String()
 }
 
-// GenerateStateHandlers генерирует Go обработчики для взаимодействия с состоянием
-func (h *StateHandler) GenerateStateHandlers(component *models.ReactComponent) string {
-	if len(component.State) == 0 {
+// GenerateStateType генерирует структуру состояния компонента. Структура передается
+// в templ компонент, поэтому объявляется в пакете шаблонов
+func (h *StateHandler) GenerateStateType(component *models.ReactComponent) string {
+	if len(component.State) == 0 && len(component.Reducers) == 0 {
 		return ""
 	}
 
 	var sb strings.Builder
 	indent := h.getIndentation(1)
 
-	// Функция для создания нового экземпляра компонента
-	sb.WriteString(fmt.Sprintf("// New%s создает новый экземпляр компонента\n", component.Name))
-	sb.WriteString(fmt.Sprintf("func New%s(w http.ResponseWriter, r *http.Request) {\n", component.Name))
-	sb.WriteString(fmt.Sprintf("%s// Генерируем уникальный ID для компонента\n", indent))
-	sb.WriteString(fmt.Sprintf("%sid := uuid.New().String()\n\n", indent))
+	sb.WriteString(fmt.Sprintf("// %sState определяет состояние компонента %s\n", component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("type %sState struct {\n", component.Name))
 
-	// Получение параметров из запроса
-	if len(component.Props) > 0 {
-		sb.WriteString(fmt.Sprintf("%s// Получаем пропсы из запроса\n", indent))
-		if h.options.PackageName != "" && h.options.PackageName != "." {
-			sb.WriteString(fmt.Sprintf("%svar props %s.%sProps\n", indent, h.options.PackageName, component.Name))
-		} else {
-			sb.WriteString(fmt.Sprintf("%svar props %sProps\n", indent, component.Name))
+	// Поля для состояний
+	for _, state := range component.State {
+		if !needsServerStorage(component, state) {
+			continue
 		}
-		sb.WriteString(fmt.Sprintf("%sif err := json.NewDecoder(r.Body).Decode(&props); err != nil {\n", indent))
-		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Ошибка декодирования пропсов\", http.StatusBadRequest)\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+		goType := h.convertTypeToGo(state.Type, state.InitialValue)
+
+		fieldName := strings.ToUpper(string(state.Name[0])) + state.Name[1:]
+		sb.WriteString(fmt.Sprintf("%s%s %s\n", indent, fieldName, goType))
 	}
 
-	// Создание начального состояния
-	sb.WriteString(fmt.Sprintf("%s// Создаем начальное состояние\n", indent))
-	sb.WriteString(fmt.Sprintf("%sstate := &%sState{\n", indent, component.Name))
+	// Refs, используемые как переменные экземпляра, хранятся вместе с состоянием
+	for _, ref := range instanceRefs(component) {
+		goType := h.convertTypeToGo("", ref.InitialValue)
+		sb.WriteString(fmt.Sprintf("%s%s %s\n", indent, exportedName(ref.Name), goType))
+	}
+
+	// Поля для состояний редьюсеров
+	for _, reducer := range component.Reducers {
+		for _, field := range reducer.Fields {
+			goType := h.convertTypeToGo(field.Type, field.InitialValue)
+			sb.WriteString(fmt.Sprintf("%s%s %s\n", indent, exportedName(field.Name), goType))
+		}
+	}
+
+	sb.WriteString("}\n\n")
+
+	// Компонент проекта могут выводить родительские компоненты из других файлов: загрузку
+	// состояния экземпляров передает в контексте запроса middleware контроллера
+	if h.isProjectFile() {
+		keyType := strings.ToLower(component.Name[:1]) + component.Name[1:] + "StateLoaderKey"
+		loaderType := fmt.Sprintf("func(id string) %sState", component.Name)
+
+		sb.WriteString(fmt.Sprintf("// %s - ключ контекста загрузки состояния экземпляров %s\n", keyType, component.Name))
+		sb.WriteString(fmt.Sprintf("type %s struct{}\n\n", keyType))
+
+		sb.WriteString(fmt.Sprintf("// With%sStateLoader возвращает контекст с загрузкой состояния экземпляров %s по id\n", component.Name, component.Name))
+		sb.WriteString(fmt.Sprintf("func With%sStateLoader(ctx context.Context, load %s) context.Context {\n", component.Name, loaderType))
+		sb.WriteString(fmt.Sprintf("%sreturn context.WithValue(ctx, %s{}, load)\n", indent, keyType))
+		sb.WriteString("}\n\n")
+
+		sb.WriteString(fmt.Sprintf("// %s возвращает состояние экземпляра %s, который выводит родительский\n", stateLoaderName(component.Name), component.Name))
+		sb.WriteString("// компонент: из загрузки контекста, без нее - начальное\n")
+		sb.WriteString(fmt.Sprintf("func %s(ctx context.Context, id string) %sState {\n", stateLoaderName(component.Name), component.Name))
+		sb.WriteString(fmt.Sprintf("%sif load, ok := ctx.Value(%s{}).(%s); ok {\n", indent, keyType, loaderType))
+		sb.WriteString(fmt.Sprintf("%sreturn load(id)\n", h.getIndentation(2)))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+		sb.WriteString(fmt.Sprintf("%sreturn %s\n", indent, strings.TrimPrefix(h.initialStateLiteral(component, 1), "&")))
+		sb.WriteString("}\n\n")
+	}
+
+	return sb.String()
+}
+
+// InitialStateFields возвращает поля литерала начального состояния компонента (Count: 0)
+func (h *StateHandler) InitialStateFields(component *models.ReactComponent) []string {
+	var fields []string
 
 	for _, state := range component.State {
+		if !needsServerStorage(component, state) {
+			continue
+		}
 		stateName := strings.ToUpper(string(state.Name[0])) + state.Name[1:]
 
-		// Установка начального значения
-		if state.InitialValue != nil {
-			sb.WriteString(fmt.Sprintf("%s%s%s: %v,\n", indent, indent, stateName, h.formatGoValue(state.InitialValue)))
+		// Установка начального значения: массивы - срезами своего типа
+		if goType := h.convertTypeToGo(state.Type, state.InitialValue); state.InitialValue != nil && strings.HasPrefix(goType, "[]") {
+			fields = append(fields, fmt.Sprintf("%s: %s", stateName, models.GoLiteral(state.InitialValue, goType)))
+		} else if state.InitialValue != nil {
+			fields = append(fields, fmt.Sprintf("%s: %v", stateName, h.formatGoValue(state.InitialValue)))
 		} else {
 			// Значение по умолчанию для типа
 			goType := h.convertTypeToGo(state.Type, nil)
-			sb.WriteString(fmt.Sprintf("%s%s%s: %s,\n", indent, indent, stateName, h.getDefaultValueForType(goType)))
+			fields = append(fields, fmt.Sprintf("%s: %s", stateName, h.getDefaultValueForType(goType)))
 		}
 	}
 
-	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+	for _, ref := range instanceRefs(component) {
+		if ref.InitialValue != nil {
+			fields = append(fields, fmt.Sprintf("%s: %v", exportedName(ref.Name), h.formatGoValue(ref.InitialValue)))
+		}
+	}
+
+	for _, reducer := range component.Reducers {
+		for _, field := range reducer.Fields {
+			if field.InitialValue != nil {
+				fields = append(fields, fmt.Sprintf("%s: %v", exportedName(field.Name), h.formatGoValue(field.InitialValue)))
+			} else {
+				goType := h.convertTypeToGo(field.Type, nil)
+				fields = append(fields, fmt.Sprintf("%s: %s", exportedName(field.Name), h.getDefaultValueForType(goType)))
+			}
+		}
+	}
+
+	return fields
+}
+
+// GenerateStateHandlers генерирует Go обработчики для взаимодействия с состоянием
+func (h *StateHandler) GenerateStateHandlers(component *models.ReactComponent) string {
+	if len(component.State) == 0 && len(component.Reducers) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	indent := h.getIndentation(1)
+
+	// Функция для создания нового экземпляра компонента
+	sb.WriteString(fmt.Sprintf("// New%s создает новый экземпляр компонента\n", component.Name))
+	sb.WriteString(fmt.Sprintf("func New%s(w http.ResponseWriter, r *http.Request) {\n", component.Name))
+	sb.WriteString(fmt.Sprintf("%s// Генерируем уникальный ID для компонента\n", indent))
+	sb.WriteString(fmt.Sprintf("%sid := uuid.New().String()\n\n", indent))
+
+	// Получение пропсов из запроса
+	if len(component.Props) > 0 {
+		h.generatePropsDecoding(&sb, component)
+	}
+
+	// Создание начального состояния
+	sb.WriteString(fmt.Sprintf("%s// Создаем начальное состояние\n", indent))
+	sb.WriteString(fmt.Sprintf("%sstate := %s\n\n", indent, h.initialStateLiteral(component, 1)))
 
 	// Сохранение состояния в зависимости от способа хранения
 	sb.WriteString(fmt.Sprintf("%s// Сохраняем состояние\n", indent))
@@ -158,39 +244,59 @@ func (h *StateHandler) GenerateStateHandlers(component *models.ReactComponent) s
 		sb.WriteString(fmt.Sprintf("%s%sMutex.Unlock()\n\n", indent, strings.ToLower(component.Name)))
 	}
 
+	// Пропсы экземпляра нужны обработчикам, которые его перерисовывают
+	if len(component.Props) > 0 {
+		sb.WriteString(fmt.Sprintf("%s// Сохраняем пропсы экземпляра\n", indent))
+		sb.WriteString(fmt.Sprintf("%s%sPropsMutex.Lock()\n", indent, strings.ToLower(component.Name)))
+		sb.WriteString(fmt.Sprintf("%s%sProps[id] = props\n", indent, strings.ToLower(component.Name)))
+		sb.WriteString(fmt.Sprintf("%s%sPropsMutex.Unlock()\n\n", indent, strings.ToLower(component.Name)))
+	}
+
 	// Рендеринг компонента
 	sb.WriteString(fmt.Sprintf("%s// Рендерим компонент\n", indent))
+	current := h.generateRenderedState(&sb, component, 1)
+	sb.WriteString(fmt.Sprintf("%stempl.Handler(%s).ServeHTTP(w, r)\n", indent, h.templCall(component, len(component.Props) > 0, current)))
+	sb.WriteString("}\n\n")
 
-	if h.options.PackageName != "" && h.options.PackageName != "." {
-		sb.WriteString(fmt.Sprintf("%stempl.Handler(%s.%s(", indent, h.options.PackageName,
-			strings.ToLower(string(component.Name[0]))+component.Name[1:]))
-	} else {
-		sb.WriteString(fmt.Sprintf("%stempl.Handler(%s(", indent, strings.ToLower(string(component.Name[0]))+component.Name[1:]))
-	}
+	// Копия состояния для рендеринга
+	h.generateStateSnapshot(&sb, component)
 
-	if len(component.Props) > 0 {
-		sb.WriteString("props, ")
-	}
-	sb.WriteString("id)).ServeHTTP(w, r)\n")
-	sb.WriteString("}\n\n")
+	// Пропсы, с которыми обработчики перерисовывают экземпляр
+	h.generatePropsLoader(&sb, component)
+
+	// Загрузка состояния экземпляров, которые выводят родительские компоненты
+	h.generateStateLoader(&sb, component)
+
+	// Проверка обязательных пропсов
+	h.generateMissingProps(&sb, component)
 
-	// Создаем обработчики для каждого состояния
+	// Создаем обработчики для каждого состояния, сеттер которого вызывается из разметки
 	for _, state := range component.State {
+		if !needsSetterEndpoint(component, state) {
+			continue
+		}
 		h.generateStateUpdater(&sb, component, state)
 	}
 
 	// Добавляем функции для callbacks, если они есть
 	for _, callback := range component.Callbacks {
+		// Колбэки, работающие только с DOM refs или клиентскими состояниями, выполняются на клиенте
+		if isClientCallback(component, callback) {
+			continue
+		}
 		h.generateCallbackHandler(&sb, component, callback)
 	}
 
 	// Добавляем обработчики для эффектов, если они есть и не используются только для загрузки данных
 	for i, effect := range component.Effects {
-		if !isEffectForDataFetching(effect) {
+		if needsEffectHandler(effect) {
 			h.generateEffectHandler(&sb, component, effect, i)
 		}
 	}
 
+	// Функции reduce и обработчики dispatch для useReducer
+	h.generateReducerHandlers(&sb, component)
+
 	return sb.String()
 }
 
@@ -204,6 +310,15 @@ func (h *StateHandler) GenerateHtmxJSHelpers(component *models.ReactComponent) s
 
 	sb.WriteString("// JavaScript для работы с компонентом " + component.Name + "\n\n")
 
+	// Подписки работают через расширение htmx sse
+	for _, effect := range component.Effects {
+		if effect.Kind == models.EffectKindSubscription {
+			sb.WriteString("// Для подписок требуется расширение htmx sse:\n")
+			sb.WriteString("// <script src=\"https://unpkg.com/htmx-ext-sse@2.2.2/sse.js\"></script>\n\n")
+			break
+		}
+	}
+
 	sb.WriteString("document.addEventListener('DOMContentLoaded', function() {\n")
 
 	indent := h.getIndentation(1)
@@ -211,86 +326,157 @@ func (h *StateHandler) GenerateHtmxJSHelpers(component *models.ReactComponent) s
 	// Инициализация обработчиков после загрузки компонента
 	sb.WriteString(fmt.Sprintf("%s// Обработчики для компонента будут добавлены после его загрузки через HTMX\n", indent))
 	sb.WriteString(fmt.Sprintf("%sdocument.body.addEventListener('htmx:afterSwap', function(event) {\n", indent))
-	sb.WriteString(fmt.Sprintf("%s%s// Проверяем, относится ли событие к нашему компоненту\n", indent, indent))
-	sb.WriteString(fmt.Sprintf("%s%sconst target = event.detail.target;\n", indent, indent))
-	sb.WriteString(fmt.Sprintf("%s%sif (target && target.id && target.id.startsWith('%s-')) {\n", indent, indent, component.Name))
-	sb.WriteString(fmt.Sprintf("%s%s%s// Инициализация компонента после загрузки\n", indent, indent, indent))
-	sb.WriteString(fmt.Sprintf("%s%s%sinitialize%s(target.id.split('-')[1]);\n", indent, indent, indent, component.Name))
-	sb.WriteString(fmt.Sprintf("%s%s}\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s// Цель - перерисованный экземпляр или элемент, в который вставлен новый (POST /api/%s/new)\n", indent, indent, strings.ToLower(component.Name)))
+	sb.WriteString(fmt.Sprintf("%s%sinitializeInstances(event.detail.target);\n", indent, indent))
 	sb.WriteString(fmt.Sprintf("%s});\n", indent))
 
-	// Функция инициализации компонента
-	sb.WriteString(fmt.Sprintf("\n%s// Функция инициализации компонента\n", indent))
-	sb.WriteString(fmt.Sprintf("%sfunction initialize%s(id) {\n", indent, component.Name))
+	// Экземпляры ищутся в самом элементе и внутри него
+	sb.WriteString(fmt.Sprintf("\n%s// Инициализирует экземпляры компонента: элемент и его потомков\n", indent))
+	sb.WriteString(fmt.Sprintf("%sfunction initializeInstances(element) {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s%sconst elements = [element].concat(Array.from(element.querySelectorAll('[id^=\"%s-\"]')));\n", indent, indent, component.Name))
+	sb.WriteString(fmt.Sprintf("%s%selements.forEach(function(element) {\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%sconst id = instanceId(element);\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%sif (id !== null) {\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%s%sinitialize%s(id);\n", indent, indent, indent, indent, component.Name))
+	sb.WriteString(fmt.Sprintf("%s%s%s}\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s});\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+
+	// id экземпляра - все, что следует за именем компонента в id корневого элемента:
+	// UUID и производные id дочерних компонентов сами содержат дефисы
+	sb.WriteString(fmt.Sprintf("\n%s// Возвращает id экземпляра по корневому элементу компонента. Фрагменты и элементы\n", indent))
+	sb.WriteString(fmt.Sprintf("%s// с ref имеют свои id (%s-r1-<id>) и экземплярами не считаются\n", indent, component.Name))
+	sb.WriteString(fmt.Sprintf("%sfunction instanceId(element) {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s%sconst match = element && element.id ? %s.exec(element.id) : null;\n", indent, indent, instanceIDPattern(component)))
+	sb.WriteString(fmt.Sprintf("%s%sreturn match ? match[1] : null;\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
 
-	// Если есть эффекты, которые не используются только для загрузки данных
+	// Если есть эффекты, которые не используются только для загрузки данных.
+	// Таймеры и подписки реализуются через hx-trigger и SSE и в JS не нуждаются
 	hasNonDataFetchingEffects := false
+	runsEffects := false
 	for _, effect := range component.Effects {
-		if !isEffectForDataFetching(effect) {
+		if isClientEffect(component, effect) {
 			hasNonDataFetchingEffects = true
-			break
+			runsEffects = runsEffects || len(serverValuesRead(effect.Body, component)) == 0
 		}
 	}
 
+	// Экземпляр монтируется, когда инициализируется впервые: при загрузке страницы или
+	// появлении нового экземпляра. Перерисовка существующего экземпляра его не монтирует
 	if hasNonDataFetchingEffects {
-		sb.WriteString(fmt.Sprintf("%s%s// Установка эффектов, не связанных с загрузкой данных\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s%ssetupEffects(id);\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("\n%s// id смонтированных экземпляров\n", indent))
+		sb.WriteString(fmt.Sprintf("%sconst mountedInstances = new Set();\n", indent))
 	}
 
+	// Функция инициализации компонента
+	sb.WriteString(fmt.Sprintf("\n%s// Функция инициализации компонента\n", indent))
+	sb.WriteString(fmt.Sprintf("%sfunction initialize%s(id) {\n", indent, component.Name))
+	if hasNonDataFetchingEffects {
+		sb.WriteString(fmt.Sprintf("%s%sconst mounting = !mountedInstances.has(id);\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%smountedInstances.add(id);\n\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s// Эффекты, которые выполняются в браузере\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%ssetupEffects(id, mounting);\n", indent, indent))
+	}
 	sb.WriteString(fmt.Sprintf("%s}\n", indent))
 
 	// Функция для эффектов
 	if hasNonDataFetchingEffects {
-		sb.WriteString(fmt.Sprintf("\n%s// Функция для настройки эффектов\n", indent))
-		sb.WriteString(fmt.Sprintf("%sfunction setupEffects(id) {\n", indent))
+		sb.WriteString(fmt.Sprintf("\n%s// Эффекты компонента в браузере: mounting - экземпляр инициализируется впервые\n", indent))
+		sb.WriteString(fmt.Sprintf("%sfunction setupEffects(id, mounting) {\n", indent))
 
 		for i, effect := range component.Effects {
-			if !isEffectForDataFetching(effect) {
-				sb.WriteString(fmt.Sprintf("%s%s// Эффект %d\n", indent, indent, i+1))
-
-				// Создаем список зависимостей
-				if len(effect.Dependencies) > 0 {
-					sb.WriteString(fmt.Sprintf("%s%s// Зависимости: %s\n", indent, indent, strings.Join(effect.Dependencies, ", ")))
-
-					// Если есть зависимости, добавляем обработчики для их отслеживания
-					for _, dep := range effect.Dependencies {
-						sb.WriteString(fmt.Sprintf("%s%s// Отслеживаем изменение %s\n", indent, indent, dep))
-						sb.WriteString(fmt.Sprintf("%s%sdocument.getElementById('%s-' + id).addEventListener('htmx:afterSettle', function(event) {\n", indent, indent, component.Name))
-						sb.WriteString(fmt.Sprintf("%s%s%s// Проверяем, изменилось ли состояние %s\n", indent, indent, indent, dep))
-						sb.WriteString(fmt.Sprintf("%s%s%sfetch('/api/%s/effect/%d?id=' + id, { method: 'POST' });\n", indent, indent, indent, strings.ToLower(component.Name), i))
-						sb.WriteString(fmt.Sprintf("%s%s});\n", indent, indent))
-					}
-				} else {
-					// Если нет зависимостей, выполняем эффект при загрузке
-					sb.WriteString(fmt.Sprintf("%s%s// Эффект без зависимостей, выполняется при загрузке\n", indent, indent))
-					sb.WriteString(fmt.Sprintf("%s%sfetch('/api/%s/effect/%d?id=' + id, { method: 'POST' });\n", indent, indent, strings.ToLower(component.Name), i))
-				}
-
-				sb.WriteString("\n")
+			if !isClientEffect(component, effect) {
+				continue
+			}
+
+			// Серверных состояний и пропсов в браузере нет: такой эффект не переносится
+			if names := serverValuesRead(effect.Body, component); len(names) > 0 {
+				sb.WriteString(fmt.Sprintf("%s%s// Эффект %d не перенесен: читает %s, недоступные в браузере\n\n", indent, indent, i+1, strings.Join(names, ", ")))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("%s%s// Эффект %d\n", indent, indent, i+1))
+
+			// Эффект без зависимостей выполняется один раз при монтировании, эффект
+			// с зависимостями - и после каждой перерисовки компонента через HTMX
+			condition := "mounting"
+			if len(effect.Dependencies) > 0 {
+				sb.WriteString(fmt.Sprintf("%s%s// Зависимости: %s\n", indent, indent, strings.Join(effect.Dependencies, ", ")))
+				condition = ""
+			}
+			bodyIndent := indent + indent
+			if condition != "" {
+				sb.WriteString(fmt.Sprintf("%s%sif (%s) {\n", indent, indent, condition))
+				bodyIndent += indent
 			}
+			sb.WriteString(fmt.Sprintf("%srunEffect(id, %d, function() {\n", bodyIndent, i+1))
+			for _, line := range effectBodyLines(effect.Body) {
+				sb.WriteString(fmt.Sprintf("%s%s%s\n", bodyIndent, indent, line))
+			}
+			sb.WriteString(fmt.Sprintf("%s});\n", bodyIndent))
+			if condition != "" {
+				sb.WriteString(fmt.Sprintf("%s%s}\n", indent, indent))
+			}
+			sb.WriteString("\n")
 		}
 
 		sb.WriteString(fmt.Sprintf("%s}\n", indent))
 	}
 
-	// Обработка очистки при удалении компонента
+	// Функция очистки, которую возвращает эффект, вызывается перед его повторным
+	// запуском и при удалении компонента, как в React
+	if runsEffects {
+		sb.WriteString(fmt.Sprintf("\n%s// Функции очистки эффектов в браузере по id экземпляра и номеру эффекта\n", indent))
+		sb.WriteString(fmt.Sprintf("%sconst effectCleanups = {};\n", indent))
+		sb.WriteString(fmt.Sprintf("\n%s// Выполняет эффект, предварительно вызывая очистку его предыдущего запуска\n", indent))
+		sb.WriteString(fmt.Sprintf("%sfunction runEffect(id, index, effect) {\n", indent))
+		sb.WriteString(fmt.Sprintf("%s%sconst key = id + ':' + index;\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%sif (typeof effectCleanups[key] === 'function') {\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s%seffectCleanups[key]();\n", indent, indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s}\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%seffectCleanups[key] = effect();\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+		sb.WriteString(fmt.Sprintf("\n%s// Вызывает функции очистки всех эффектов экземпляра\n", indent))
+		sb.WriteString(fmt.Sprintf("%sfunction cleanupEffects(id) {\n", indent))
+		sb.WriteString(fmt.Sprintf("%s%sObject.keys(effectCleanups).forEach(function(key) {\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s%sif (!key.startsWith(id + ':')) {\n", indent, indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s%s%sreturn;\n", indent, indent, indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s%s}\n", indent, indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s%sif (typeof effectCleanups[key] === 'function') {\n", indent, indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s%s%seffectCleanups[key]();\n", indent, indent, indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s%s}\n", indent, indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s%sdelete effectCleanups[key];\n", indent, indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%s});\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	// Обработка очистки при удалении компонента. При перерисовке через hx-swap
+	// htmx тоже очищает старый элемент, но на его месте уже стоит новый с тем же id
 	sb.WriteString(fmt.Sprintf("\n%s// Очистка ресурсов при удалении компонента\n", indent))
 	sb.WriteString(fmt.Sprintf("%sdocument.body.addEventListener('htmx:beforeCleanupElement', function(event) {\n", indent))
 	sb.WriteString(fmt.Sprintf("%s%sconst element = event.detail.element;\n", indent, indent))
-	sb.WriteString(fmt.Sprintf("%s%sif (element && element.id && element.id.startsWith('%s-')) {\n", indent, indent, component.Name))
-	sb.WriteString(fmt.Sprintf("%s%s%sconst id = element.id.split('-')[1];\n", indent, indent, indent))
-	sb.WriteString(fmt.Sprintf("%s%s%s// Уведомляем сервер о необходимости очистки ресурсов\n", indent, indent, indent))
-	sb.WriteString(fmt.Sprintf("%s%s%sfetch('/api/%s/cleanup?id=' + id, { method: 'POST' });\n", indent, indent, indent, strings.ToLower(component.Name)))
+	sb.WriteString(fmt.Sprintf("%s%sconst id = instanceId(element);\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%sif (id !== null) {\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%s// Элемент заменен новым при перерисовке - компонент остается на странице\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%ssetTimeout(function() {\n", indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%s%sif (document.getElementById(element.id)) {\n", indent, indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%s%s%sreturn;\n", indent, indent, indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%s%s}\n", indent, indent, indent, indent))
+	if runsEffects {
+		sb.WriteString(fmt.Sprintf("%s%s%s%scleanupEffects(id);\n", indent, indent, indent, indent))
+	}
+	if hasNonDataFetchingEffects {
+		sb.WriteString(fmt.Sprintf("%s%s%s%smountedInstances.delete(id);\n", indent, indent, indent, indent))
+	}
+	sb.WriteString(fmt.Sprintf("%s%s%s%s// Уведомляем сервер о необходимости очистки ресурсов\n", indent, indent, indent, indent))
+	sb.WriteString(fmt.Sprintf("%s%s%s%sfetch('/api/%s/cleanup?id=' + id, { method: 'POST' });\n", indent, indent, indent, indent, strings.ToLower(component.Name)))
+	sb.WriteString(fmt.Sprintf("%s%s%s});\n", indent, indent, indent))
 	sb.WriteString(fmt.Sprintf("%s%s}\n", indent, indent))
 	sb.WriteString(fmt.Sprintf("%s});\n", indent))
 
 	// Первоначальная инициализация компонента
 	sb.WriteString(fmt.Sprintf("\n%s// Попытка инициализации компонента при загрузке страницы\n", indent))
-	sb.WriteString(fmt.Sprintf("%sconst components = document.querySelectorAll('[id^=\"%s-\"]');\n", indent, component.Name))
-	sb.WriteString(fmt.Sprintf("%scomponents.forEach(function(component) {\n", indent))
-	sb.WriteString(fmt.Sprintf("%s%sconst id = component.id.split('-')[1];\n", indent, indent))
-	sb.WriteString(fmt.Sprintf("%s%sinitialize%s(id);\n", indent, indent, component.Name))
-	sb.WriteString(fmt.Sprintf("%s});\n", indent))
+	sb.WriteString(fmt.Sprintf("%sinitializeInstances(document.body);\n", indent))
 
 	sb.WriteString("});\n")
 
@@ -302,14 +488,7 @@ func (h *StateHandler) GenerateHtmxJSHelpers(component *models.ReactComponent) s
 // generateStateUpdater генерирует обработчик для обновления состояния
 func (h *StateHandler) generateStateUpdater(sb *strings.Builder, component *models.ReactComponent, state models.StateDefinition) {
 	stateName := strings.ToUpper(string(state.Name[0])) + state.Name[1:]
-	setterName := state.Setter
-
-	// Имя обработчика
-	handlerName := setterName
-	// Если setter не начинается с большой буквы, делаем первую букву заглавной
-	if len(handlerName) > 0 && handlerName[0] >= 'a' && handlerName[0] <= 'z' {
-		handlerName = strings.ToUpper(string(handlerName[0])) + handlerName[1:]
-	}
+	handlerName := setterHandlerName(component, state)
 
 	indent := h.getIndentation(1)
 
@@ -323,39 +502,7 @@ func (h *StateHandler) generateStateUpdater(sb *strings.Builder, component *mode
 	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
 
 	// Получаем и проверяем состояние компонента
-	switch h.options.StatePersistence {
-	case "redis":
-		sb.WriteString(fmt.Sprintf("%s// Получаем состояние из Redis\n", indent))
-		sb.WriteString(fmt.Sprintf("%sjsonState, err := redisClient.Get(context.Background(), %sKeyPrefix+id).Bytes()\n", indent, strings.ToLower(component.Name)))
-		sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
-		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Состояние компонента не найдено\", http.StatusNotFound)\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
-
-		sb.WriteString(fmt.Sprintf("%svar state %sState\n", indent, component.Name))
-		sb.WriteString(fmt.Sprintf("%sif err := json.Unmarshal(jsonState, &state); err != nil {\n", indent))
-		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Ошибка десериализации состояния\", http.StatusInternalServerError)\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
-
-	case "database":
-		sb.WriteString(fmt.Sprintf("%s// Получаем состояние из БД\n", indent))
-		sb.WriteString(fmt.Sprintf("%sstate, err := %sRepository.GetState(id)\n", indent, strings.ToLower(component.Name)))
-		sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
-		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Состояние компонента не найдено\", http.StatusNotFound)\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
-
-	default:
-		sb.WriteString(fmt.Sprintf("%s// Получаем состояние из памяти\n", indent))
-		sb.WriteString(fmt.Sprintf("%s%sMutex.RLock()\n", indent, strings.ToLower(component.Name)))
-		sb.WriteString(fmt.Sprintf("%sstate, ok := %sStates[id]\n", indent, strings.ToLower(component.Name)))
-		sb.WriteString(fmt.Sprintf("%s%sMutex.RUnlock()\n", indent, strings.ToLower(component.Name)))
-		sb.WriteString(fmt.Sprintf("%sif !ok {\n", indent))
-		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Состояние компонента не найдено\", http.StatusNotFound)\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
-	}
+	h.generateStateLoading(sb, component)
 
 	// Получаем новое значение состояния
 	sb.WriteString(fmt.Sprintf("%s// Получаем новое значение состояния из запроса\n", indent))
@@ -374,7 +521,8 @@ func (h *StateHandler) generateStateUpdater(sb *strings.Builder, component *mode
 		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
 	} else if goType == "bool" {
 		sb.WriteString(fmt.Sprintf("%snewValueStr := r.FormValue(\"value\")\n", indent))
-		sb.WriteString(fmt.Sprintf("%snewValue := newValueStr == \"true\"\n\n", indent))
+		// Флажок формы без hx-vals отправляет "on"
+		sb.WriteString(fmt.Sprintf("%snewValue := newValueStr == \"true\" || newValueStr == \"on\"\n\n", indent))
 	} else if goType == "float64" {
 		sb.WriteString(fmt.Sprintf("%snewValueStr := r.FormValue(\"value\")\n", indent))
 		sb.WriteString(fmt.Sprintf("%snewValue, err := strconv.ParseFloat(newValueStr, 64)\n", indent))
@@ -391,42 +539,10 @@ func (h *StateHandler) generateStateUpdater(sb *strings.Builder, component *mode
 	}
 
 	// Обновляем состояние
-	sb.WriteString(fmt.Sprintf("%s// Обновляем состояние\n", indent))
-
-	switch h.options.StatePersistence {
-	case "redis":
-		sb.WriteString(fmt.Sprintf("%sstate.%s = newValue\n", indent, stateName))
-		sb.WriteString(fmt.Sprintf("%sjsonState, _ = json.Marshal(state)\n", indent))
-		sb.WriteString(fmt.Sprintf("%sredisClient.Set(context.Background(), %sKeyPrefix+id, jsonState, 24*time.Hour)\n\n", indent, strings.ToLower(component.Name)))
-
-	case "database":
-		sb.WriteString(fmt.Sprintf("%sstate.%s = newValue\n", indent, stateName))
-		sb.WriteString(fmt.Sprintf("%sif err := %sRepository.UpdateState(id, state); err != nil {\n", indent, strings.ToLower(component.Name)))
-		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Ошибка обновления состояния\", http.StatusInternalServerError)\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
-		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+	h.generateStateSaving(sb, component, []string{fmt.Sprintf("state.%s = newValue", stateName)})
 
-	default:
-		sb.WriteString(fmt.Sprintf("%s%sMutex.Lock()\n", indent, strings.ToLower(component.Name)))
-		sb.WriteString(fmt.Sprintf("%sstate.%s = newValue\n", indent, stateName))
-		sb.WriteString(fmt.Sprintf("%s%sMutex.Unlock()\n\n", indent, strings.ToLower(component.Name)))
-	}
-
-	// Рендерим компонент заново
-	sb.WriteString(fmt.Sprintf("%s// Получаем пропсы (в реальном приложении нужно сохранять пропсы)\n", indent))
-	if h.options.PackageName != "" && h.options.PackageName != "." {
-		sb.WriteString(fmt.Sprintf("%svar props %s.%sProps\n\n", indent, h.options.PackageName, component.Name))
-	} else {
-		sb.WriteString(fmt.Sprintf("%svar props %sProps\n\n", indent, component.Name))
-	}
-
-	sb.WriteString(fmt.Sprintf("%s// Рендерим компонент с обновленным состоянием\n", indent))
-
-	if h.options.PackageName != "" && h.options.PackageName != "." {
-		sb.WriteString(fmt.Sprintf("%stempl.Handler(%s.%s(props, id)).ServeHTTP(w, r)\n", indent, h.options.PackageName, strings.ToLower(string(component.Name[0]))+component.Name[1:]))
-	} else {
-		sb.WriteString(fmt.Sprintf("%stempl.Handler(%s(props, id)).ServeHTTP(w, r)\n", indent, strings.ToLower(string(component.Name[0]))+component.Name[1:]))
-	}
+	// Рендерим фрагменты, зависящие от состояния, или компонент заново
+	h.generateSetterRender(sb, component, state)
 
 	sb.WriteString("}\n\n")
 }
@@ -435,12 +551,7 @@ func (h *StateHandler) generateStateUpdater(sb *strings.Builder, component *mode
 func (h *StateHandler) generateCallbackHandler(sb *strings.Builder, component *models.ReactComponent, callback models.CallbackDefinition) {
 	callbackName := callback.Name
 
-	// Имя обработчика
-	handlerName := callbackName
-	// Если callbackName не начинается с большой буквы, делаем первую букву заглавной
-	if len(handlerName) > 0 && handlerName[0] >= 'a' && handlerName[0] <= 'z' {
-		handlerName = strings.ToUpper(string(handlerName[0])) + handlerName[1:]
-	}
+	handlerName := callbackHandlerName(component, callback)
 
 	indent := h.getIndentation(1)
 
@@ -453,31 +564,36 @@ func (h *StateHandler) generateCallbackHandler(sb *strings.Builder, component *m
 	sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
 	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
 
-	// Получаем и проверяем состояние компонента (аналогично обработчику состояния)
-	// ...код аналогичен generateStateUpdater...
+	// Получаем состояние компонента
+	h.generateStateLoading(sb, component)
 
 	// Логика callback (зависит от конкретного callback)
 	sb.WriteString(fmt.Sprintf("%s// Реализация callback-функции %s\n", indent, callbackName))
 	sb.WriteString(fmt.Sprintf("%s// TODO: Реализуйте логику callback-функции\n\n", indent))
 
 	// Рендерим компонент заново
-	sb.WriteString(fmt.Sprintf("%s// Рендерим компонент с обновленным состоянием\n", indent))
-	if h.options.PackageName != "" && h.options.PackageName != "." {
-		sb.WriteString(fmt.Sprintf("%stempl.Handler(%s.%s(props, id)).ServeHTTP(w, r)\n", indent, h.options.PackageName, strings.ToLower(string(component.Name[0]))+component.Name[1:]))
-	} else {
-		sb.WriteString(fmt.Sprintf("%stempl.Handler(%s(props, id)).ServeHTTP(w, r)\n", indent, strings.ToLower(string(component.Name[0]))+component.Name[1:]))
-	}
+	h.generateRender(sb, component)
 
 	sb.WriteString("}\n\n")
 }
 
-// generateEffectHandler генерирует обработчик для useEffect
+// generateEffectHandler генерирует обработчик для useEffect, который вызывает разметка:
+// таймеры через hx-trigger, подписки через sse-connect
 func (h *StateHandler) generateEffectHandler(sb *strings.Builder, component *models.ReactComponent, effect models.EffectDefinition, index int) {
-	handlerName := fmt.Sprintf("Effect%d", index+1)
+	if effect.Kind == models.EffectKindSubscription {
+		h.generateSubscriptionHandler(sb, component, effect, index)
+		return
+	}
+
+	handlerName := effectHandlerName(component, index)
 
 	indent := h.getIndentation(1)
 
-	sb.WriteString(fmt.Sprintf("// %s обрабатывает эффект компонента\n", handlerName))
+	timer := "setInterval"
+	if effect.Kind == models.EffectKindTimeout {
+		timer = "setTimeout"
+	}
+	sb.WriteString(fmt.Sprintf("// %s обрабатывает эффект компонента (%s, вызывается через hx-trigger=\"%s\")\n", handlerName, timer, effectTrigger(effect)))
 	sb.WriteString(fmt.Sprintf("func %s(w http.ResponseWriter, r *http.Request) {\n", handlerName))
 	sb.WriteString(fmt.Sprintf("%s// Получаем ID компонента из запроса\n", indent))
 	sb.WriteString(fmt.Sprintf("%sid := r.URL.Query().Get(\"id\")\n", indent))
@@ -486,24 +602,501 @@ func (h *StateHandler) generateEffectHandler(sb *strings.Builder, component *mod
 	sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
 	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
 
-	// Получаем и проверяем состояние компонента (аналогично обработчику состояния)
-	// ...код аналогичен generateStateUpdater...
+	// Таймеры обновляют состояние и перерисовывают компонент
+	h.generateStateLoading(sb, component)
+
+	statements := translateSetterCalls(effect.Callback, component)
+	if len(statements) == 0 {
+		sb.WriteString(fmt.Sprintf("%s// TODO: Переведите колбэк таймера: %s\n", indent, singleLine(effect.Callback)))
+		statements = []string{"_ = state"}
+	}
+	h.generateStateSaving(sb, component, statements)
+
+	h.generateRender(sb, component)
+	sb.WriteString("}\n\n")
+}
+
+// generateSubscriptionHandler генерирует обработчик text/event-stream для подписки, к которому
+// подключается расширение htmx sse. Сервер сам подписывается на источник EventSource,
+// на каждое сообщение обновляет состояние, как обработчик onmessage, и отправляет
+// перерисованный компонент
+func (h *StateHandler) generateSubscriptionHandler(sb *strings.Builder, component *models.ReactComponent, effect models.EffectDefinition, index int) {
+	handlerName := effectHandlerName(component, index)
+
+	indent := h.getIndentation(1)
+	indent2 := h.getIndentation(2)
+	indent3 := h.getIndentation(3)
+
+	sb.WriteString(fmt.Sprintf("// %s передает обновления компонента через Server-Sent Events\n", handlerName))
+	if effect.Source != "" {
+		sb.WriteString(fmt.Sprintf("// Исходная подписка React: %s\n", effect.Source))
+	}
+	sb.WriteString(fmt.Sprintf("func %s(w http.ResponseWriter, r *http.Request) {\n", handlerName))
+	sb.WriteString(fmt.Sprintf("%s// Получаем ID компонента из запроса\n", indent))
+	sb.WriteString(fmt.Sprintf("%sid := r.URL.Query().Get(\"id\")\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif id == \"\" {\n", indent))
+	sb.WriteString(fmt.Sprintf("%shttp.Error(w, \"ID компонента не указан\", http.StatusBadRequest)\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sreturn\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+
+	// Состояние компонента, которое рендерится при каждом событии
+	h.generateStateLoading(sb, component)
+
+	source, sourceOK := h.subscriptionSource(effect)
+	if !sourceOK {
+		// Источник не удается открыть на сервере: соединение держится открытым до отключения клиента
+		sb.WriteString(fmt.Sprintf("%s// TODO: Подключите источник событий подписки и отправляйте перерисованный компонент на каждое событие\n", indent))
+		sb.WriteString(fmt.Sprintf("%s_ = state\n", indent))
+		sb.WriteString(fmt.Sprintf("%sw.Header().Set(\"Content-Type\", \"text/event-stream\")\n", indent))
+		sb.WriteString(fmt.Sprintf("%s<-r.Context().Done()\n", indent))
+		sb.WriteString("}\n\n")
+		return
+	}
+
+	// Подключение к источнику: адрес задан при конвертации и не зависит от запроса клиента
+	sb.WriteString(fmt.Sprintf("%s// Подключаемся к источнику событий исходной подписки\n", indent))
+	sb.WriteString(fmt.Sprintf("%ssourceRequest, err := http.NewRequestWithContext(r.Context(), http.MethodGet, %q, nil)\n", indent, source))
+	sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
+	sb.WriteString(fmt.Sprintf("%shttp.Error(w, \"Неверный адрес источника событий\", http.StatusInternalServerError)\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sreturn\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	sb.WriteString(fmt.Sprintf("%ssourceRequest.Header.Set(\"Accept\", \"text/event-stream\")\n", indent))
+	sb.WriteString(fmt.Sprintf("%sresponse, err := http.DefaultClient.Do(sourceRequest)\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
+	sb.WriteString(fmt.Sprintf("%shttp.Error(w, \"Источник событий недоступен\", http.StatusBadGateway)\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sreturn\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	sb.WriteString(fmt.Sprintf("%sdefer response.Body.Close()\n\n", indent))
+
+	sb.WriteString(fmt.Sprintf("%sflusher, ok := w.(http.Flusher)\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif !ok {\n", indent))
+	sb.WriteString(fmt.Sprintf("%shttp.Error(w, \"Потоковая передача не поддерживается\", http.StatusInternalServerError)\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sreturn\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+
+	sb.WriteString(fmt.Sprintf("%sw.Header().Set(\"Content-Type\", \"text/event-stream\")\n", indent))
+	sb.WriteString(fmt.Sprintf("%sw.Header().Set(\"Cache-Control\", \"no-cache\")\n", indent))
+	sb.WriteString(fmt.Sprintf("%sw.Header().Set(\"Connection\", \"keep-alive\")\n\n", indent))
+
+	if len(component.Props) > 0 {
+		sb.WriteString(fmt.Sprintf("%s// Получаем пропсы, переданные при создании экземпляра\n", indent))
+		sb.WriteString(fmt.Sprintf("%sprops := load%sProps(id)\n\n", indent, component.Name))
+	}
+
+	// Сообщение SSE - строки data: до пустой строки
+	sb.WriteString(fmt.Sprintf("%sscanner := bufio.NewScanner(response.Body)\n", indent))
+	sb.WriteString(fmt.Sprintf("%svar data []string\n", indent))
+	sb.WriteString(fmt.Sprintf("%sfor scanner.Scan() {\n", indent))
+	sb.WriteString(fmt.Sprintf("%sline := scanner.Text()\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sif value, ok := strings.CutPrefix(line, \"data:\"); ok {\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sdata = append(data, strings.TrimPrefix(value, \" \"))\n", indent3))
+	sb.WriteString(fmt.Sprintf("%scontinue\n", indent3))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sif line != \"\" || len(data) == 0 {\n", indent2))
+	sb.WriteString(fmt.Sprintf("%scontinue\n", indent3))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent2))
+	sb.WriteString(fmt.Sprintf("%smessage := strings.Join(data, \"\\n\")\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sdata = nil\n\n", indent2))
+
+	h.generateMessageUpdates(sb, component, effect, 2)
+
+	current := h.generateRenderedState(sb, component, 2)
+	sb.WriteString(fmt.Sprintf("%svar buf bytes.Buffer\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sif err := %s.Render(r.Context(), &buf); err != nil {\n", indent2, h.rootTemplCall(component, len(component.Props) > 0, current)))
+	sb.WriteString(fmt.Sprintf("%sreturn\n", indent3))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sfmt.Fprintf(w, \"data: %%s\\n\\n\", strings.ReplaceAll(buf.String(), \"\\n\", \"\\ndata: \"))\n", indent2))
+	sb.WriteString(fmt.Sprintf("%sflusher.Flush()\n", indent2))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+
+	// Поток завершается, когда клиент отключается (контекст запроса отменяет подписку) или источник закрывает соединение
+	sb.WriteString(fmt.Sprintf("%s// Клиент отключился или источник закрыл поток: соединение с источником закрывает defer,\n", indent))
+	sb.WriteString(fmt.Sprintf("%s// как функция очистки эффекта\n", indent))
+	sb.WriteString("}\n\n")
+}
+
+// subscriptionSource возвращает адрес источника подписки: относительный адрес разрешается
+// относительно SubscriptionBaseURL опций. false, если источник не удается открыть на сервере
+func (h *StateHandler) subscriptionSource(effect models.EffectDefinition) (string, bool) {
+	if !isSubscriptionURL(effect) {
+		return "", false
+	}
+	base, err := url.Parse(h.options.SubscriptionBaseURL)
+	if err != nil || (!base.IsAbs() && strings.HasPrefix(effect.Source, "/")) {
+		return "", false
+	}
+	source, err := base.Parse(effect.Source)
+	if err != nil {
+		return "", false
+	}
+	return source.String(), true
+}
+
+// generateMessageUpdates генерирует запись данных сообщения подписки в состояние
+// по обработчику onmessage. Сообщение, которое не удалось разобрать, пропускается
+func (h *StateHandler) generateMessageUpdates(sb *strings.Builder, component *models.ReactComponent, effect models.EffectDefinition, level int) {
+	indent := h.getIndentation(level)
+	inner := h.getIndentation(level + 1)
+
+	updates, ok := subscriptionUpdates(effect, component)
+	if !ok {
+		sb.WriteString(fmt.Sprintf("%s// TODO: Переведите обработчик сообщений подписки, данные сообщения: message\n", indent))
+		sb.WriteString(fmt.Sprintf("%s_ = message\n\n", indent))
+		return
+	}
+
+	var statements []string
+	readsMessage := false
+	for _, update := range updates {
+		readsMessage = readsMessage || update.Statement == ""
+		if update.Statement != "" {
+			statements = append(statements, update.Statement)
+			continue
+		}
+
+		field := exportedName(update.State.Name)
+		variable := update.State.Name + "Value"
+		goType := h.convertTypeToGo(update.State.Type, update.State.InitialValue)
+
+		switch {
+		case update.Conversion != messageJSON && goType == "string":
+			statements = append(statements, fmt.Sprintf("state.%s = message", field))
+		case update.Conversion != messageJSON && goType == "bool":
+			statements = append(statements, fmt.Sprintf("state.%s = message == \"true\"", field))
+		case update.Conversion != messageJSON && goType == "float64":
+			sb.WriteString(fmt.Sprintf("%s%s, err := strconv.ParseFloat(strings.TrimSpace(message), 64)\n", indent, variable))
+			sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
+			sb.WriteString(fmt.Sprintf("%scontinue\n", inner))
+			sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+			statements = append(statements, fmt.Sprintf("state.%s = %s", field, variable))
+		default:
+			sb.WriteString(fmt.Sprintf("%svar %s %s\n", indent, variable, goType))
+			sb.WriteString(fmt.Sprintf("%sif err := json.Unmarshal([]byte(message), &%s); err != nil {\n", indent, variable))
+			sb.WriteString(fmt.Sprintf("%scontinue\n", inner))
+			sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+			statements = append(statements, fmt.Sprintf("state.%s = %s", field, variable))
+		}
+	}
+
+	if !readsMessage {
+		sb.WriteString(fmt.Sprintf("%s_ = message\n", indent))
+	}
+	h.generateStateSavingAt(sb, component, statements, level)
+}
+
+// GenerateRoutes генерирует функцию регистрации HTTP маршрутов компонента
+func (h *StateHandler) GenerateRoutes(component *models.ReactComponent) string {
+	if len(component.State) == 0 && len(component.Reducers) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	indent := h.getIndentation(1)
+	basePath := "/api/" + strings.ToLower(component.Name)
+
+	sb.WriteString(fmt.Sprintf("// Register%sRoutes регистрирует HTTP маршруты компонента %s\n", component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("func Register%sRoutes(mux *http.ServeMux) {\n", component.Name))
+	sb.WriteString(fmt.Sprintf("%smux.HandleFunc(\"POST %s/new\", New%s)\n", indent, basePath, component.Name))
+
+	// Сеттеры: путь совпадает с тем, что генерирует JSX конвертер (setCount -> /count)
+	for _, state := range component.State {
+		if !needsSetterEndpoint(component, state) {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%smux.HandleFunc(\"POST %s/%s\", %s)\n", indent, basePath, setterRoute(state.Setter), setterHandlerName(component, state)))
+	}
+
+	for _, callback := range component.Callbacks {
+		if isClientCallback(component, callback) {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%smux.HandleFunc(\"POST %s/%s\", %s)\n", indent, basePath, strings.ToLower(callback.Name), callbackHandlerName(component, callback)))
+	}
+
+	for i, effect := range component.Effects {
+		if !needsEffectHandler(effect) {
+			continue
+		}
+		method := "POST"
+		if effect.Kind == models.EffectKindSubscription {
+			method = "GET"
+		}
+		sb.WriteString(fmt.Sprintf("%smux.HandleFunc(\"%s %s\", %s)\n", indent, method, effectRoutePath(component, i), effectHandlerName(component, i)))
+	}
+
+	for _, reducer := range component.Reducers {
+		sb.WriteString(fmt.Sprintf("%smux.HandleFunc(\"POST %s/%s\", %s)\n", indent, basePath, reducer.Dispatch, dispatchHandlerName(component, reducer)))
+	}
+
+	sb.WriteString(fmt.Sprintf("%smux.HandleFunc(\"POST %s/cleanup\", Cleanup%s)\n", indent, basePath, component.Name))
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// generateStateLoading генерирует код получения состояния компонента по id
+func (h *StateHandler) generateStateLoading(sb *strings.Builder, component *models.ReactComponent) {
+	indent := h.getIndentation(1)
+
+	switch h.options.StatePersistence {
+	case "redis":
+		sb.WriteString(fmt.Sprintf("%s// Получаем состояние из Redis\n", indent))
+		sb.WriteString(fmt.Sprintf("%sjsonState, err := redisClient.Get(context.Background(), %sKeyPrefix+id).Bytes()\n", indent, strings.ToLower(component.Name)))
+		sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
+		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Состояние компонента не найдено\", http.StatusNotFound)\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+
+		sb.WriteString(fmt.Sprintf("%svar state %sState\n", indent, component.Name))
+		sb.WriteString(fmt.Sprintf("%sif err := json.Unmarshal(jsonState, &state); err != nil {\n", indent))
+		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Ошибка десериализации состояния\", http.StatusInternalServerError)\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+
+	case "database":
+		sb.WriteString(fmt.Sprintf("%s// Получаем состояние из БД\n", indent))
+		sb.WriteString(fmt.Sprintf("%sstate, err := %sRepository.GetState(id)\n", indent, strings.ToLower(component.Name)))
+		sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
+		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Состояние компонента не найдено\", http.StatusNotFound)\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+
+	default:
+		// Экземпляры дочерних компонентов регистрирует загрузка состояния при рендеринге
+		// родителя (Load<Name>State): неизвестный id - ошибка клиента
+		sb.WriteString(fmt.Sprintf("%s// Получаем состояние из памяти\n", indent))
+		sb.WriteString(fmt.Sprintf("%s%sMutex.RLock()\n", indent, strings.ToLower(component.Name)))
+		sb.WriteString(fmt.Sprintf("%sstate, ok := %sStates[id]\n", indent, strings.ToLower(component.Name)))
+		sb.WriteString(fmt.Sprintf("%s%sMutex.RUnlock()\n", indent, strings.ToLower(component.Name)))
+		sb.WriteString(fmt.Sprintf("%sif !ok {\n", indent))
+		sb.WriteString(fmt.Sprintf("%s%shttp.Error(w, \"Состояние компонента не найдено\", http.StatusNotFound)\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
+		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+	}
+}
+
+// generateStateLoader генерирует загрузку состояния экземпляра по id для шаблонов родительских
+// компонентов проекта (экземпляр создается с начальным состоянием при первом обращении)
+// и middleware, которое передает ее шаблонам в контексте запроса
+func (h *StateHandler) generateStateLoader(sb *strings.Builder, component *models.ReactComponent) {
+	if !h.isProjectFile() {
+		return
+	}
+
+	indent := h.getIndentation(1)
+	inner := h.getIndentation(2)
+	prefix := strings.ToLower(component.Name)
+	loader := "load" + component.Name + "State"
+
+	sb.WriteString(fmt.Sprintf("// %sStateMiddleware передает шаблонам родительских компонентов загрузку состояния\n", component.Name))
+	sb.WriteString(fmt.Sprintf("// экземпляров %s из хранилища (%s)\n", component.Name, stateLoaderName(component.Name)))
+	sb.WriteString(fmt.Sprintf("func %sStateMiddleware(next http.Handler) http.Handler {\n", component.Name))
+	sb.WriteString(fmt.Sprintf("%sreturn http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {\n", indent))
+	sb.WriteString(fmt.Sprintf("%snext.ServeHTTP(w, r.WithContext(%s(r.Context(), %s)))\n", inner, h.qualifiedName("With"+component.Name+"StateLoader"), loader))
+	sb.WriteString(fmt.Sprintf("%s})\n", indent))
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(fmt.Sprintf("// %s возвращает состояние экземпляра компонента по id\n", loader))
+	sb.WriteString(fmt.Sprintf("func %s(id string) %sState {\n", loader, component.Name))
+
+	switch h.options.StatePersistence {
+	case "redis":
+		sb.WriteString(fmt.Sprintf("%sif jsonState, err := redisClient.Get(context.Background(), %sKeyPrefix+id).Bytes(); err == nil {\n", indent, prefix))
+		sb.WriteString(fmt.Sprintf("%svar state %sState\n", inner, component.Name))
+		sb.WriteString(fmt.Sprintf("%sif err := json.Unmarshal(jsonState, &state); err == nil {\n", inner))
+		sb.WriteString(fmt.Sprintf("%s%sreturn state\n", inner, indent))
+		sb.WriteString(fmt.Sprintf("%s}\n", inner))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+		sb.WriteString(fmt.Sprintf("%sstate := %s\n", indent, h.initialStateLiteral(component, 1)))
+		sb.WriteString(fmt.Sprintf("%sjsonState, _ := json.Marshal(state)\n", indent))
+		sb.WriteString(fmt.Sprintf("%sredisClient.Set(context.Background(), %sKeyPrefix+id, jsonState, 24*time.Hour)\n", indent, prefix))
+	case "database":
+		sb.WriteString(fmt.Sprintf("%sif state, err := %sRepository.GetState(id); err == nil {\n", indent, prefix))
+		sb.WriteString(fmt.Sprintf("%sreturn *state\n", inner))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+		sb.WriteString(fmt.Sprintf("%sstate := %s\n", indent, h.initialStateLiteral(component, 1)))
+		sb.WriteString(fmt.Sprintf("%s%sRepository.SaveState(id, state)\n", indent, prefix))
+	default:
+		sb.WriteString(fmt.Sprintf("%s%sMutex.Lock()\n", indent, prefix))
+		sb.WriteString(fmt.Sprintf("%sdefer %sMutex.Unlock()\n", indent, prefix))
+		sb.WriteString(fmt.Sprintf("%sstate, ok := %sStates[id]\n", indent, prefix))
+		sb.WriteString(fmt.Sprintf("%sif !ok {\n", indent))
+		sb.WriteString(fmt.Sprintf("%sstate = %s\n", inner, h.initialStateLiteral(component, 2)))
+		sb.WriteString(fmt.Sprintf("%s%sStates[id] = state\n", inner, prefix))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	sb.WriteString(fmt.Sprintf("%sreturn *state\n", indent))
+	sb.WriteString("}\n\n")
+}
+
+// isProjectFile проверяет, конвертируется ли компонент в составе проекта
+// (ConvertProject): его могут выводить родительские компоненты из других файлов
+func (h *StateHandler) isProjectFile() bool {
+	return h.options.Components != nil
+}
+
+// stateLoaderName возвращает имя функции пакета шаблонов, загружающей состояние экземпляра
+func stateLoaderName(component string) string {
+	return "Load" + component + "State"
+}
+
+// initialStateLiteral возвращает указатель на начальное состояние компонента (&CounterState{...})
+// с полями на отступ глубже level
+func (h *StateHandler) initialStateLiteral(component *models.ReactComponent, level int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("&%sState{\n", component.Name))
+	for _, field := range h.InitialStateFields(component) {
+		sb.WriteString(fmt.Sprintf("%s%s,\n", h.getIndentation(level+1), field))
+	}
+	sb.WriteString(h.getIndentation(level) + "}")
+	return sb.String()
+}
+
+// generateStateSaving генерирует код изменения состояния с учетом способа хранения
+func (h *StateHandler) generateStateSaving(sb *strings.Builder, component *models.ReactComponent, statements []string) {
+	h.generateStateSavingAt(sb, component, statements, 1)
+}
+
+// generateStateSavingAt генерирует изменение состояния на заданном уровне отступа
+func (h *StateHandler) generateStateSavingAt(sb *strings.Builder, component *models.ReactComponent, statements []string, level int) {
+	statements = withDerivedUpdates(component, statements)
+	indent := h.getIndentation(level)
+	inner := h.getIndentation(level + 1)
+
+	sb.WriteString(fmt.Sprintf("%s// Обновляем состояние\n", indent))
+
+	switch h.options.StatePersistence {
+	case "redis":
+		for _, statement := range statements {
+			sb.WriteString(fmt.Sprintf("%s%s\n", indent, statement))
+		}
+		sb.WriteString(fmt.Sprintf("%sjsonState, _ = json.Marshal(state)\n", indent))
+		sb.WriteString(fmt.Sprintf("%sredisClient.Set(context.Background(), %sKeyPrefix+id, jsonState, 24*time.Hour)\n\n", indent, strings.ToLower(component.Name)))
+
+	case "database":
+		for _, statement := range statements {
+			sb.WriteString(fmt.Sprintf("%s%s\n", indent, statement))
+		}
+		sb.WriteString(fmt.Sprintf("%sif err := %sRepository.UpdateState(id, state); err != nil {\n", indent, strings.ToLower(component.Name)))
+		sb.WriteString(fmt.Sprintf("%shttp.Error(w, \"Ошибка обновления состояния\", http.StatusInternalServerError)\n", inner))
+		sb.WriteString(fmt.Sprintf("%sreturn\n", inner))
+		sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
+
+	default:
+		sb.WriteString(fmt.Sprintf("%s%sMutex.Lock()\n", indent, strings.ToLower(component.Name)))
+		for _, statement := range statements {
+			sb.WriteString(fmt.Sprintf("%s%s\n", indent, statement))
+		}
+		sb.WriteString(fmt.Sprintf("%s%sMutex.Unlock()\n\n", indent, strings.ToLower(component.Name)))
+	}
+}
+
+// generateRender генерирует рендеринг компонента в ответ на запрос
+func (h *StateHandler) generateRender(sb *strings.Builder, component *models.ReactComponent) {
+	indent := h.getIndentation(1)
 
-	// Логика эффекта (зависит от конкретного эффекта)
-	sb.WriteString(fmt.Sprintf("%s// Реализация эффекта\n", indent))
-	sb.WriteString(fmt.Sprintf("%s// TODO: Реализуйте логику эффекта на основе его тела и зависимостей\n", indent))
-	if len(effect.Dependencies) > 0 {
-		sb.WriteString(fmt.Sprintf("%s// Зависимости: %s\n", indent, strings.Join(effect.Dependencies, ", ")))
+	if len(component.Props) > 0 {
+		sb.WriteString(fmt.Sprintf("%s// Получаем пропсы, переданные при создании экземпляра\n", indent))
+		sb.WriteString(fmt.Sprintf("%sprops := load%sProps(id)\n\n", indent, component.Name))
 	}
-	sb.WriteString("\n")
 
-	// Возвращаем успешный статус
-	sb.WriteString(fmt.Sprintf("%s// Возвращаем успешный статус\n", indent))
-	sb.WriteString(fmt.Sprintf("%sw.WriteHeader(http.StatusOK)\n", indent))
+	sb.WriteString(fmt.Sprintf("%s// Рендерим компонент с обновленным состоянием\n", indent))
+	current := h.generateRenderedState(sb, component, 1)
+	sb.WriteString(fmt.Sprintf("%stempl.Handler(%s).ServeHTTP(w, r)\n", indent, h.rootTemplCall(component, len(component.Props) > 0, current)))
+}
 
+// templCall возвращает вызов templ компонента с учетом пакета шаблонов.
+// stateArg - выражение со значением состояния компонента
+func (h *StateHandler) templCall(component *models.ReactComponent, withProps bool, stateArg string) string {
+	return fmt.Sprintf("%s(%s)", h.qualifiedName(exportedName(component.Name)), h.templArgs(component, withProps, stateArg))
+}
+
+// rootTemplCall возвращает вызов templ компонента с корневым элементом: обработчики
+// перерисовывают только его, оболочка компонента с триггерами эффектов остается на странице
+func (h *StateHandler) rootTemplCall(component *models.ReactComponent, withProps bool, stateArg string) string {
+	return fmt.Sprintf("%s(%s)", h.qualifiedName(rootTemplName(component, h.options)), h.templArgs(component, withProps, stateArg))
+}
+
+// templArgs возвращает аргументы вызова templ компонента (и его фрагментов):
+// пропсы, id и состояние - в порядке параметров templ компонента
+func (h *StateHandler) templArgs(component *models.ReactComponent, withProps bool, stateArg string) string {
+	args := "id"
+	if withProps {
+		args = "props, id"
+	}
+	if len(component.State) > 0 || len(component.Reducers) > 0 {
+		args += ", " + stateArg
+	}
+	return args
+}
+
+// generateRenderedState генерирует получение состояния, которое рендерит шаблон, и возвращает
+// его выражение. Состояние в памяти копируется под блокировкой: другие запросы изменяют его,
+// пока шаблон рендерится
+func (h *StateHandler) generateRenderedState(sb *strings.Builder, component *models.ReactComponent, level int) string {
+	switch h.options.StatePersistence {
+	case "redis":
+		return "state"
+	case "database":
+		return "*state"
+	}
+	sb.WriteString(fmt.Sprintf("%scurrent := snapshot%sState(state)\n", h.getIndentation(level), component.Name))
+	return "current"
+}
+
+// generateStateSnapshot генерирует копирование состояния экземпляра под блокировкой
+// хранилища в памяти
+func (h *StateHandler) generateStateSnapshot(sb *strings.Builder, component *models.ReactComponent) {
+	if h.options.StatePersistence == "redis" || h.options.StatePersistence == "database" {
+		return
+	}
+
+	indent := h.getIndentation(1)
+	prefix := strings.ToLower(component.Name)
+
+	sb.WriteString(fmt.Sprintf("// snapshot%sState возвращает копию состояния экземпляра, сделанную под блокировкой\n", component.Name))
+	sb.WriteString(fmt.Sprintf("func snapshot%sState(state *%sState) %sState {\n", component.Name, component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("%s%sMutex.RLock()\n", indent, prefix))
+	sb.WriteString(fmt.Sprintf("%sdefer %sMutex.RUnlock()\n", indent, prefix))
+	sb.WriteString(fmt.Sprintf("%sreturn *state\n", indent))
 	sb.WriteString("}\n\n")
 }
 
+// generatePropsLoader генерирует функцию load<Name>Props: пропсы, сохраненные при создании
+// экземпляра, или пропсы по умолчанию, если экземпляр создан не через New<Name>
+func (h *StateHandler) generatePropsLoader(sb *strings.Builder, component *models.ReactComponent) {
+	if len(component.Props) == 0 {
+		return
+	}
+
+	indent := h.getIndentation(1)
+	prefix := strings.ToLower(component.Name)
+	propsType := h.qualifiedName(component.Name + "Props")
+
+	sb.WriteString(fmt.Sprintf("// load%sProps возвращает пропсы, переданные при создании экземпляра, или пропсы по умолчанию\n", component.Name))
+	sb.WriteString(fmt.Sprintf("func load%sProps(id string) %s {\n", component.Name, propsType))
+	sb.WriteString(fmt.Sprintf("%s%sPropsMutex.RLock()\n", indent, prefix))
+	sb.WriteString(fmt.Sprintf("%sdefer %sPropsMutex.RUnlock()\n", indent, prefix))
+	sb.WriteString(fmt.Sprintf("%sif props, ok := %sProps[id]; ok {\n", indent, prefix))
+	sb.WriteString(fmt.Sprintf("%s%sreturn props\n", indent, indent))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	if models.HasPropDefaults(component) {
+		sb.WriteString(fmt.Sprintf("%sreturn %s()\n", indent, h.qualifiedName("New"+component.Name+"Props")))
+	} else {
+		sb.WriteString(fmt.Sprintf("%sreturn %s{}\n", indent, propsType))
+	}
+	sb.WriteString("}\n\n")
+}
+
+// qualifiedName добавляет к имени префикс пакета шаблонов, если он задан
+func (h *StateHandler) qualifiedName(name string) string {
+	if h.options.PackageName != "" && h.options.PackageName != "." {
+		return h.options.PackageName + "." + name
+	}
+	return name
+}
+
 // Utility functions
 
 // convertTypeToGo преобразует тип TypeScript/JavaScript в тип Go
@@ -532,6 +1125,9 @@ func (h *StateHandler) convertTypeToGo(tsType string, value interface{}) string
 			goElementType := h.convertTypeToGo(elementType, nil)
 			return "[]" + goElementType
 		}
+		if strings.HasSuffix(tsType, "[]") {
+			return "[]" + h.convertTypeToGo(strings.TrimSuffix(tsType, "[]"), nil)
+		}
 		return "interface{}"
 	}
 }
@@ -634,3 +1230,57 @@ func isEffectForDataFetching(effect models.EffectDefinition) bool {
 
 	return hasFetch && !hasOtherEffects
 }
+
+// isClientEffect проверяет, выполняется ли эффект в клиентском JavaScript.
+// Таймеры и подписки запускаются через hx-trigger и SSE, пересчет производных
+// состояний выполняют обработчики, меняющие зависимости эффекта
+func isClientEffect(component *models.ReactComponent, effect models.EffectDefinition) bool {
+	return (effect.Kind == "" || effect.Kind == models.EffectKindGeneric) && !isEffectForDataFetching(effect) &&
+		!isDerivedStateEffect(component, effect)
+}
+
+// setterRoute возвращает сегмент пути для сеттера (setIsOpen -> isOpen)
+func setterRoute(setter string) string {
+	route := strings.TrimPrefix(setter, "set")
+	if route == "" {
+		return strings.ToLower(setter)
+	}
+	return strings.ToLower(route[:1]) + route[1:]
+}
+
+// setterHandlerName возвращает имя обработчика сеттера. Имя компонента в начале не дает
+// обработчикам разных компонентов одного пакета совпасть (CounterSetCount)
+func setterHandlerName(component *models.ReactComponent, state models.StateDefinition) string {
+	return component.Name + exportedName(state.Setter)
+}
+
+// callbackHandlerName возвращает имя обработчика callback-функции компонента
+func callbackHandlerName(component *models.ReactComponent, callback models.CallbackDefinition) string {
+	return component.Name + exportedName(callback.Name)
+}
+
+// effectHandlerName возвращает имя обработчика эффекта компонента по его номеру
+func effectHandlerName(component *models.ReactComponent, index int) string {
+	return fmt.Sprintf("%sEffect%d", component.Name, index+1)
+}
+
+// effectBodyLines возвращает строки тела эффекта без внешних фигурных скобок
+func effectBodyLines(body string) []string {
+	body = strings.TrimSpace(body)
+	if strings.HasPrefix(body, "{") && strings.HasSuffix(body, "}") {
+		body = body[1 : len(body)-1]
+	}
+
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// singleLine сворачивает многострочный код в одну строку для комментария
+func singleLine(code string) string {
+	return strings.Join(strings.Fields(code), " ")
+}
@@ -0,0 +1,349 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Атрибут, которым resolveStyledComponents отмечает элемент стилизованного компонента
+const styledMarkerProp = "__styled__"
+
+var (
+	// Метка подстановки ${...} в тексте шаблона стилей (см. styledTemplate)
+	styledPlaceholderRegex = regexp.MustCompile(`\x00(\d+)\x00`)
+	// Функция, читающая пропс: p => p.color, (props) => props.$size
+	styledAccessorRegex = regexp.MustCompile(`^\(?\s*(\w+)\s*\)?\s*=>\s*(\w+)\.(\$?[A-Za-z_]\w*)$`)
+	// Функция с деструктуризацией пропса: ({ color }) => color
+	styledDestructuredRegex = regexp.MustCompile(`^\(\s*\{\s*(\$?[A-Za-z_]\w*)\s*\}\s*\)\s*=>\s*(\$?[A-Za-z_]\w*)$`)
+	// Имя свойства, которое принимает templ css компонент
+	templCSSPropertyRegex = regexp.MustCompile(`^-{0,2}[a-z][a-zA-Z0-9-]*$`)
+)
+
+// styledStyles - стили стилизованного компонента, разобранные из шаблона: свойства
+// верхнего уровня выводит templ css компонент, вложенные правила (&:hover, @media) -
+// таблица стилей с уникальным классом компонента
+type styledStyles struct {
+	definition   models.StyledDefinition
+	declarations []styledDeclaration
+	params       []string
+	nested       []cssStatement
+}
+
+// styledDeclaration - свойство templ css компонента: постоянное значение или
+// выражение Go с параметром (пропсом стилизованного компонента)
+type styledDeclaration struct {
+	name   string
+	value  string
+	goExpr string
+}
+
+// styledTemplate собирает текст шаблона стилей, заменяя подстановки метками с их номером.
+// Метки не содержат скобок и кавычек, поэтому не мешают разбору CSS
+func styledTemplate(definition models.StyledDefinition) string {
+	var sb strings.Builder
+	for i, quasi := range definition.Quasis {
+		sb.WriteString(quasi)
+		if i < len(definition.Expressions) {
+			sb.WriteString(fmt.Sprintf("\x00%d\x00", i))
+		}
+	}
+	return sb.String()
+}
+
+// analyzeStyled разбирает шаблон стилей. Подстановки поддерживаются только в значениях
+// свойств верхнего уровня и только как чтение пропса (p => p.color); остальные свойства
+// и правила с подстановками пропускаются с предупреждением
+func analyzeStyled(definition models.StyledDefinition) (*styledStyles, []string) {
+	styles := &styledStyles{definition: definition}
+	var warnings []string
+	skip := func(what string) {
+		warnings = append(warnings, fmt.Sprintf("Стилизованный компонент %s: %s пропущено, подстановка не поддерживается", definition.Name, what))
+	}
+
+	for _, statement := range parseCSS(styledTemplate(definition)) {
+		if statement.prelude != "" {
+			if containsPlaceholder(statement) {
+				skip(fmt.Sprintf("правило %q", statement.prelude))
+				continue
+			}
+			styles.nested = append(styles.nested, statement)
+			continue
+		}
+
+		name, value, ok := splitDeclaration(statement.declaration)
+		if !ok || !templCSSPropertyRegex.MatchString(name) {
+			skip(fmt.Sprintf("объявление %q", statement.declaration))
+			continue
+		}
+
+		placeholders := styledPlaceholderRegex.FindAllStringSubmatchIndex(value, -1)
+		if len(placeholders) == 0 {
+			styles.declarations = append(styles.declarations, styledDeclaration{name: name, value: value})
+			continue
+		}
+
+		index, _ := strconv.Atoi(value[placeholders[0][2]:placeholders[0][3]])
+		prop := styledAccessorProp(definition.Expressions[index])
+		if len(placeholders) > 1 || prop == "" {
+			skip(fmt.Sprintf("объявление %q", name))
+			continue
+		}
+
+		param := styledParamName(prop)
+		if !containsString(styles.params, prop) {
+			styles.params = append(styles.params, prop)
+		}
+		var parts []string
+		if prefix := value[:placeholders[0][0]]; prefix != "" {
+			parts = append(parts, strconv.Quote(prefix))
+		}
+		parts = append(parts, param)
+		if suffix := value[placeholders[0][1]:]; suffix != "" {
+			parts = append(parts, strconv.Quote(suffix))
+		}
+		styles.declarations = append(styles.declarations, styledDeclaration{name: name, goExpr: strings.Join(parts, " + ")})
+	}
+	return styles, warnings
+}
+
+// containsPlaceholder проверяет, есть ли подстановки в правиле или вложенных в него правилах
+func containsPlaceholder(statement cssStatement) bool {
+	if styledPlaceholderRegex.MatchString(statement.prelude) || styledPlaceholderRegex.MatchString(statement.declaration) {
+		return true
+	}
+	for _, rule := range statement.rules {
+		if containsPlaceholder(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// styledAccessorProp возвращает имя пропса, который читает функция подстановки
+func styledAccessorProp(code string) string {
+	code = strings.TrimSpace(code)
+	if match := styledAccessorRegex.FindStringSubmatch(code); match != nil && match[1] == match[2] {
+		return match[3]
+	}
+	if match := styledDestructuredRegex.FindStringSubmatch(code); match != nil && match[1] == match[2] {
+		return match[1]
+	}
+	return ""
+}
+
+// styledParamName возвращает имя параметра templ css компонента для пропса ($size -> size)
+func styledParamName(prop string) string {
+	return strings.TrimPrefix(prop, "$")
+}
+
+// findStyled возвращает стилизованный компонент по имени
+func findStyled(component *models.ReactComponent, name string) *models.StyledDefinition {
+	if component == nil {
+		return nil
+	}
+	for i := range component.Styled {
+		if component.Styled[i].Name == name {
+			return &component.Styled[i]
+		}
+	}
+	return nil
+}
+
+// resolveStyledComponents заменяет элементы стилизованных компонентов (<Title>) их HTML
+// тегами с отметкой styledMarkerProp и возвращает таблицу стилей вложенных правил
+func resolveStyledComponents(component *models.ReactComponent) (string, []string) {
+	if len(component.Styled) == 0 {
+		return "", nil
+	}
+
+	var stylesheet strings.Builder
+	var warnings []string
+	for _, definition := range component.Styled {
+		styles, styleWarnings := analyzeStyled(definition)
+		warnings = append(warnings, styleWarnings...)
+		if len(styles.nested) > 0 {
+			stylesheet.WriteString(fmt.Sprintf("/* %s.%s */\n", component.Name, definition.Name))
+			writeCSS(&stylesheet, scopeStyledRules(styles.nested, []string{"." + scopedClassName(component.Name, definition.Name)}), 0)
+		}
+	}
+
+	if component.JSX != nil {
+		rewriter := jsxRewriter{element: func(elementType string, props map[string]interface{}) string {
+			definition := findStyled(component, elementType)
+			if definition == nil {
+				return elementType
+			}
+			props[styledMarkerProp] = definition.Name
+			return definition.Tag
+		}}
+		rewriter.rewrite(component.JSX)
+	}
+	return stylesheet.String(), warnings
+}
+
+// scopeStyledRules переводит вложенные правила в правила с классом компонента:
+// & заменяется селектором родителя, селектор без & становится селектором потомка.
+// Свойства внутри @media применяются к самому элементу
+func scopeStyledRules(statements []cssStatement, parents []string) []cssStatement {
+	var scoped []cssStatement
+	for _, statement := range statements {
+		var declarations, rules []cssStatement
+		for _, rule := range statement.rules {
+			if rule.prelude == "" {
+				declarations = append(declarations, rule)
+			} else {
+				rules = append(rules, rule)
+			}
+		}
+
+		if strings.HasPrefix(statement.prelude, "@") {
+			var body []cssStatement
+			if len(declarations) > 0 {
+				body = append(body, cssStatement{prelude: strings.Join(parents, ", "), rules: declarations})
+			}
+			scoped = append(scoped, cssStatement{prelude: statement.prelude, rules: append(body, scopeStyledRules(rules, parents)...)})
+			continue
+		}
+
+		var selectors []string
+		for _, parent := range parents {
+			for _, selector := range splitTopLevel(statement.prelude, ',') {
+				selector = strings.TrimSpace(selector)
+				if strings.Contains(selector, "&") {
+					selectors = append(selectors, strings.ReplaceAll(selector, "&", parent))
+				} else {
+					selectors = append(selectors, parent+" "+selector)
+				}
+			}
+		}
+		if len(declarations) > 0 {
+			scoped = append(scoped, cssStatement{prelude: strings.Join(selectors, ", "), rules: declarations})
+		}
+		scoped = append(scoped, scopeStyledRules(rules, selectors)...)
+	}
+	return scoped
+}
+
+// styledClassAttribute выводит атрибут class элемента стилизованного компонента:
+// вызов templ css компонента со свойствами верхнего уровня, уникальный класс для
+// вложенных правил и классы из className
+func (c *JSXToHTMXConverter) styledClassAttribute(jsx *models.JSXElement) string {
+	name, _ := jsx.Props[styledMarkerProp].(string)
+	definition := findStyled(c.component, name)
+	if definition == nil {
+		return ""
+	}
+	styles, _ := analyzeStyled(*definition)
+
+	var items []string
+	if len(styles.declarations) > 0 {
+		var args []string
+		for _, prop := range styles.params {
+			args = append(args, c.styledArgument(jsx.Props[prop]))
+		}
+		items = append(items, fmt.Sprintf("%s(%s)", c.styledCSSComponent(styles), strings.Join(args, ", ")))
+	}
+	if len(styles.nested) > 0 {
+		items = append(items, strconv.Quote(scopedClassName(c.component.Name, definition.Name)))
+	}
+
+	switch className := jsx.Props["className"].(type) {
+	case string:
+		for _, class := range strings.Fields(className) {
+			items = append(items, strconv.Quote(class))
+		}
+	case map[string]interface{}:
+		code, _ := className["code"].(string)
+		if classItems, ok := c.classItems(code); ok {
+			for _, item := range classItems {
+				items = append(items, item.goArg())
+			}
+		} else if literal, ok := models.ParseJSLiteral(code); ok {
+			if value, ok := literal.(string); ok {
+				for _, class := range strings.Fields(value) {
+					items = append(items, strconv.Quote(class))
+				}
+			}
+		} else {
+			items = append(items, c.stringExpression(c.convertReactExprToGoExpr(code)))
+		}
+	}
+
+	if len(items) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" class={ %s }", strings.Join(items, ", "))
+}
+
+// styledCSSComponent генерирует templ css компонент стилизованного компонента
+// (один раз на компонент) и возвращает его имя
+func (c *JSXToHTMXConverter) styledCSSComponent(styles *styledStyles) string {
+	name := fmt.Sprintf("%s%s%sCSS", strings.ToLower(c.componentName()[:1]), c.componentName()[1:], styles.definition.Name)
+	if c.styledCSS[name] {
+		return name
+	}
+	if c.styledCSS == nil {
+		c.styledCSS = make(map[string]bool)
+	}
+	c.styledCSS[name] = true
+
+	var params []string
+	for _, prop := range styles.params {
+		params = append(params, styledParamName(prop)+" string")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// %s - стили %s = styled.%s компонента %s\n", name, styles.definition.Name, styles.definition.Tag, c.componentName()))
+	sb.WriteString(fmt.Sprintf("css %s(%s) {\n", name, strings.Join(params, ", ")))
+	for _, declaration := range styles.declarations {
+		if declaration.goExpr != "" {
+			sb.WriteString(fmt.Sprintf("\t%s: { %s };\n", declaration.name, declaration.goExpr))
+		} else {
+			sb.WriteString(fmt.Sprintf("\t%s: %s;\n", declaration.name, declaration.value))
+		}
+	}
+	sb.WriteString("}\n\n")
+	c.regionTemplates = append(c.regionTemplates, sb.String())
+	return name
+}
+
+// styledArgument переводит значение пропса стилизованного компонента в строку Go для
+// параметра templ css компонента. Отсутствующий пропс дает пустое значение
+func (c *JSXToHTMXConverter) styledArgument(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case map[string]interface{}:
+		code, _ := v["code"].(string)
+		if literal, ok := models.ParseJSLiteral(code); ok {
+			switch literal.(type) {
+			case string, float64:
+				return strconv.Quote(fmt.Sprint(literal))
+			}
+			return `""`
+		}
+		return c.stringExpression(c.convertReactExprToGoExpr(code))
+	}
+	return `""`
+}
+
+// isStyledParam проверяет, передается ли атрибут элемента в templ css компонент
+// (такие пропсы стилизованный компонент не выводит в HTML)
+func (c *JSXToHTMXConverter) isStyledParam(jsx *models.JSXElement, name string) bool {
+	styledName, ok := jsx.Props[styledMarkerProp].(string)
+	if !ok {
+		return false
+	}
+	definition := findStyled(c.component, styledName)
+	if definition == nil {
+		return false
+	}
+	if strings.HasPrefix(name, "$") {
+		return true
+	}
+	styles, _ := analyzeStyled(*definition)
+	return containsString(styles.params, name)
+}
@@ -0,0 +1,262 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Свойства CSS, к числовым значениям которых React не добавляет px
+var unitlessCSSProperties = map[string]bool{
+	"animationIterationCount": true, "aspectRatio": true, "borderImageOutset": true,
+	"borderImageSlice": true, "borderImageWidth": true, "boxFlex": true, "boxFlexGroup": true,
+	"boxOrdinalGroup": true, "columnCount": true, "columns": true, "flex": true, "flexGrow": true,
+	"flexPositive": true, "flexShrink": true, "flexNegative": true, "flexOrder": true,
+	"gridArea": true, "gridRow": true, "gridRowEnd": true, "gridRowSpan": true, "gridRowStart": true,
+	"gridColumn": true, "gridColumnEnd": true, "gridColumnSpan": true, "gridColumnStart": true,
+	"fontWeight": true, "lineClamp": true, "lineHeight": true, "opacity": true, "order": true,
+	"orphans": true, "scale": true, "tabSize": true, "widows": true, "zIndex": true, "zoom": true,
+	"fillOpacity": true, "floodOpacity": true, "stopOpacity": true, "strokeDasharray": true,
+	"strokeDashoffset": true, "strokeMiterlimit": true, "strokeOpacity": true, "strokeWidth": true,
+}
+
+// Префиксы производителей в именах свойств объекта стилей: WebkitLineClamp, msFlex
+var vendorPrefixRegex = regexp.MustCompile(`^(Webkit|Moz|O|ms)([A-Z])`)
+
+// Идентификатор JavaScript, пригодный и как имя переменной Go
+var goIdentifierRegex = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+
+// cssPropertyName переводит имя свойства объекта стилей React в имя CSS:
+// fontSize -> font-size, WebkitTransition -> -webkit-transition, msFlex -> -ms-flex.
+// Пользовательские свойства (--accent) остаются как есть
+func cssPropertyName(key string) string {
+	if strings.HasPrefix(key, "--") {
+		return key
+	}
+	name := camelCaseToKebabCase(key)
+	if vendorPrefixRegex.MatchString(key) {
+		name = "-" + name
+	}
+	return name
+}
+
+// isUnitlessCSSProperty проверяет, выводит ли React число в свойстве без единиц измерения
+func isUnitlessCSSProperty(key string) bool {
+	if strings.HasPrefix(key, "--") {
+		return true
+	}
+	if match := vendorPrefixRegex.FindStringSubmatch(key); match != nil {
+		key = strings.ToLower(match[2]) + key[len(match[0]):]
+	}
+	return unitlessCSSProperties[key]
+}
+
+// cssValue форматирует литеральное значение свойства так же, как React: к числам,
+// кроме нуля и свойств без единиц (opacity, zIndex...), добавляется px.
+// ok = false для значений, которые React не выводит (null, undefined, булевы, пустая строка)
+func cssValue(key string, value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		v = strings.TrimSpace(v)
+		return v, v != ""
+	case float64:
+		formatted := strconv.FormatFloat(v, 'f', -1, 64)
+		if v != 0 && !isUnitlessCSSProperty(key) {
+			formatted += "px"
+		}
+		return formatted, true
+	}
+	return "", false
+}
+
+// staticStyle переводит объект стилей из литералов в строку CSS в формате React:
+// {color: 'red', fontSize: 12} -> "color:red;font-size:12px"
+func staticStyle(object *models.JSObject) string {
+	var declarations []string
+	for _, key := range object.Keys {
+		if value, ok := cssValue(key, object.Values[key]); ok {
+			declarations = append(declarations, cssPropertyName(key)+":"+value)
+		}
+	}
+	return strings.Join(declarations, ";")
+}
+
+// styleEntries разбирает объектный литерал стилей на пары ключ-выражение в порядке
+// исходного кода. ok = false, если стиль - не литерал ({...base}, вычисляемые ключи, переменная)
+func styleEntries(code string) ([][2]string, bool) {
+	code = strings.TrimSpace(code)
+	if !strings.HasPrefix(code, "{") || !strings.HasSuffix(code, "}") {
+		return nil, false
+	}
+
+	var entries [][2]string
+	for _, entry := range splitTopLevel(code[1:len(code)-1], ',') {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.HasPrefix(entry, "...") || strings.HasPrefix(entry, "[") {
+			return nil, false
+		}
+
+		parts := splitTopLevel(entry, ':')
+		key := strings.Trim(strings.TrimSpace(parts[0]), `"'`)
+		value := key
+		if len(parts) > 1 {
+			value = strings.TrimSpace(strings.Join(parts[1:], ":"))
+		} else if !goIdentifierRegex.MatchString(key) {
+			return nil, false
+		}
+		entries = append(entries, [2]string{key, value})
+	}
+	return entries, true
+}
+
+// styleAttribute переводит style={{...}} в атрибут style. Объект из литералов становится
+// строкой CSS, объект с выражениями - вызовом сгенерированной функции, вычисляющей
+// строку при рендеринге (см. convertStyleHelper). Остальные значения выводятся как есть
+func (c *JSXToHTMXConverter) styleAttribute(tag string, code string) string {
+	if literal, ok := models.ParseJSLiteral(code); ok {
+		if object, ok := literal.(*models.JSObject); ok {
+			if css := staticStyle(object); css != "" {
+				return constantAttribute(tag, "style", css)
+			}
+			return ""
+		}
+	}
+
+	entries, ok := styleEntries(code)
+	if !ok {
+		return c.expressionAttribute(tag, "style", code)
+	}
+	return fmt.Sprintf(" style={ %s }", c.convertStyleHelper(tag, entries))
+}
+
+// convertStyleHelper генерирует Go функцию, собирающую строку CSS из значений объекта
+// стилей (тернарные выражения - через if), и возвращает ее вызов. Функция принимает
+// параметры templ компонента; templ проверяет результат сам (SanitizeStyleAttributeValues)
+func (c *JSXToHTMXConverter) convertStyleHelper(tag string, entries [][2]string) string {
+	c.styleCount++
+	name := fmt.Sprintf("%s%sStyle%d", strings.ToLower(c.componentName()[:1]), c.componentName()[1:], c.styleCount)
+
+	var body strings.Builder
+	var parts []string
+	literal := ""
+	for i, entry := range entries {
+		key, code := entry[0], entry[1]
+		declaration := cssPropertyName(key) + ":"
+		if len(parts) > 0 || literal != "" {
+			declaration = ";" + declaration
+		}
+
+		if value, ok := models.ParseJSLiteral(code); ok {
+			if css, ok := cssValue(key, value); ok {
+				literal += declaration + css
+			}
+			continue
+		}
+
+		var goExpr string
+		if condition, whenTrue, whenFalse, ok := splitTernary(code); ok {
+			// Значение, выбираемое условием, вычисляется в переменной
+			variable := key
+			if !goIdentifierRegex.MatchString(variable) {
+				variable = fmt.Sprintf("value%d", i+1)
+			}
+			body.WriteString(fmt.Sprintf("\t%s := %s\n", variable, styleValueWithUnit(c.styleValueExpression(key, whenFalse))))
+			body.WriteString(fmt.Sprintf("\tif %s {\n", c.convertReactExprToGoExpr(condition)))
+			body.WriteString(fmt.Sprintf("\t\t%s = %s\n", variable, styleValueWithUnit(c.styleValueExpression(key, whenTrue))))
+			body.WriteString("\t}\n")
+			parts = append(parts, strconv.Quote(literal+declaration), variable)
+			literal = ""
+			continue
+		}
+
+		goExpr, unit := c.styleValueExpression(key, code)
+		parts = append(parts, strconv.Quote(literal+declaration), goExpr)
+		literal = unit
+	}
+	if literal != "" || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(literal))
+	}
+
+	c.regionTemplates = append(c.regionTemplates,
+		fmt.Sprintf("// %s - стиль элемента <%s> компонента %s, вычисляемый при рендеринге\nfunc %s(%s) string {\n%s\treturn %s\n}\n\n",
+			name, tag, c.componentName(), name, c.templParams, body.String(), strings.Join(parts, " + ")))
+
+	return fmt.Sprintf("%s(%s)", name, c.templArgs)
+}
+
+// styleValueExpression возвращает строковое выражение Go для значения свойства стиля
+// и единицу измерения: числовые поля пропсов и состояния получают px, если свойство
+// не безразмерное
+func (c *JSXToHTMXConverter) styleValueExpression(key string, code string) (string, string) {
+	if value, ok := models.ParseJSLiteral(code); ok {
+		css, _ := cssValue(key, value)
+		return strconv.Quote(css), ""
+	}
+
+	goExpr := c.convertReactExprToGoExpr(code)
+	switch c.fieldGoType(goExpr) {
+	case "int", "float64":
+		if isUnitlessCSSProperty(key) {
+			return fmt.Sprintf("fmt.Sprint(%s)", goExpr), ""
+		}
+		return fmt.Sprintf("fmt.Sprint(%s)", goExpr), "px"
+	}
+	return c.stringExpression(goExpr), ""
+}
+
+// styleValueWithUnit дописывает к выражению значения стиля единицу измерения
+func styleValueWithUnit(goExpr string, unit string) string {
+	if unit == "" {
+		return goExpr
+	}
+	return goExpr + " + " + strconv.Quote(unit)
+}
+
+// splitTernary разбирает выражение верхнего уровня condition ? whenTrue : whenFalse.
+// Скобки, строки, ?. и ?? не считаются частью тернарного оператора
+func splitTernary(code string) (string, string, string, bool) {
+	depth, nested := 0, 0
+	question := -1
+	var quote byte
+
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(' || ch == '[' || ch == '{':
+			depth++
+		case ch == ')' || ch == ']' || ch == '}':
+			depth--
+		case depth > 0:
+		case ch == '?':
+			if i+1 < len(code) && (code[i+1] == '.' || code[i+1] == '?') {
+				i++
+				continue
+			}
+			if question < 0 {
+				question = i
+			} else {
+				nested++
+			}
+		case ch == ':' && question >= 0:
+			if nested > 0 {
+				nested--
+				continue
+			}
+			return strings.TrimSpace(code[:question]), strings.TrimSpace(code[question+1 : i]), strings.TrimSpace(code[i+1:]), true
+		}
+	}
+	return "", "", "", false
+}
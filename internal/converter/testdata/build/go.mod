@@ -0,0 +1,8 @@
+module react-to-templ
+
+go 1.24
+
+require (
+	github.com/a-h/templ v0.3.833
+	github.com/google/uuid v1.6.0
+)
@@ -0,0 +1,27 @@
+package converter
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+)
+
+// wrapperWarnings возвращает предупреждения об обертках, которые парсер снял с компонента.
+// memo не требует действий: templ компонент и так рендерится заново на каждый запрос,
+// ref из forwardRef превращается в стабильный id элемента, остальные обертки
+// (observer, HOC) добавляют поведение или пропсы, которых в templ нет
+func wrapperWarnings(component *models.ReactComponent) []string {
+	var warnings []string
+	for _, wrapper := range component.Wrappers {
+		switch wrapper {
+		case "memo":
+			continue
+		case "forwardRef":
+			warnings = append(warnings, fmt.Sprintf("Компонент %s обернут в forwardRef: родитель не получает ref при серверном рендеринге, элемент с переданным ref доступен по стабильному id", component.Name))
+		case "observer":
+			warnings = append(warnings, fmt.Sprintf("Компонент %s обернут в observer: реактивность MobX не переносится, данные хранилища нужно передать пропсами", component.Name))
+		default:
+			warnings = append(warnings, fmt.Sprintf("HOC %s не применяется к компоненту %s: пропсы, которые он добавляет, нужно передать явно", wrapper, component.Name))
+		}
+	}
+	return warnings
+}
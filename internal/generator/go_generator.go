@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"react-to-templ-converter/internal/config"
 	"react-to-templ-converter/internal/models"
+	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -21,6 +23,7 @@ type StateHandler interface {
 	GenerateStateStructs(component *models.ReactComponent) string
 	GenerateStateHandlers(component *models.ReactComponent) string
 	GenerateHtmxJSHelpers(component *models.ReactComponent) string
+	GenerateRoutes(component *models.ReactComponent) string
 	SetDebug(debug bool)
 	SetIndentation(style string, size int)
 }
@@ -64,34 +67,45 @@ func (g *GoGenerator) GenerateGoController(component *models.ReactComponent) str
 		return ""
 	}
 
-	// Если нет состояний и эффектов, контроллер тоже не нужен
-	if len(component.State) == 0 && len(component.Effects) == 0 && len(component.Callbacks) == 0 {
+	// Без серверного состояния контроллер не нужен: обработчики, хранилище и очистка
+	// работают с состоянием экземпляра, а клиентские состояния и эффекты живут в браузере
+	if len(component.State) == 0 && len(component.Reducers) == 0 {
 		return ""
 	}
 
 	var sb strings.Builder
 
-	// 1. Генерация заголовка файла
-	sb.WriteString(g.generateFileHeader(component))
-
-	// 2. Генерация структур для состояний
+	// 1. Генерация структур для состояний
 	if g.stateHandler != nil {
 		sb.WriteString(g.stateHandler.GenerateStateStructs(component))
 	} else {
 		sb.WriteString(g.generateBasicStateStructs(component))
 	}
 
-	// 3. Генерация обработчиков для состояний и эффектов
+	// 2. Генерация обработчиков для состояний и эффектов
 	if g.stateHandler != nil {
 		sb.WriteString(g.stateHandler.GenerateStateHandlers(component))
 	} else {
 		sb.WriteString(g.generateBasicStateHandlers(component))
 	}
 
-	// 4. Генерация вспомогательных функций
+	// 3. Генерация вспомогательных функций
 	sb.WriteString(g.generateUtilityFunctions(component))
 
-	return sb.String()
+	// 4. Регистрация маршрутов
+	var routes string
+	if g.stateHandler != nil {
+		routes = g.stateHandler.GenerateRoutes(component)
+	} else {
+		routes = g.generateBasicRoutes(component)
+	}
+	if routes != "" {
+		sb.WriteString("\n")
+		sb.WriteString(routes)
+	}
+
+	// 5. Заголовок файла: импорты определяются по сгенерированному коду
+	return g.generateFileHeader(sb.String()) + sb.String()
 }
 
 // GenerateJavaScript создает JavaScript код для поддержки HTMX
@@ -108,14 +122,14 @@ func (g *GoGenerator) GenerateJavaScript(component *models.ReactComponent) strin
 }
 
 // generateFileHeader генерирует заголовок файла с пакетом и импортами
-func (g *GoGenerator) generateFileHeader(component *models.ReactComponent) string {
+func (g *GoGenerator) generateFileHeader(code string) string {
 	var sb strings.Builder
 
 	// Пакет
 	sb.WriteString("package controllers\n\n")
 
 	// Импорты
-	imports := g.detectRequiredImports(component)
+	imports := g.detectRequiredImports(code)
 	if len(imports) > 0 {
 		sb.WriteString("import (\n")
 		for _, imp := range imports {
@@ -135,22 +149,14 @@ func (g *GoGenerator) generateBasicStateStructs(component *models.ReactComponent
 
 	var sb strings.Builder
 
-	// Определение структуры состояния
-	sb.WriteString(fmt.Sprintf("// %sState определяет состояние компонента %s\n", component.Name, component.Name))
-	sb.WriteString(fmt.Sprintf("type %sState struct {\n", component.Name))
-
 	indent := g.getIndentation(1)
 
-	// Добавляем поля для состояний
-	for _, state := range component.State {
-		stateName := strings.Title(state.Name)
-		goType := g.convertTypeToGo(state.Type, state.InitialValue)
-
-		sb.WriteString(fmt.Sprintf("%s%s %s\n", indent, stateName, goType))
+	// Структура состояния объявлена в templ файле: templ компонент принимает ее параметром
+	if g.options.PackageName != "" && g.options.PackageName != "." {
+		sb.WriteString(fmt.Sprintf("// %sState определяет состояние компонента %s (объявлено в пакете шаблонов)\n", component.Name, component.Name))
+		sb.WriteString(fmt.Sprintf("type %sState = %s.%sState\n\n", component.Name, g.options.PackageName, component.Name))
 	}
 
-	sb.WriteString("}\n\n")
-
 	// Глобальные переменные для хранения состояний
 	sb.WriteString("var (\n")
 	sb.WriteString(fmt.Sprintf("%s%sStates = make(map[string]*%sState)\n", indent, strings.ToLower(component.Name), component.Name))
@@ -203,14 +209,14 @@ func (g *GoGenerator) generateBasicStateHandlers(component *models.ReactComponen
 	}
 
 	sb.WriteString(fmt.Sprintf("%stempl.Handler(%s%s(", indent, packagePrefix,
-		strings.ToLower(string(component.Name[0]))+component.Name[1:]))
+		strings.ToUpper(component.Name[:1])+component.Name[1:]))
 
 	// Передаем пропсы (упрощенно)
 	if len(component.Props) > 0 {
 		sb.WriteString(fmt.Sprintf("%sProps{}, ", component.Name))
 	}
 
-	sb.WriteString("id)).ServeHTTP(w, r)\n")
+	sb.WriteString("id, *state)).ServeHTTP(w, r)\n")
 	sb.WriteString("}\n\n")
 
 	// Создание базовых обработчиков для каждого состояния
@@ -267,7 +273,7 @@ func (g *GoGenerator) generateBasicStateUpdater(sb *strings.Builder, component *
 		sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
 		sb.WriteString(fmt.Sprintf("%s}\n", indent))
 	} else if goType == "bool" {
-		sb.WriteString(fmt.Sprintf("%snewValue := r.FormValue(\"value\") == \"true\"\n", indent))
+		sb.WriteString(fmt.Sprintf("%snewValue := r.FormValue(\"value\") == \"true\" || r.FormValue(\"value\") == \"on\"\n", indent))
 	} else {
 		sb.WriteString(fmt.Sprintf("%s// Для сложных типов используем JSON\n", indent))
 		sb.WriteString(fmt.Sprintf("%svar newValue %s\n", indent, goType))
@@ -291,14 +297,14 @@ func (g *GoGenerator) generateBasicStateUpdater(sb *strings.Builder, component *
 
 	sb.WriteString(fmt.Sprintf("%s// Рендерим компонент с обновленным состоянием\n", indent))
 	sb.WriteString(fmt.Sprintf("%stempl.Handler(%s%s(", indent, packagePrefix,
-		strings.ToLower(string(component.Name[0]))+component.Name[1:]))
+		strings.ToUpper(component.Name[:1])+component.Name[1:]))
 
 	// Передаем пропсы (упрощенно)
 	if len(component.Props) > 0 {
 		sb.WriteString(fmt.Sprintf("%sProps{}, ", component.Name))
 	}
 
-	sb.WriteString("id)).ServeHTTP(w, r)\n")
+	sb.WriteString("id, *state)).ServeHTTP(w, r)\n")
 	sb.WriteString("}\n\n")
 }
 
@@ -342,6 +348,10 @@ func (g *GoGenerator) generateUtilityFunctions(component *models.ReactComponent)
 	sb.WriteString(fmt.Sprintf("%s%sreturn\n", indent, indent))
 	sb.WriteString(fmt.Sprintf("%s}\n\n", indent))
 
+	// Функции очистки эффектов сюда не переносятся: таймеры htmx останавливает сам при
+	// удалении их элементов, подписки SSE закрываются вместе с соединением, а эффекты
+	// в браузере очищает JavaScript компонента
+
 	// Удаляем состояние
 	sb.WriteString(fmt.Sprintf("%s// Удаляем состояние компонента\n", indent))
 	sb.WriteString(fmt.Sprintf("%s%sMutex.Lock()\n", indent, strings.ToLower(component.Name)))
@@ -356,50 +366,100 @@ func (g *GoGenerator) generateUtilityFunctions(component *models.ReactComponent)
 	return sb.String()
 }
 
-// detectRequiredImports определяет необходимые импорты для контроллера
-func (g *GoGenerator) detectRequiredImports(component *models.ReactComponent) []string {
+// generateBasicRoutes генерирует базовую регистрацию HTTP маршрутов компонента
+func (g *GoGenerator) generateBasicRoutes(component *models.ReactComponent) string {
+	if len(component.State) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	indent := g.getIndentation(1)
+	basePath := "/api/" + strings.ToLower(component.Name)
+
+	sb.WriteString(fmt.Sprintf("// Register%sRoutes регистрирует HTTP маршруты компонента %s\n", component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("func Register%sRoutes(mux *http.ServeMux) {\n", component.Name))
+	sb.WriteString(fmt.Sprintf("%smux.HandleFunc(\"POST %s/new\", New%s)\n", indent, basePath, component.Name))
+
+	for _, state := range component.State {
+		route := strings.TrimPrefix(state.Setter, "set")
+		if route != "" {
+			route = strings.ToLower(route[:1]) + route[1:]
+		}
+		sb.WriteString(fmt.Sprintf("%smux.HandleFunc(\"POST %s/%s\", %s)\n", indent, basePath, route, strings.Title(state.Setter)))
+	}
+
+	sb.WriteString(fmt.Sprintf("%smux.HandleFunc(\"POST %s/cleanup\", Cleanup%s)\n", indent, basePath, component.Name))
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// detectRequiredImports определяет необходимые импорты для контроллера по сгенерированному
+// коду code: пакеты стандартной библиотеки, к которым он обращается
+func (g *GoGenerator) detectRequiredImports(code string) []string {
 	imports := make(map[string]bool)
 
+	for imp, pattern := range standardPackageUses {
+		if pattern.MatchString(code) {
+			imports[imp] = true
+		}
+	}
+
 	// Стандартные импорты
 	imports["net/http"] = true
-	imports["sync"] = true
 
 	// Если используется templ
-	templatePackage := "templates"
-	if g.options.PackageName != "" && g.options.PackageName != "." {
-		templatePackage = g.options.PackageName
-	}
-
 	imports["github.com/a-h/templ"] = true
-	imports[fmt.Sprintf("react-to-templ/%s", templatePackage)] = true
-
-	// Для работы с JSON
-	if len(component.Props) > 0 || g.options.StatePersistence == "redis" {
-		imports["encoding/json"] = true
-	}
+	imports[g.options.TemplatesImportPath()] = true
 
 	// Для генерации ID
 	imports["github.com/google/uuid"] = true
 
-	// Для преобразования типов
-	for _, state := range component.State {
-		if state.Type == "number" || (state.InitialValue != nil && isNumeric(state.InitialValue)) {
-			imports["strconv"] = true
-			break
-		}
-	}
+	return sortImports(imports, g.options.ModulePath)
+}
 
-	// Преобразуем map в slice
+// Вспомогательные функции
+
+// standardPackageUses сопоставляет пакеты стандартной библиотеки с обращениями к ним в коде
+var standardPackageUses = map[string]*regexp.Regexp{
+	"bufio":         regexp.MustCompile(`(^|[^\w.])bufio\.`),
+	"bytes":         regexp.MustCompile(`(^|[^\w.])bytes\.`),
+	"context":       regexp.MustCompile(`(^|[^\w.])context\.`),
+	"encoding/json": regexp.MustCompile(`(^|[^\w.])json\.`),
+	"fmt":           regexp.MustCompile(`(^|[^\w.])fmt\.`),
+	"io":            regexp.MustCompile(`(^|[^\w.])io\.`),
+	"net/http":      regexp.MustCompile(`(^|[^\w.])http\.`),
+	"net/url":       regexp.MustCompile(`(^|[^\w.])url\.`),
+	"regexp":        regexp.MustCompile(`(^|[^\w.])regexp\.`),
+	"slices":        regexp.MustCompile(`(^|[^\w.])slices\.`),
+	"strconv":       regexp.MustCompile(`(^|[^\w.])strconv\.`),
+	"strings":       regexp.MustCompile(`(^|[^\w.])strings\.`),
+	"sync":          regexp.MustCompile(`(^|[^\w.])sync\.`),
+	"time":          regexp.MustCompile(`(^|[^\w.])time\.`),
+}
+
+// sortImports упорядочивает импорты: сначала стандартная библиотека, затем остальные
+// пакеты (включая пакеты модуля modulePath), внутри групп - по алфавиту.
+// Вывод не зависит от порядка обхода map
+func sortImports(imports map[string]bool, modulePath string) []string {
 	result := make([]string, 0, len(imports))
 	for imp := range imports {
 		result = append(result, imp)
 	}
 
+	isStd := func(imp string) bool {
+		return !strings.Contains(strings.SplitN(imp, "/", 2)[0], ".") && !strings.HasPrefix(imp, modulePath+"/")
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if isStd(result[i]) != isStd(result[j]) {
+			return isStd(result[i])
+		}
+		return result[i] < result[j]
+	})
+
 	return result
 }
 
-// Вспомогательные функции
-
 // convertTypeToGo преобразует тип TypeScript/JavaScript в тип Go
 func (g *GoGenerator) convertTypeToGo(tsType string, value interface{}) string {
 	if tsType == "" && value != nil {
@@ -0,0 +1,237 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"react-to-templ-converter/internal/models"
+	"regexp"
+	"strings"
+)
+
+// generateContextDefinitions генерирует Go код для контекстов, созданных в модуле:
+// типизированный ключ context.Context, функции чтения/записи значения,
+// templ обертку для <X.Provider> и HTTP middleware
+func (g *TemplGenerator) generateContextDefinitions(component *models.ReactComponent) string {
+	var sb strings.Builder
+	indent := g.getIndentation(1)
+	indent2 := g.getIndentation(2)
+	indent3 := g.getIndentation(3)
+
+	for _, context := range component.Contexts {
+		if !context.Created {
+			continue
+		}
+
+		name := context.Name
+		keyType := strings.ToLower(name[:1]) + name[1:] + "Key"
+		goType := g.convertTypeToGo(context.Type)
+
+		sb.WriteString(fmt.Sprintf("// %s - типизированный ключ контекста %s\n", keyType, name))
+		sb.WriteString(fmt.Sprintf("type %s struct{}\n\n", keyType))
+
+		sb.WriteString(fmt.Sprintf("// With%s возвращает контекст со значением %s\n", name, name))
+		sb.WriteString(fmt.Sprintf("func With%s(ctx context.Context, value %s) context.Context {\n", name, goType))
+		sb.WriteString(fmt.Sprintf("%sreturn context.WithValue(ctx, %s{}, value)\n", indent, keyType))
+		sb.WriteString("}\n\n")
+
+		sb.WriteString(fmt.Sprintf("// %sValue возвращает значение %s из контекста (аналог useContext)\n", name, name))
+		sb.WriteString(fmt.Sprintf("func %sValue(ctx context.Context) %s {\n", name, goType))
+		sb.WriteString(fmt.Sprintf("%sif value, ok := ctx.Value(%s{}).(%s); ok {\n", indent, keyType, goType))
+		sb.WriteString(fmt.Sprintf("%sreturn value\n", indent2))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+		sb.WriteString(fmt.Sprintf("%s// Значение по умолчанию из createContext\n", indent))
+		sb.WriteString(fmt.Sprintf("%sreturn %s\n", indent, formatContextDefault(goType, context.DefaultValue)))
+		sb.WriteString("}\n\n")
+
+		sb.WriteString(fmt.Sprintf("// %sProvider заменяет <%s.Provider>: передает значение дочерним компонентам\n", name, name))
+		sb.WriteString(fmt.Sprintf("func %sProvider(value %s) templ.Component {\n", name, goType))
+		sb.WriteString(fmt.Sprintf("%sreturn templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {\n", indent))
+		sb.WriteString(fmt.Sprintf("%schildren := templ.GetChildren(ctx)\n", indent2))
+		sb.WriteString(fmt.Sprintf("%sctx = templ.ClearChildren(ctx)\n", indent2))
+		sb.WriteString(fmt.Sprintf("%sreturn children.Render(With%s(ctx, value), w)\n", indent2, name))
+		sb.WriteString(fmt.Sprintf("%s})\n", indent))
+		sb.WriteString("}\n\n")
+
+		sb.WriteString(fmt.Sprintf("// %sMiddleware добавляет значение %s в контекст каждого запроса\n", name, name))
+		sb.WriteString(fmt.Sprintf("func %sMiddleware(value %s) func(http.Handler) http.Handler {\n", name, goType))
+		sb.WriteString(fmt.Sprintf("%sreturn func(next http.Handler) http.Handler {\n", indent))
+		sb.WriteString(fmt.Sprintf("%sreturn http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {\n", indent2))
+		sb.WriteString(fmt.Sprintf("%snext.ServeHTTP(w, r.WithContext(With%s(r.Context(), value)))\n", indent3, name))
+		sb.WriteString(fmt.Sprintf("%s})\n", indent2))
+		sb.WriteString(fmt.Sprintf("%s}\n", indent))
+		sb.WriteString("}\n\n")
+	}
+
+	return sb.String()
+}
+
+// generateImportedContextReaders генерирует функции чтения контекстов, созданных в других
+// модулях. При конвертации проекта функция вызывает <Name>Value пакета создающего модуля,
+// а если модуль не конвертируется (в нем нет разметки) - возвращает значение по умолчанию
+// из createContext. Без проекта функции <Name>Value нет: заглушка возвращает нулевое значение
+func (g *TemplGenerator) generateImportedContextReaders(component *models.ReactComponent) string {
+	var sb strings.Builder
+	indent := g.getIndentation(1)
+
+	for _, context := range importedContexts(component) {
+		reader := contextReader(component, context)
+		ref := g.options.Contexts[context.Name]
+
+		switch {
+		case ref != nil && ref.ImportPath != "":
+			goType := g.convertTypeToGo(ref.Definition.Type)
+			value := context.Name + "Value(ctx)"
+			if ref.ImportPath != g.options.TemplatesImportPath() {
+				value = ref.Package + "." + value
+			}
+			sb.WriteString(fmt.Sprintf("// %s читает контекст %s, созданный в %s\n", reader, context.Name, filepath.Base(ref.SourceFile)))
+			sb.WriteString(fmt.Sprintf("func %s(ctx context.Context) %s {\n", reader, goType))
+			sb.WriteString(fmt.Sprintf("%sreturn %s\n", indent, value))
+		case ref != nil:
+			goType := g.convertTypeToGo(ref.Definition.Type)
+			sb.WriteString(fmt.Sprintf("// %s возвращает значение по умолчанию контекста %s: модуль %s\n", reader, context.Name, filepath.Base(ref.SourceFile)))
+			sb.WriteString("// не содержит разметки и не конвертируется\n")
+			sb.WriteString(fmt.Sprintf("func %s(ctx context.Context) %s {\n", reader, goType))
+			sb.WriteString(fmt.Sprintf("%sreturn %s\n", indent, formatContextDefault(goType, ref.Definition.DefaultValue)))
+		default:
+			goType := g.convertTypeToGo(context.Type)
+			sb.WriteString(fmt.Sprintf("// %s читает контекст %s, созданный в другом модуле.\n", reader, context.Name))
+			sb.WriteString(fmt.Sprintf("// TODO: Замените заглушку вызовом %sValue(ctx) из пакета модуля с createContext\n", context.Name))
+			sb.WriteString(fmt.Sprintf("func %s(ctx context.Context) %s {\n", reader, goType))
+			sb.WriteString(fmt.Sprintf("%sreturn %s\n", indent, formatContextDefault(goType, nil)))
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	return sb.String()
+}
+
+// importedContextPackages добавляет пакеты шаблонов, в которых созданы контексты из других модулей
+func (g *TemplGenerator) importedContextPackages(component *models.ReactComponent, imports map[string]bool) {
+	for _, context := range importedContexts(component) {
+		if ref := g.options.Contexts[context.Name]; ref != nil && ref.ImportPath != "" && ref.ImportPath != g.options.TemplatesImportPath() {
+			imports[ref.ImportPath] = true
+		}
+	}
+}
+
+// generateContextReads генерирует чтение значений useContext в начале templ компонента.
+// templ передает ctx в каждый компонент неявно
+func (g *TemplGenerator) generateContextReads(component *models.ReactComponent) string {
+	var sb strings.Builder
+
+	for _, context := range component.Contexts {
+		if context.Variable == "" || !jsxReferences(component.JSX, context.Variable) {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\t{{ %s := %s(ctx) }}\n", context.Variable, contextReader(component, context)))
+	}
+
+	return sb.String()
+}
+
+// contextReader возвращает функцию чтения контекста: <Name>Value для контекста этого
+// модуля, заглушку с именем компонента для контекста из другого модуля
+func contextReader(component *models.ReactComponent, context models.ContextDefinition) string {
+	if createsContext(component, context.Name) {
+		return context.Name + "Value"
+	}
+	return strings.ToLower(component.Name[:1]) + component.Name[1:] + context.Name + "Value"
+}
+
+// importedContexts возвращает контексты, которые компонент читает, но не создает
+func importedContexts(component *models.ReactComponent) []models.ContextDefinition {
+	var contexts []models.ContextDefinition
+	seen := make(map[string]bool)
+	for _, context := range component.Contexts {
+		if context.Variable == "" || seen[context.Name] || createsContext(component, context.Name) {
+			continue
+		}
+		seen[context.Name] = true
+		contexts = append(contexts, context)
+	}
+	return contexts
+}
+
+// createsContext проверяет, создается ли контекст name в модуле компонента
+func createsContext(component *models.ReactComponent, name string) bool {
+	for _, context := range component.Contexts {
+		if context.Created && context.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCreatedContext проверяет, создается ли в модуле хотя бы один контекст
+func hasCreatedContext(component *models.ReactComponent) bool {
+	for _, context := range component.Contexts {
+		if context.Created {
+			return true
+		}
+	}
+	return false
+}
+
+// jsxReferences проверяет, используется ли идентификатор в выражениях JSX
+func jsxReferences(jsx *models.JSXElement, name string) bool {
+	if jsx == nil {
+		return false
+	}
+
+	identRegex := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+
+	for _, value := range jsx.Props {
+		switch v := value.(type) {
+		case string:
+			if jsx.Type == "expression" && identRegex.MatchString(v) {
+				return true
+			}
+		case map[string]interface{}:
+			if code, ok := v["code"].(string); ok && identRegex.MatchString(code) {
+				return true
+			}
+		}
+	}
+
+	for _, child := range jsx.Children {
+		if jsxReferences(child, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatContextDefault форматирует значение по умолчанию контекста как литерал Go
+func formatContextDefault(goType string, value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if goType == "string" || goType == "interface{}" {
+			return fmt.Sprintf("%q", v)
+		}
+	case float64:
+		if goType == "int" {
+			return fmt.Sprintf("%d", int(v))
+		}
+		if goType == "interface{}" || goType == "float64" {
+			return fmt.Sprintf("%v", v)
+		}
+	case bool:
+		if goType == "bool" || goType == "interface{}" {
+			return fmt.Sprintf("%t", v)
+		}
+	}
+
+	// Сложные значения по умолчанию заменяются нулевым значением типа
+	switch goType {
+	case "string":
+		return `""`
+	case "int", "float64":
+		return "0"
+	case "bool":
+		return "false"
+	default:
+		return "nil"
+	}
+}
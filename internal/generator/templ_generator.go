@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"react-to-templ-converter/internal/config"
 	"react-to-templ-converter/internal/models"
+	"strconv"
 	"strings"
 )
 
@@ -14,6 +15,12 @@ type TemplGenerator struct {
 	indentSize  int
 	indentStyle string
 	jsxToHtml   JSXToHTMLConverter
+	stateTypes  StateTypeGenerator
+}
+
+// StateTypeGenerator генерирует структуру состояния компонента, которую принимает templ компонент
+type StateTypeGenerator = interface {
+	GenerateStateType(component *models.ReactComponent) string
 }
 
 // JSXToHTMLConverter определяет интерфейс для конвертации JSX в HTML
@@ -37,6 +44,11 @@ func (g *TemplGenerator) SetJSXConverter(converter JSXToHTMLConverter) {
 	g.jsxToHtml = converter
 }
 
+// SetStateTypeGenerator устанавливает генератор структуры состояния
+func (g *TemplGenerator) SetStateTypeGenerator(stateTypes StateTypeGenerator) {
+	g.stateTypes = stateTypes
+}
+
 // SetDebug устанавливает режим отладки
 func (g *TemplGenerator) SetDebug(debug bool) {
 	g.debug = debug
@@ -55,16 +67,21 @@ func (g *TemplGenerator) SetIndentation(style string, size int) {
 func (g *TemplGenerator) GenerateTemplFile(component *models.ReactComponent) string {
 	var sb strings.Builder
 
-	// 1. Генерация заголовка файла (пакет, импорты)
-	sb.WriteString(g.generateFileHeader(component))
-
-	// 2. Генерация структуры пропсов
+	// 1. Генерация структуры пропсов
 	if len(component.Props) > 0 {
 		sb.WriteString(g.generatePropsStruct(component))
 	}
 
-	// 3. Генерация вспомогательных структур для состояний
-	if len(component.State) > 0 {
+	// Ключи и обертки для контекстов, созданных в модуле (createContext)
+	if hasCreatedContext(component) {
+		sb.WriteString(g.generateContextDefinitions(component))
+	}
+
+	// Заглушки чтения контекстов из других модулей
+	sb.WriteString(g.generateImportedContextReaders(component))
+
+	// 3. Генерация структуры состояния, которую принимает templ компонент
+	if hasStateStruct(component) {
 		sb.WriteString(g.generateStateStructs(component))
 	}
 
@@ -76,11 +93,12 @@ func (g *TemplGenerator) GenerateTemplFile(component *models.ReactComponent) str
 		sb.WriteString(g.generateHelperFunctions(component))
 	}
 
-	return sb.String()
+	// Заголовок файла (пакет, импорты): импортируется только то, что используется
+	return g.generateFileHeader(component, sb.String()) + sb.String()
 }
 
-// generateFileHeader генерирует заголовок файла с пакетом и импортами
-func (g *TemplGenerator) generateFileHeader(component *models.ReactComponent) string {
+// generateFileHeader генерирует заголовок файла с пакетом и импортами по коду файла
+func (g *TemplGenerator) generateFileHeader(component *models.ReactComponent, code string) string {
 	var sb strings.Builder
 
 	// Пакет
@@ -91,7 +109,7 @@ func (g *TemplGenerator) generateFileHeader(component *models.ReactComponent) st
 	sb.WriteString(fmt.Sprintf("package %s\n\n", packageName))
 
 	// Импорты
-	imports := g.detectRequiredImports(component)
+	imports := g.detectRequiredImports(component, code)
 	if len(imports) > 0 {
 		sb.WriteString("import (\n")
 		for _, imp := range imports {
@@ -115,7 +133,12 @@ func (g *TemplGenerator) generatePropsStruct(component *models.ReactComponent) s
 
 	// Поля структуры
 	for _, prop := range component.Props {
-		goType := g.convertTypeToGo(prop.Type)
+		// children передаются через механизм дочерних элементов templ: { children... }
+		if prop.Name == "children" {
+			continue
+		}
+
+		goType := models.PropFieldType(prop)
 		fieldName := strings.Title(prop.Name) // Title вместо ToUpper для совместимости с Go conventions
 
 		// Комментарий о необходимости заполнения
@@ -128,15 +151,35 @@ func (g *TemplGenerator) generatePropsStruct(component *models.ReactComponent) s
 
 	sb.WriteString("}\n\n")
 
+	// Значения по умолчанию: function Card({ size = 'md' }), Card.defaultProps
+	sb.WriteString(models.PropsConstructor(component, indent))
+
 	return sb.String()
 }
 
 // generateStateStructs генерирует структуры для хранения состояний
 func (g *TemplGenerator) generateStateStructs(component *models.ReactComponent) string {
-	// В templ файле не нужно включать структуры состояний - они будут в Go контроллерах
-	// Эта функция оставлена на случай, если есть локальные состояния, которые нужны только в templ
+	// Структура объявляется в пакете шаблонов: контроллеры ссылаются на нее через псевдоним
+	if g.stateTypes != nil {
+		return g.stateTypes.GenerateStateType(component)
+	}
+
+	var sb strings.Builder
+	indent := g.getIndentation(1)
+
+	sb.WriteString(fmt.Sprintf("// %sState определяет состояние компонента %s\n", component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("type %sState struct {\n", component.Name))
+	for _, state := range component.State {
+		sb.WriteString(fmt.Sprintf("%s%s %s\n", indent, strings.ToUpper(state.Name[:1])+state.Name[1:], g.convertTypeToGo(state.Type)))
+	}
+	sb.WriteString("}\n\n")
+
+	return sb.String()
+}
 
-	return ""
+// hasStateStruct проверяет, есть ли у компонента структура состояния
+func hasStateStruct(component *models.ReactComponent) bool {
+	return len(component.State) > 0 || len(component.Reducers) > 0
 }
 
 // generateTemplComponent генерирует основной templ компонент
@@ -153,49 +196,51 @@ func (g *TemplGenerator) generateTemplComponent(component *models.ReactComponent
 		}
 	}
 
-	// Имя функции templ с маленькой буквы
-	funcName := strings.ToLower(string(component.Name[0])) + component.Name[1:]
+	// Имя функции templ экспортируется: контроллеры вызывают ее из своего пакета
+	funcName := strings.ToUpper(component.Name[:1]) + component.Name[1:]
 
 	// Параметры функции
-	params := ""
-	if len(component.Props) > 0 {
-		params = fmt.Sprintf("props %sProps", component.Name)
-	}
-
-	// Если используем HTMX, добавим параметр id
-	if g.options.UseHtmx {
-		if params != "" {
-			params += ", "
+	params := g.templParams(component)
+
+	// Фрагментам, перерисовываемым отдельно, нужны те же параметры
+	if signatureAware, ok := g.jsxToHtml.(interface{ SetTemplSignature(string, string) }); ok {
+		signatureAware.SetTemplSignature(params, templArgs(params))
+	}
+
+	// Оболочка выводит корневой элемент и то, что должно пережить его перерисовку
+	// (триггеры эффектов); разметка объявляется отдельным компонентом, который
+	// перерисовывают обработчики
+	if shellAware, ok := g.jsxToHtml.(interface {
+		ShellTemplate(args string) string
+		RootTemplName() string
+	}); ok && component.JSX != nil {
+		if componentAware, ok := g.jsxToHtml.(interface{ SetComponent(*models.ReactComponent) }); ok {
+			componentAware.SetComponent(component)
 		}
-		params += "id string"
-	}
+		if shell := shellAware.ShellTemplate(templArgs(params)); shell != "" {
+			sb.WriteString(fmt.Sprintf("templ %s(%s) {\n", funcName, params))
+			sb.WriteString(shell)
+			sb.WriteString("}\n\n")
 
-	// Параметр для хранения состояния
-	if len(component.State) > 0 {
-		if params != "" {
-			params += ", "
-		}
-		for _, state := range component.State {
-			if state.Type == "number" {
-				params += fmt.Sprintf("%s int", state.Name)
-				break
-			} else if state.Type == "string" {
-				params += fmt.Sprintf("%s string", state.Name)
-				break
-			} else {
-				params += fmt.Sprintf("%s interface{}", state.Name)
-				break
-			}
+			funcName = shellAware.RootTemplName()
+			sb.WriteString(fmt.Sprintf("// %s - корневой элемент %s, который перерисовывают обработчики\n", funcName, component.Name))
 		}
 	}
 
 	// Определение templ компонента
 	sb.WriteString(fmt.Sprintf("templ %s(%s) {\n", funcName, params))
 
+	// Значения useContext читаются из ctx
+	sb.WriteString(g.generateContextReads(component))
+
 	// Если JSX есть, используем его
 	if component.JSX != nil {
 		var jsxTemplate string
 		if g.jsxToHtml != nil {
+			// Конвертеру может понадобиться весь компонент (эффекты, состояния)
+			if componentAware, ok := g.jsxToHtml.(interface{ SetComponent(*models.ReactComponent) }); ok {
+				componentAware.SetComponent(component)
+			}
 			jsxTemplate = g.jsxToHtml.ConvertJSXToTempl(component.JSX, 1)
 		} else {
 			jsxTemplate = g.simpleJSXToTempl(component, component.JSX, 1)
@@ -207,7 +252,7 @@ func (g *TemplGenerator) generateTemplComponent(component *models.ReactComponent
 
 		// Внешний div с ID для HTMX (если используется)
 		if g.options.UseHtmx {
-			sb.WriteString(fmt.Sprintf("%s<div id=\"%s-{id}\">\n", indent, component.Name))
+			sb.WriteString(fmt.Sprintf("%s<div id={ \"%s-\" + id }>\n", indent, component.Name))
 		} else {
 			sb.WriteString(fmt.Sprintf("%s<div>\n", indent))
 		}
@@ -218,12 +263,10 @@ func (g *TemplGenerator) generateTemplComponent(component *models.ReactComponent
 				// Заголовок счетчика
 				sb.WriteString(fmt.Sprintf("%s%s<h2>Счетчик: ", indent, indent))
 
-				if state.Type == "number" {
-					sb.WriteString("{ strconv.Itoa(count) }</h2>\n")
-				} else if state.Type == "string" {
-					sb.WriteString("{ count }</h2>\n")
+				if state.Type == "string" {
+					sb.WriteString("{ state.Count }</h2>\n")
 				} else {
-					sb.WriteString("{ fmt.Sprint(count) }</h2>\n")
+					sb.WriteString("{ fmt.Sprint(state.Count) }</h2>\n")
 				}
 
 				// Кнопки
@@ -231,8 +274,8 @@ func (g *TemplGenerator) generateTemplComponent(component *models.ReactComponent
 
 				// Кнопка уменьшения (-)
 				if g.options.UseHtmx {
-					sb.WriteString(fmt.Sprintf("%s%s%s<button hx-post=\"/api/%s/setCount?id={id}&value=-1\" "+
-						"hx-target=\"#%s-{id}\" hx-swap=\"outerHTML\">-</button>\n",
+					sb.WriteString(fmt.Sprintf("%s%s%s<button hx-post={ \"/api/%s/setCount?value=-1&id=\" + id } "+
+						"hx-target={ \"#%s-\" + id } hx-swap=\"outerHTML\">-</button>\n",
 						indent, indent, indent, strings.ToLower(component.Name), component.Name))
 				} else {
 					sb.WriteString(fmt.Sprintf("%s%s%s<button>-</button>\n", indent, indent, indent))
@@ -240,8 +283,8 @@ func (g *TemplGenerator) generateTemplComponent(component *models.ReactComponent
 
 				// Кнопка увеличения (+)
 				if g.options.UseHtmx {
-					sb.WriteString(fmt.Sprintf("%s%s%s<button hx-post=\"/api/%s/setCount?id={id}&value=1\" "+
-						"hx-target=\"#%s-{id}\" hx-swap=\"outerHTML\">+</button>\n",
+					sb.WriteString(fmt.Sprintf("%s%s%s<button hx-post={ \"/api/%s/setCount?value=1&id=\" + id } "+
+						"hx-target={ \"#%s-\" + id } hx-swap=\"outerHTML\">+</button>\n",
 						indent, indent, indent, strings.ToLower(component.Name), component.Name))
 				} else {
 					sb.WriteString(fmt.Sprintf("%s%s%s<button>+</button>\n", indent, indent, indent))
@@ -253,12 +296,11 @@ func (g *TemplGenerator) generateTemplComponent(component *models.ReactComponent
 				sb.WriteString(fmt.Sprintf("%s%s<h3>%s: ", indent, indent, state.Name))
 
 				// Отображаем значение в зависимости от типа
-				if state.Type == "number" {
-					sb.WriteString(fmt.Sprintf("{ strconv.Itoa(%s) }</h3>\n", state.Name))
-				} else if state.Type == "string" {
-					sb.WriteString(fmt.Sprintf("{ %s }</h3>\n", state.Name))
+				fieldName := strings.ToUpper(state.Name[:1]) + state.Name[1:]
+				if state.Type == "string" {
+					sb.WriteString(fmt.Sprintf("{ state.%s }</h3>\n", fieldName))
 				} else {
-					sb.WriteString(fmt.Sprintf("{ fmt.Sprint(%s) }</h3>\n", state.Name))
+					sb.WriteString(fmt.Sprintf("{ fmt.Sprint(state.%s) }</h3>\n", fieldName))
 				}
 			}
 		}
@@ -272,9 +314,51 @@ func (g *TemplGenerator) generateTemplComponent(component *models.ReactComponent
 
 	sb.WriteString("}\n\n")
 
+	// Фрагменты, вынесенные в отдельные templ компоненты
+	if regionAware, ok := g.jsxToHtml.(interface{ RegionTemplates() string }); ok {
+		sb.WriteString(regionAware.RegionTemplates())
+	}
+
 	return sb.String()
 }
 
+// templParams возвращает параметры templ компонента: пропсы, id и состояние
+func (g *TemplGenerator) templParams(component *models.ReactComponent) string {
+	params := ""
+	if len(component.Props) > 0 {
+		params = fmt.Sprintf("props %sProps", component.Name)
+	}
+
+	// Если используем HTMX, добавим параметр id
+	if g.options.UseHtmx {
+		if params != "" {
+			params += ", "
+		}
+		params += "id string"
+	}
+
+	// Состояние компонента передается структурой
+	if hasStateStruct(component) {
+		if params != "" {
+			params += ", "
+		}
+		params += fmt.Sprintf("state %sState", component.Name)
+	}
+
+	return params
+}
+
+// templArgs возвращает аргументы вызова templ компонента по его параметрам
+func templArgs(params string) string {
+	var args []string
+	for _, param := range strings.Split(params, ",") {
+		if fields := strings.Fields(param); len(fields) > 0 {
+			args = append(args, fields[0])
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
 // simpleJSXToTempl - простая реализация конвертера JSX в templ
 // Первый параметр - компонент, второй - JSX элемент, третий - уровень отступа
 func (g *TemplGenerator) simpleJSXToTempl(component *models.ReactComponent, jsx *models.JSXElement, indent int) string {
@@ -285,13 +369,11 @@ func (g *TemplGenerator) simpleJSXToTempl(component *models.ReactComponent, jsx
 	indentation := g.getIndentation(indent)
 	var sb strings.Builder
 
-	// Если это Fragment, преобразуем в <>...</>
-	if jsx.Type == "Fragment" {
-		sb.WriteString(indentation + "{<>}\n")
+	// Фрагмент не создает элемента: выводим дочерние узлы на его месте
+	if jsx.IsFragment() {
 		for _, child := range jsx.Children {
-			sb.WriteString(g.simpleJSXToTempl(component, child, indent+1))
+			sb.WriteString(g.simpleJSXToTempl(component, child, indent))
 		}
-		sb.WriteString(indentation + "{</>}\n")
 		return sb.String()
 	}
 
@@ -304,7 +386,7 @@ func (g *TemplGenerator) simpleJSXToTempl(component *models.ReactComponent, jsx
 		sb.WriteString(indentation + "\t// Вызов компонента " + jsx.Type + "\n")
 
 		// Имя компонента в templ формате
-		templComponentName := strings.ToLower(string(jsx.Type[0])) + jsx.Type[1:]
+		templComponentName := strings.ToUpper(jsx.Type[:1]) + jsx.Type[1:]
 
 		// Вызов компонента
 		sb.WriteString(indentation + "\t" + templComponentName + "(")
@@ -312,20 +394,10 @@ func (g *TemplGenerator) simpleJSXToTempl(component *models.ReactComponent, jsx
 		// Параметры
 		if len(jsx.Props) > 0 {
 			sb.WriteString(jsx.Type + "Props{\n")
-			for name, value := range jsx.Props {
-				propName := strings.Title(name)
-				sb.WriteString(indentation + "\t\t" + propName + ": ")
 
-				// Форматируем значение в зависимости от его типа
-				if str, ok := value.(string); ok {
-					sb.WriteString(fmt.Sprintf("\"%s\"", str))
-				} else if b, ok := value.(bool); ok {
-					sb.WriteString(fmt.Sprintf("%v", b))
-				} else {
-					sb.WriteString("/* TODO: complex value */")
-				}
-
-				sb.WriteString(",\n")
+			for _, name := range jsx.PropNames() {
+				propName := strings.Title(name)
+				sb.WriteString(indentation + "\t\t" + propName + ": " + g.propValueToGo(jsx.Props[name]) + ",\n")
 			}
 			sb.WriteString(indentation + "\t}")
 		}
@@ -346,6 +418,10 @@ func (g *TemplGenerator) simpleJSXToTempl(component *models.ReactComponent, jsx
 	// Текстовый узел
 	if jsx.Type == "text" {
 		if content, ok := jsx.Props["content"].(string); ok {
+			// Символы разметки templ в тексте выводятся строкой Go (templ ее экранирует)
+			if strings.ContainsAny(content, "{}<>&") {
+				content = "{ " + strconv.Quote(content) + " }"
+			}
 			sb.WriteString(indentation + content + "\n")
 		}
 		return sb.String()
@@ -354,7 +430,7 @@ func (g *TemplGenerator) simpleJSXToTempl(component *models.ReactComponent, jsx
 	// Выражение
 	if jsx.Type == "expression" {
 		if content, ok := jsx.Props["content"].(string); ok {
-			sb.WriteString(indentation + "{ " + content + " }\n")
+			sb.WriteString(indentation + "{ fmt.Sprint(" + content + ") }\n")
 		}
 		return sb.String()
 	}
@@ -362,32 +438,36 @@ func (g *TemplGenerator) simpleJSXToTempl(component *models.ReactComponent, jsx
 	// HTML элемент
 	sb.WriteString(indentation + "<" + jsx.Type)
 
-	// Атрибуты
-	for name, value := range jsx.Props {
-		// Преобразуем camelCase в kebab-case для HTML атрибутов
-		attrName := g.camelCaseToKebabCase(name)
+	// Атрибуты в порядке исходного кода
+	for _, name := range jsx.PropNames() {
+		value := jsx.Props[name]
+		// Имя пропса React -> имя атрибута HTML (className -> class, tabIndex -> tabindex)
+		attrName := models.HTMLAttributeName(name)
 
-		// Специальные атрибуты
-		if name == "className" {
-			attrName = "class"
-		} else if name == "htmlFor" {
-			attrName = "for"
+		// Распространение {...rest} и стилизованные компоненты переводит только конвертер JSX
+		if strings.HasPrefix(name, "__spread__") || name == "__styled__" {
+			continue
 		}
 
 		// Запись атрибута
 		if value == true {
 			sb.WriteString(" " + attrName)
 		} else if valueStr, ok := value.(string); ok {
-			sb.WriteString(" " + attrName + "=\"" + valueStr + "\"")
-		} else {
-			// Сложные значения
-			sb.WriteString(" " + attrName + "=\"/* TODO: complex value */\"")
+			// Кавычку внутри значения templ не поддерживает: такое значение - строка Go
+			if strings.Contains(valueStr, `"`) {
+				sb.WriteString(" " + attrName + "={ " + strconv.Quote(valueStr) + " }")
+			} else {
+				sb.WriteString(" " + attrName + "=\"" + valueStr + "\"")
+			}
+		} else if value != false {
+			// Выражения приводятся к строке, templ экранирует их при выводе
+			sb.WriteString(" " + attrName + "={ fmt.Sprint(" + g.propValueToGo(value) + ") }")
 		}
 	}
 
 	// HTMX атрибуты для корневого элемента
 	if g.options.UseHtmx && indent == 1 {
-		sb.WriteString(fmt.Sprintf(" id=\"%s-{id}\"", component.Name))
+		sb.WriteString(fmt.Sprintf(" id={ \"%s-\" + id }", component.Name))
 	}
 
 	// Закрытие тега и дочерние элементы
@@ -416,63 +496,33 @@ func (g *TemplGenerator) generateHelperFunctions(component *models.ReactComponen
 	return sb.String()
 }
 
-// detectRequiredImports определяет необходимые импорты на основе компонента
-func (g *TemplGenerator) detectRequiredImports(component *models.ReactComponent) []string {
+// detectRequiredImports определяет импорты templ файла по сгенерированному коду:
+// пакеты стандартной библиотеки - по обращениям к ним, пакеты шаблонов проекта -
+// по вызовам компонентов из них
+func (g *TemplGenerator) detectRequiredImports(component *models.ReactComponent, code string) []string {
 	imports := make(map[string]bool)
 
-	// Всегда импортируем fmt для интерполяции строк и форматирования
-	imports["fmt"] = true
-
-	// Если используем HTMX или есть числовые состояния, импортируем strconv
-	if g.options.UseHtmx || hasNumericState(component) {
-		imports["strconv"] = true
+	for imp, pattern := range standardPackageUses {
+		if pattern.MatchString(code) {
+			imports[imp] = true
+		}
 	}
 
-	// Проверка JSX на необходимость дополнительных импортов
 	g.detectImportsFromJSX(component.JSX, imports)
+	g.importedContextPackages(component, imports)
 
-	// Проверка эффектов, состояний и т.д.
-	g.detectImportsFromState(component, imports)
-
-	// Преобразуем map в slice
-	result := make([]string, 0, len(imports))
-	for imp := range imports {
-		result = append(result, imp)
-	}
-
-	return result
+	return sortImports(imports, g.options.ModulePath)
 }
 
-// hasNumericState проверяет, есть ли в компоненте числовые состояния
-func hasNumericState(component *models.ReactComponent) bool {
-	for _, state := range component.State {
-		if state.Type == "number" {
-			return true
-		}
-		if state.InitialValue != nil {
-			_, isNumeric := state.InitialValue.(float64)
-			if isNumeric {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-// detectImportsFromJSX проверяет JSX на необходимость дополнительных импортов
+// detectImportsFromJSX добавляет пакеты компонентов из других файлов проекта
 func (g *TemplGenerator) detectImportsFromJSX(jsx *models.JSXElement, imports map[string]bool) {
 	if jsx == nil {
 		return
 	}
 
-	// Проверяем текущий элемент
-	for _, value := range jsx.Props {
-		if expr, ok := value.(map[string]interface{}); ok {
-			if code, ok := expr["code"].(string); ok {
-				// Проверяем выражения в атрибутах
-				g.detectImportsFromExpression(code, imports)
-			}
-		}
+	// Компонент из другого пакета проекта
+	if ref := g.options.Components[jsx.Type]; ref != nil && ref.ImportPath != g.options.TemplatesImportPath() {
+		imports[ref.ImportPath] = true
 	}
 
 	// Рекурсивно проверяем дочерние элементы
@@ -481,48 +531,22 @@ func (g *TemplGenerator) detectImportsFromJSX(jsx *models.JSXElement, imports ma
 	}
 }
 
-// detectImportsFromState проверяет состояния на необходимость дополнительных импортов
-func (g *TemplGenerator) detectImportsFromState(component *models.ReactComponent, imports map[string]bool) {
-	// Для работы с числами
-	for _, state := range component.State {
-		if state.Type == "number" || (state.InitialValue != nil && isNumeric(state.InitialValue)) {
-			imports["strconv"] = true
-			break
-		}
+// propValueToGo форматирует значение пропса как литерал Go. Выражение, не являющееся
+// литералом, выводится как есть
+func (g *TemplGenerator) propValueToGo(value interface{}) string {
+	valueMap, ok := value.(map[string]interface{})
+	if !ok {
+		return models.GoLiteral(value, "")
 	}
 
-	// Другие проверки для эффектов, колбэков и т.д.
-}
-
-// detectImportsFromExpression проверяет выражения на необходимость дополнительных импортов
-func (g *TemplGenerator) detectImportsFromExpression(expr string, imports map[string]bool) {
-	// Шаблонные строки требуют fmt
-	if strings.Contains(expr, "`") {
-		imports["fmt"] = true
+	code, ok := valueMap["code"].(string)
+	if !ok {
+		return "nil"
 	}
-
-	// Числовые операции могут требовать strconv
-	if strings.Contains(expr, ".toString()") ||
-		strings.Contains(expr, ".toFixed(") ||
-		strings.Contains(expr, "parseInt(") ||
-		strings.Contains(expr, "parseFloat(") {
-		imports["strconv"] = true
-	}
-
-	// Работа с датами может требовать time
-	if strings.Contains(expr, "new Date(") ||
-		strings.Contains(expr, ".getTime(") ||
-		strings.Contains(expr, ".getDate(") {
-		imports["time"] = true
-	}
-
-	// Регулярные выражения
-	if strings.Contains(expr, "RegExp(") ||
-		strings.Contains(expr, "match(") ||
-		strings.Contains(expr, "test(") ||
-		strings.Contains(expr, "replace(") {
-		imports["regexp"] = true
+	if literal, ok := models.ParseJSLiteral(code); ok {
+		return models.GoLiteral(literal, "")
 	}
+	return strings.TrimSpace(code)
 }
 
 // needsHelperFunctions проверяет, нужны ли вспомогательные функции для компонента
@@ -533,29 +557,10 @@ func (g *TemplGenerator) needsHelperFunctions(component *models.ReactComponent)
 
 // Вспомогательные функции
 
-// convertTypeToGo преобразует тип TypeScript в тип Go
+// convertTypeToGo преобразует тип TypeScript в тип Go. Разметка в пропсе (ReactNode)
+// передается templ компонентом, render props - функциями, колбэки - URL действий
 func (g *TemplGenerator) convertTypeToGo(tsType string) string {
-	switch tsType {
-	case "string":
-		return "string"
-	case "number":
-		return "int"
-	case "boolean":
-		return "bool"
-	case "any":
-		return "interface{}"
-	case "array":
-		return "[]interface{}"
-	case "object":
-		return "map[string]interface{}"
-	default:
-		if strings.HasPrefix(tsType, "Array<") {
-			elementType := tsType[6 : len(tsType)-1]
-			goElementType := g.convertTypeToGo(elementType)
-			return "[]" + goElementType
-		}
-		return "interface{}"
-	}
+	return models.PropGoType(tsType)
 }
 
 // getIndentation возвращает строку с отступом заданного уровня
@@ -565,25 +570,3 @@ func (g *TemplGenerator) getIndentation(level int) string {
 	}
 	return strings.Repeat(" ", g.indentSize*level)
 }
-
-// camelCaseToKebabCase преобразует camelCase в kebab-case
-func (g *TemplGenerator) camelCaseToKebabCase(s string) string {
-	var result strings.Builder
-	for i, r := range s {
-		if i > 0 && 'A' <= r && r <= 'Z' {
-			result.WriteRune('-')
-		}
-		result.WriteRune(r)
-	}
-	return strings.ToLower(result.String())
-}
-
-// isNumeric проверяет, является ли значение числовым
-func isNumeric(value interface{}) bool {
-	switch value.(type) {
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
-		return true
-	default:
-		return false
-	}
-}
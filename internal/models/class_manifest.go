@@ -0,0 +1,49 @@
+package models
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ClassManifest возвращает содержимое манифеста классов (<name>.classes.txt): по имени класса
+// в строке. Файл добавляется в content конфигурации Tailwind, чтобы классы, собираемые
+// при рендеринге, не удалялись из CSS
+func ClassManifest(classes []string) string {
+	if len(classes) == 0 {
+		return ""
+	}
+	return strings.Join(classes, "\n") + "\n"
+}
+
+// TailwindConfigSnippet возвращает фрагмент tailwind.config.js: templ файлы и манифесты
+// классов сканируются как content, классы манифеста дублируются в safelist
+func TailwindConfigSnippet(classes []string) string {
+	var sb strings.Builder
+	sb.WriteString("// Фрагмент tailwind.config.js для сконвертированных шаблонов\n")
+	sb.WriteString("module.exports = {\n")
+	sb.WriteString("  content: ['./**/*.templ', './**/*.go', './**/*.classes.txt'],\n")
+	sb.WriteString("  safelist: [\n")
+	for _, class := range classes {
+		sb.WriteString("    " + strconv.Quote(class) + ",\n")
+	}
+	sb.WriteString("  ],\n")
+	sb.WriteString("};\n")
+	return sb.String()
+}
+
+// MergeClasses объединяет списки классов без повторов в алфавитном порядке
+func MergeClasses(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var classes []string
+	for _, list := range lists {
+		for _, class := range list {
+			if !seen[class] {
+				seen[class] = true
+				classes = append(classes, class)
+			}
+		}
+	}
+	sort.Strings(classes)
+	return classes
+}
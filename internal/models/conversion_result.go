@@ -16,10 +16,19 @@ type ConversionResult struct {
 	HtmxJS       string `json:"htmxJS"`       // JavaScript для HTMX
 	PropsStruct  string `json:"propsStruct"`  // Структура Go для пропсов
 
-	ComponentName string                 `json:"componentName"` // Имя компонента
-	SourceFile    string                 `json:"sourceFile"`    // Имя исходного файла
-	ConvertedAt   string                 `json:"convertedAt"`   // Время конвертации
-	Settings      map[string]interface{} `json:"settings"`      // Настройки конвертации
+	ComponentName string                 `json:"componentName"`         // Имя компонента
+	PackagePath   string                 `json:"packagePath,omitempty"` // Каталог пакета шаблонов (при конвертации проекта)
+	SourceFile    string                 `json:"sourceFile"`            // Имя исходного файла
+	ConvertedAt   string                 `json:"convertedAt"`           // Время конвертации
+	Settings      map[string]interface{} `json:"settings"`              // Настройки конвертации
+	Warnings      []string               `json:"warnings,omitempty"`    // Предупреждения (неразрешенные хуки и т.д.)
+	Dataflow      *DataflowGraph         `json:"dataflow,omitempty"`    // Граф потоков данных состояний
+
+	Classes        []string `json:"classes,omitempty"`        // Классы разметки (манифест для Tailwind)
+	TailwindConfig string   `json:"tailwindConfig,omitempty"` // Фрагмент tailwind.config.js (если включен в опциях)
+	Stylesheet     string   `json:"stylesheet,omitempty"`     // Таблица стилей CSS модулей и стилизованных компонентов
+
+	RenderCheck *RenderCheck `json:"renderCheck,omitempty"` // Сверка рендеринга React и templ (если задана фикстура)
 }
 
 // NewConversionResult создает новый результат конвертации
@@ -63,6 +72,30 @@ func (r *ConversionResult) SaveToFiles(outputDir string) error {
 		}
 	}
 
+	// Сохраняем манифест классов
+	if len(r.Classes) > 0 {
+		classesPath := filepath.Join(outputDir, r.getClassesFileName())
+		if err := os.WriteFile(classesPath, []byte(ClassManifest(r.Classes)), 0644); err != nil {
+			return fmt.Errorf("ошибка записи манифеста классов: %w", err)
+		}
+	}
+
+	// Сохраняем фрагмент конфигурации Tailwind
+	if r.TailwindConfig != "" {
+		configPath := filepath.Join(outputDir, r.getTailwindConfigFileName())
+		if err := os.WriteFile(configPath, []byte(r.TailwindConfig), 0644); err != nil {
+			return fmt.Errorf("ошибка записи конфигурации Tailwind: %w", err)
+		}
+	}
+
+	// Сохраняем таблицу стилей
+	if r.Stylesheet != "" {
+		stylesheetPath := filepath.Join(outputDir, r.getStylesheetFileName())
+		if err := os.WriteFile(stylesheetPath, []byte(r.Stylesheet), 0644); err != nil {
+			return fmt.Errorf("ошибка записи таблицы стилей: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -88,6 +121,21 @@ func (r *ConversionResult) getJSFileName() string {
 	return fmt.Sprintf("%s.js", getComponentFileName(r.ComponentName))
 }
 
+// getClassesFileName возвращает имя файла манифеста классов
+func (r *ConversionResult) getClassesFileName() string {
+	return fmt.Sprintf("%s.classes.txt", getComponentFileName(r.ComponentName))
+}
+
+// getTailwindConfigFileName возвращает имя файла фрагмента конфигурации Tailwind
+func (r *ConversionResult) getTailwindConfigFileName() string {
+	return fmt.Sprintf("%s.tailwind.config.js", getComponentFileName(r.ComponentName))
+}
+
+// getStylesheetFileName возвращает имя файла таблицы стилей
+func (r *ConversionResult) getStylesheetFileName() string {
+	return fmt.Sprintf("%s.css", getComponentFileName(r.ComponentName))
+}
+
 // getComponentFileName возвращает имя файла компонента в нижнем регистре
 func getComponentFileName(componentName string) string {
 	var result bytes.Buffer
@@ -115,6 +163,15 @@ func (r *ConversionResult) GetSummary() map[string]interface{} {
 	if r.HtmxJS != "" {
 		files = append(files, r.getJSFileName())
 	}
+	if len(r.Classes) > 0 {
+		files = append(files, r.getClassesFileName())
+	}
+	if r.TailwindConfig != "" {
+		files = append(files, r.getTailwindConfigFileName())
+	}
+	if r.Stylesheet != "" {
+		files = append(files, r.getStylesheetFileName())
+	}
 
 	return map[string]interface{}{
 		"componentName": r.ComponentName,
@@ -122,5 +179,6 @@ func (r *ConversionResult) GetSummary() map[string]interface{} {
 		"convertedAt":   r.ConvertedAt,
 		"files":         files,
 		"settings":      r.Settings,
+		"warnings":      r.Warnings,
 	}
 }
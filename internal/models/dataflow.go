@@ -0,0 +1,120 @@
+package models
+
+// Классы состояний по результатам анализа потоков данных
+const (
+	StateKindServer   = "server"   // Состояние нужно серверу: хранится в памяти и имеет обработчик сеттера
+	StateKindClient   = "client"   // Локальное состояние интерфейса, сервер его не читает
+	StateKindDerived  = "derived"  // Вычисляется из других состояний и пропсов, сеттер из разметки не вызывается
+	StateKindReadOnly = "readonly" // Сеттер не вызывается: значение всегда начальное
+)
+
+// Виды узлов графа потоков данных
+const (
+	DataflowNodeState    = "state"
+	DataflowNodeJSX      = "jsx"
+	DataflowNodeHandler  = "handler"
+	DataflowNodeCallback = "callback"
+	DataflowNodeEffect   = "effect"
+)
+
+// Виды ребер графа потоков данных
+const (
+	DataflowEdgeReads   = "reads"   // Узел читает состояние (ребро от состояния к узлу)
+	DataflowEdgeWrites  = "writes"  // Узел меняет состояние через сеттер (ребро от узла к состоянию)
+	DataflowEdgeDepends = "depends" // Эффект перезапускается при изменении состояния (зависимость useEffect)
+	DataflowEdgeCalls   = "calls"   // Обработчик события вызывает колбэк компонента
+)
+
+// DataflowGraph описывает, где читаются и меняются состояния компонента
+type DataflowGraph struct {
+	Nodes   []DataflowNode    `json:"nodes"`
+	Edges   []DataflowEdge    `json:"edges"`
+	Classes map[string]string `json:"classes"` // Класс каждого состояния: server, client или derived
+}
+
+// DataflowNode описывает узел графа: состояние, узел JSX, обработчик, колбэк или эффект
+type DataflowNode struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// DataflowEdge описывает связь между узлами графа
+type DataflowEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// NewDataflowGraph создает пустой граф потоков данных
+func NewDataflowGraph() *DataflowGraph {
+	return &DataflowGraph{
+		Classes: make(map[string]string),
+	}
+}
+
+// AddNode добавляет узел, если узла с таким ID еще нет
+func (g *DataflowGraph) AddNode(id string, kind string, name string) {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return
+		}
+	}
+	g.Nodes = append(g.Nodes, DataflowNode{ID: id, Kind: kind, Name: name})
+}
+
+// AddEdge добавляет ребро, если такого ребра еще нет
+func (g *DataflowGraph) AddEdge(from string, to string, kind string) {
+	for _, edge := range g.Edges {
+		if edge.From == from && edge.To == to && edge.Kind == kind {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, DataflowEdge{From: from, To: to, Kind: kind})
+}
+
+// Readers возвращает узлы, читающие состояние
+func (g *DataflowGraph) Readers(state string) []DataflowNode {
+	return g.linked(StateNodeID(state), DataflowEdgeReads, false)
+}
+
+// Writers возвращает узлы, меняющие состояние
+func (g *DataflowGraph) Writers(state string) []DataflowNode {
+	return g.linked(StateNodeID(state), DataflowEdgeWrites, true)
+}
+
+// Dependents возвращает эффекты, зависящие от состояния
+func (g *DataflowGraph) Dependents(state string) []DataflowNode {
+	return g.linked(StateNodeID(state), DataflowEdgeDepends, false)
+}
+
+// linked возвращает узлы, связанные с узлом id ребрами вида kind
+// (incoming - ребра, входящие в узел id)
+func (g *DataflowGraph) linked(id string, kind string, incoming bool) []DataflowNode {
+	var nodes []DataflowNode
+	for _, edge := range g.Edges {
+		if edge.Kind != kind {
+			continue
+		}
+		other := ""
+		if incoming && edge.To == id {
+			other = edge.From
+		} else if !incoming && edge.From == id {
+			other = edge.To
+		}
+		if other == "" {
+			continue
+		}
+		for _, node := range g.Nodes {
+			if node.ID == other {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	return nodes
+}
+
+// StateNodeID возвращает ID узла состояния
+func StateNodeID(name string) string {
+	return DataflowNodeState + ":" + name
+}
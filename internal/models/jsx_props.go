@@ -0,0 +1,147 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// PropNames возвращает имена атрибутов элемента в порядке исходного кода.
+// Атрибуты, отсутствующие в PropOrder, идут следом по алфавиту
+func (j *JSXElement) PropNames() []string {
+	names := make([]string, 0, len(j.Props))
+	seen := make(map[string]bool, len(j.Props))
+	for _, name := range j.PropOrder {
+		if _, ok := j.Props[name]; ok && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+
+	var rest []string
+	for name := range j.Props {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(names, rest...)
+}
+
+// UnmarshalJSON разбирает JSX элемент. Если парсер не передал порядок атрибутов
+// (propOrder), он берется из порядка ключей объекта props
+func (j *JSXElement) UnmarshalJSON(data []byte) error {
+	type plainElement JSXElement
+	var raw struct {
+		plainElement
+		Props json.RawMessage `json:"props"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*j = JSXElement(raw.plainElement)
+	if len(raw.Props) == 0 || string(raw.Props) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw.Props, &j.Props); err != nil {
+		return err
+	}
+	if len(j.PropOrder) == 0 {
+		j.PropOrder = objectKeys(raw.Props)
+	}
+	return nil
+}
+
+// objectKeys возвращает ключи JSON объекта в порядке их следования
+func objectKeys(data []byte) []string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return keys
+		}
+		key, _ := token.(string)
+		var skipped json.RawMessage
+		if err := decoder.Decode(&skipped); err != nil {
+			return keys
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// reactAttributeNames сопоставляет имена пропсов React DOM, которые не переводятся
+// в kebab-case, с именами атрибутов HTML и SVG
+var reactAttributeNames = map[string]string{
+	"className":           "class",
+	"htmlFor":             "for",
+	"httpEquiv":           "http-equiv",
+	"acceptCharset":       "accept-charset",
+	"accessKey":           "accesskey",
+	"allowFullScreen":     "allowfullscreen",
+	"autoCapitalize":      "autocapitalize",
+	"autoComplete":        "autocomplete",
+	"autoFocus":           "autofocus",
+	"autoPlay":            "autoplay",
+	"cellPadding":         "cellpadding",
+	"cellSpacing":         "cellspacing",
+	"charSet":             "charset",
+	"colSpan":             "colspan",
+	"contentEditable":     "contenteditable",
+	"crossOrigin":         "crossorigin",
+	"dateTime":            "datetime",
+	"encType":             "enctype",
+	"enterKeyHint":        "enterkeyhint",
+	"formAction":          "formaction",
+	"formEncType":         "formenctype",
+	"formMethod":          "formmethod",
+	"formNoValidate":      "formnovalidate",
+	"formTarget":          "formtarget",
+	"frameBorder":         "frameborder",
+	"hrefLang":            "hreflang",
+	"inputMode":           "inputmode",
+	"itemProp":            "itemprop",
+	"itemScope":           "itemscope",
+	"itemType":            "itemtype",
+	"maxLength":           "maxlength",
+	"minLength":           "minlength",
+	"noModule":            "nomodule",
+	"noValidate":          "novalidate",
+	"playsInline":         "playsinline",
+	"readOnly":            "readonly",
+	"referrerPolicy":      "referrerpolicy",
+	"rowSpan":             "rowspan",
+	"spellCheck":          "spellcheck",
+	"srcDoc":              "srcdoc",
+	"srcLang":             "srclang",
+	"srcSet":              "srcset",
+	"tabIndex":            "tabindex",
+	"useMap":              "usemap",
+	"viewBox":             "viewBox",
+	"preserveAspectRatio": "preserveAspectRatio",
+}
+
+// HTMLAttributeName возвращает имя атрибута HTML для пропса React DOM: className -> class,
+// tabIndex -> tabindex; остальные camelCase имена (strokeWidth, SVG) - в kebab-case
+func HTMLAttributeName(name string) string {
+	if attribute, ok := reactAttributeNames[name]; ok {
+		return attribute
+	}
+
+	var result strings.Builder
+	for i, r := range name {
+		if i > 0 && 'A' <= r && r <= 'Z' {
+			result.WriteRune('-')
+		}
+		result.WriteRune(r)
+	}
+	return strings.ToLower(result.String())
+}
@@ -0,0 +1,370 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSObject - объектный литерал JavaScript с сохраненным порядком ключей
+type JSObject struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// ParseJSLiteral разбирает литерал JavaScript: числа, строки (без подстановок ${}),
+// true/false/null, массивы и объекты из литералов. ok = false, если код не литерал
+// (содержит обращения к переменным, вызовы и т.д.)
+func ParseJSLiteral(code string) (interface{}, bool) {
+	p := &jsLiteralParser{src: strings.TrimSpace(code)}
+	value, ok := p.value()
+	if !ok {
+		return nil, false
+	}
+	p.skipSpaces()
+	return value, p.pos == len(p.src)
+}
+
+// jsLiteralParser - рекурсивный разбор литерала JavaScript
+type jsLiteralParser struct {
+	src string
+	pos int
+}
+
+func (p *jsLiteralParser) skipSpaces() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *jsLiteralParser) value() (interface{}, bool) {
+	p.skipSpaces()
+	if p.pos >= len(p.src) {
+		return nil, false
+	}
+
+	switch ch := p.src[p.pos]; {
+	case ch == '"' || ch == '\'' || ch == '`':
+		return p.string(ch)
+	case ch == '[':
+		return p.array()
+	case ch == '{':
+		return p.object()
+	case ch == '-' || ch == '.' || (ch >= '0' && ch <= '9'):
+		return p.number()
+	}
+
+	for _, word := range []string{"true", "false", "null", "undefined"} {
+		if strings.HasPrefix(p.src[p.pos:], word) && !p.identAt(p.pos+len(word)) {
+			p.pos += len(word)
+			if word == "true" || word == "false" {
+				return word == "true", true
+			}
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// identAt проверяет, продолжается ли идентификатор в позиции pos
+func (p *jsLiteralParser) identAt(pos int) bool {
+	if pos >= len(p.src) {
+		return false
+	}
+	ch := p.src[pos]
+	return ch == '_' || ch == '$' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
+
+func (p *jsLiteralParser) string(quote byte) (interface{}, bool) {
+	var sb strings.Builder
+	for p.pos++; p.pos < len(p.src); p.pos++ {
+		ch := p.src[p.pos]
+		switch {
+		case ch == quote:
+			p.pos++
+			return sb.String(), true
+		case quote == '`' && ch == '$' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '{':
+			// Шаблонная строка с подстановкой - не литерал
+			return nil, false
+		case ch == '\\' && p.pos+1 < len(p.src):
+			p.pos++
+			switch escaped := p.src[p.pos]; escaped {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				sb.WriteByte(escaped)
+			}
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return nil, false
+}
+
+func (p *jsLiteralParser) number() (interface{}, bool) {
+	start := p.pos
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.src) && (p.src[p.pos] == '.' || p.src[p.pos] == '_' || p.src[p.pos] == 'e' || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+		p.pos++
+	}
+
+	number, err := strconv.ParseFloat(strings.ReplaceAll(p.src[start:p.pos], "_", ""), 64)
+	if err != nil {
+		return nil, false
+	}
+	return number, true
+}
+
+func (p *jsLiteralParser) array() (interface{}, bool) {
+	items := []interface{}{}
+	p.pos++
+	for {
+		p.skipSpaces()
+		if p.pos < len(p.src) && p.src[p.pos] == ']' {
+			p.pos++
+			return items, true
+		}
+
+		item, ok := p.value()
+		if !ok {
+			return nil, false
+		}
+		items = append(items, item)
+
+		if !p.separator(']') {
+			return nil, false
+		}
+	}
+}
+
+func (p *jsLiteralParser) object() (interface{}, bool) {
+	object := &JSObject{Values: make(map[string]interface{})}
+	p.pos++
+	for {
+		p.skipSpaces()
+		if p.pos < len(p.src) && p.src[p.pos] == '}' {
+			p.pos++
+			return object, true
+		}
+
+		// Ключ: идентификатор или строка
+		var key string
+		if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+			quoted, ok := p.string(p.src[p.pos])
+			if !ok {
+				return nil, false
+			}
+			key = quoted.(string)
+		} else {
+			start := p.pos
+			for p.identAt(p.pos) {
+				p.pos++
+			}
+			key = p.src[start:p.pos]
+		}
+
+		p.skipSpaces()
+		if key == "" || p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			// Сокращенная запись { count } ссылается на переменную
+			return nil, false
+		}
+		p.pos++
+
+		value, ok := p.value()
+		if !ok {
+			return nil, false
+		}
+		if _, exists := object.Values[key]; !exists {
+			object.Keys = append(object.Keys, key)
+		}
+		object.Values[key] = value
+
+		if !p.separator('}') {
+			return nil, false
+		}
+	}
+}
+
+// separator пропускает запятую между элементами; закрывающая скобка остается для вызывающего
+func (p *jsLiteralParser) separator(closing byte) bool {
+	p.skipSpaces()
+	if p.pos >= len(p.src) {
+		return false
+	}
+	if p.src[p.pos] == ',' {
+		p.pos++
+		return true
+	}
+	return p.src[p.pos] == closing
+}
+
+// GoLiteral форматирует значение литерала (из ParseJSLiteral или JSON) как литерал Go
+// типа goType: строки экранируются, массивы становятся срезами, объекты - структурами
+// (поля в порядке объявления типа) или map с упорядоченными ключами. Пустой goType
+// означает, что тип выводится из значения
+func GoLiteral(value interface{}, goType string) string {
+	switch {
+	case goType == "string":
+		if s, ok := value.(string); ok {
+			return strconv.Quote(s)
+		}
+		if value == nil {
+			return `""`
+		}
+		// Число в строке - как String(1) в JavaScript, без дробной части литерала float64
+		if number, ok := value.(float64); ok {
+			return strconv.Quote(strconv.FormatFloat(number, 'f', -1, 64))
+		}
+		return strconv.Quote(strings.Trim(GoLiteral(value, ""), `"`))
+
+	case goType == "int" || goType == "float64":
+		switch v := value.(type) {
+		case float64:
+			return formatNumber(v, goType)
+		case string:
+			if number, err := strconv.ParseFloat(v, 64); err == nil {
+				return formatNumber(number, goType)
+			}
+		case nil:
+			return "0"
+		}
+
+	case goType == "bool":
+		switch v := value.(type) {
+		case bool:
+			return strconv.FormatBool(v)
+		case nil:
+			return "false"
+		case string:
+			return strconv.FormatBool(v != "" && v != "false")
+		}
+
+	case strings.HasPrefix(goType, "[]"):
+		items, ok := value.([]interface{})
+		if !ok {
+			if value == nil {
+				return "nil"
+			}
+			break
+		}
+		var literals []string
+		for _, item := range items {
+			literals = append(literals, GoLiteral(item, goType[2:]))
+		}
+		return goType + "{" + strings.Join(literals, ", ") + "}"
+
+	case strings.HasPrefix(goType, "struct {"):
+		keys, values := objectEntries(value)
+		if keys == nil && value != nil {
+			break
+		}
+		var fields []string
+		for _, field := range structFields(goType) {
+			for _, key := range keys {
+				if exportedField(key) == field[0] {
+					fields = append(fields, fmt.Sprintf("%s: %s", field[0], GoLiteral(values[key], field[1])))
+				}
+			}
+		}
+		return goType + "{" + strings.Join(fields, ", ") + "}"
+	}
+
+	return inferGoLiteral(value)
+}
+
+// inferGoLiteral форматирует значение без известного типа
+func inferGoLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return formatNumber(v, "")
+	case []interface{}:
+		var literals []string
+		for _, item := range v {
+			literals = append(literals, inferGoLiteral(item))
+		}
+		return "[]interface{}{" + strings.Join(literals, ", ") + "}"
+	}
+
+	keys, values := objectEntries(value)
+	if keys == nil {
+		return fmt.Sprintf("%#v", value)
+	}
+	var entries []string
+	for _, key := range keys {
+		entries = append(entries, fmt.Sprintf("%s: %s", strconv.Quote(key), inferGoLiteral(values[key])))
+	}
+	return "map[string]interface{}{" + strings.Join(entries, ", ") + "}"
+}
+
+// objectEntries возвращает ключи объекта в порядке литерала (для map из JSON - по алфавиту)
+func objectEntries(value interface{}) ([]string, map[string]interface{}) {
+	switch v := value.(type) {
+	case *JSObject:
+		return append([]string{}, v.Keys...), v.Values
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys, v
+	}
+	return nil, nil
+}
+
+// structFields разбирает тип анонимной структуры "struct { X int; Y string }" в пары имя-тип
+func structFields(goType string) [][2]string {
+	body := strings.TrimSuffix(strings.TrimPrefix(goType, "struct {"), "}")
+
+	var fields [][2]string
+	depth, start := 0, 0
+	for i := 0; i <= len(body); i++ {
+		if i < len(body) {
+			switch body[i] {
+			case '{':
+				depth++
+				continue
+			case '}':
+				depth--
+				continue
+			case ';':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		if field := strings.TrimSpace(body[start:i]); field != "" {
+			if space := strings.Index(field, " "); space > 0 {
+				fields = append(fields, [2]string{field[:space], strings.TrimSpace(field[space+1:])})
+			}
+		}
+		start = i + 1
+	}
+	return fields
+}
+
+// formatNumber форматирует число. Числа JavaScript - float64, поэтому без известного типа
+// литерал получает дробную часть; дробное число для int не округляется
+func formatNumber(number float64, goType string) string {
+	formatted := strconv.FormatFloat(number, 'f', -1, 64)
+	if goType != "int" && !strings.Contains(formatted, ".") {
+		formatted += ".0"
+	}
+	return formatted
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ComponentRef описывает компонент из другого файла проекта, на который ссылается JSX:
+// где находится его templ компонент и какие параметры он принимает
+type ComponentRef struct {
+	Name       string           `json:"name"`                 // Имя компонента в исходном файле
+	Package    string           `json:"package"`              // Имя Go пакета шаблонов
+	ImportPath string           `json:"importPath"`           // Путь импорта Go пакета
+	Props      []PropDefinition `json:"props,omitempty"`      // Пропсы компонента
+	HasState   bool             `json:"hasState,omitempty"`   // Принимает ли templ компонент состояние
+	SourceFile string           `json:"sourceFile,omitempty"` // Файл, из которого компонент сконвертирован
+}
+
+// ContextRef описывает контекст, созданный в другом файле проекта: объявление createContext
+// и пакет шаблонов с функцией <Name>Value (пустой, если модуль без разметки не конвертируется)
+type ContextRef struct {
+	Definition ContextDefinition `json:"definition"`           // Объявление createContext
+	Package    string            `json:"package,omitempty"`    // Имя Go пакета шаблонов
+	ImportPath string            `json:"importPath,omitempty"` // Путь импорта Go пакета
+	SourceFile string            `json:"sourceFile,omitempty"` // Файл, в котором создается контекст
+}
+
+// FindProp ищет пропс компонента по имени
+func (r *ComponentRef) FindProp(name string) *PropDefinition {
+	for i := range r.Props {
+		if r.Props[i].Name == name {
+			return &r.Props[i]
+		}
+	}
+	return nil
+}
+
+// ProjectConversionResult содержит результаты конвертации проекта: входного файла
+// и всех компонентов, импортированных из него по относительным путям
+type ProjectConversionResult struct {
+	Entry    string              `json:"entry"`              // Входной файл
+	Files    []*ConversionResult `json:"files"`              // Результаты в порядке зависимостей (входной файл последний)
+	Warnings []string            `json:"warnings,omitempty"` // Неразрешенные импорты и циклы
+
+	Classes        []string `json:"classes,omitempty"`        // Классы разметки всех файлов (манифест для Tailwind)
+	TailwindConfig string   `json:"tailwindConfig,omitempty"` // Фрагмент tailwind.config.js для проекта
+}
+
+// SaveToFiles сохраняет результаты конвертации проекта, раскладывая файлы по каталогам пакетов.
+// Контроллеры объявлены в пакете controllers, поэтому сохраняются в отдельное дерево
+// каталогов: шаблоны templates/ui - контроллеры controllers/ui
+func (r *ProjectConversionResult) SaveToFiles(outputDir string) error {
+	for _, file := range r.Files {
+		templates := *file
+		templates.GoController = ""
+		if err := templates.SaveToFiles(filepath.Join(outputDir, filepath.FromSlash(file.PackagePath))); err != nil {
+			return fmt.Errorf("ошибка сохранения %s: %w", file.ComponentName, err)
+		}
+
+		if file.GoController == "" {
+			continue
+		}
+		controller := &ConversionResult{ComponentName: file.ComponentName, GoController: file.GoController}
+		if err := controller.SaveToFiles(filepath.Join(outputDir, filepath.FromSlash(controllersPath(file.PackagePath)))); err != nil {
+			return fmt.Errorf("ошибка сохранения контроллера %s: %w", file.ComponentName, err)
+		}
+	}
+
+	// Манифест классов и конфигурация Tailwind - общие для проекта, в корне каталога.
+	// Имя манифеста совпадает с шаблоном *.classes.txt из content конфигурации
+	if len(r.Classes) > 0 {
+		if err := os.WriteFile(filepath.Join(outputDir, "project.classes.txt"), []byte(ClassManifest(r.Classes)), 0644); err != nil {
+			return fmt.Errorf("ошибка записи манифеста классов: %w", err)
+		}
+	}
+	if r.TailwindConfig != "" {
+		if err := os.WriteFile(filepath.Join(outputDir, "tailwind.config.snippet.js"), []byte(r.TailwindConfig), 0644); err != nil {
+			return fmt.Errorf("ошибка записи конфигурации Tailwind: %w", err)
+		}
+	}
+	return nil
+}
+
+// controllersPath возвращает каталог контроллеров пакета шаблонов: корневой каталог
+// шаблонов заменяется на controllers, вложенные каталоги сохраняются
+func controllersPath(packagePath string) string {
+	if i := strings.Index(packagePath, "/"); i >= 0 {
+		return "controllers" + packagePath[i:]
+	}
+	return "controllers"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HasPropDefaults проверяет, задано ли у компонента значение по умолчанию хотя бы
+// для одного пропса: function Card({ size = 'md' }) или Card.defaultProps
+func HasPropDefaults(component *ReactComponent) bool {
+	for _, prop := range component.Props {
+		if prop.DefaultValue != nil && prop.Name != "children" && !prop.Rest {
+			return true
+		}
+	}
+	return false
+}
+
+// PropsConstructor возвращает функцию New<Name>Props, которая создает пропсы компонента
+// со значениями по умолчанию. Если значений по умолчанию нет, возвращает пустую строку
+func PropsConstructor(component *ReactComponent, indent string) string {
+	if !HasPropDefaults(component) {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// New%sProps создает пропсы компонента %s со значениями по умолчанию\n", component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("func New%sProps() %sProps {\n", component.Name, component.Name))
+	sb.WriteString(fmt.Sprintf("%sreturn %sProps{\n", indent, component.Name))
+	for _, prop := range component.Props {
+		if prop.DefaultValue == nil || prop.Name == "children" || prop.Rest {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s%s%s: %s,\n", indent, indent, exportedField(prop.Name), GoLiteral(prop.DefaultValue, PropFieldType(prop))))
+	}
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	sb.WriteString("}\n\n")
+
+	return sb.String()
+}
+
+// RequiredProps возвращает обязательные пропсы, которые передаются данными (JSON):
+// разметка, render props и атрибуты задаются только при вызове из родителя
+func RequiredProps(component *ReactComponent) []string {
+	var names []string
+	for _, prop := range component.Props {
+		if !prop.Required || prop.DefaultValue != nil || prop.Name == "children" || prop.Rest {
+			continue
+		}
+		goType := PropFieldType(prop)
+		if strings.HasPrefix(goType, "templ.") || strings.HasPrefix(goType, "func(") {
+			continue
+		}
+		names = append(names, prop.Name)
+	}
+	return names
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Типы TypeScript, значения которых - JSX разметка
+	slotTypeRegex = regexp.MustCompile(`^(React\.)?(ReactNode|ReactElement|ReactChild)(<.*>)?$|^JSX\.Element$`)
+	// Функциональный тип: (item: string, index: number) => ReactNode
+	functionTypeRegex = regexp.MustCompile(`^\(([\s\S]*)\)\s*=>\s*(.+)$`)
+	// Встроенный объектный тип: { x: number; y: string }
+	objectTypeRegex = regexp.MustCompile(`^\{([\s\S]*)\}$`)
+)
+
+// IsSlotType проверяет, описывает ли тип пропса JSX разметку (ReactNode, JSX.Element)
+func IsSlotType(tsType string) bool {
+	return slotTypeRegex.MatchString(strings.TrimSpace(tsType))
+}
+
+// RenderPropParams возвращает имена и типы Go параметров render prop по его типу
+// ((item: string) => ReactNode). ok = false, если тип не render prop
+func RenderPropParams(tsType string) (names []string, goTypes []string, ok bool) {
+	match := functionTypeRegex.FindStringSubmatch(strings.TrimSpace(tsType))
+	if match == nil || !IsSlotType(match[2]) {
+		return nil, nil, false
+	}
+
+	names, tsTypes := typeMembers(match[1], ",")
+	for _, paramType := range tsTypes {
+		goTypes = append(goTypes, PropGoType(paramType))
+	}
+	return names, goTypes, true
+}
+
+// IsCallbackType проверяет, описывает ли тип пропса колбэк (функцию, не возвращающую разметку).
+// Колбэк передается дочернему компоненту URL действия родителя
+func IsCallbackType(tsType string) bool {
+	match := functionTypeRegex.FindStringSubmatch(strings.TrimSpace(tsType))
+	return (match != nil && !IsSlotType(match[2])) || tsType == "function"
+}
+
+// PropFieldType возвращает тип Go поля структуры пропсов. Обработчики событий (onSelect)
+// без известного типа считаются колбэками, rest-параметр - атрибутами templ.Attributes
+func PropFieldType(prop PropDefinition) string {
+	if prop.Rest {
+		return "templ.Attributes"
+	}
+	if (prop.Type == "" || prop.Type == "any") && isEventName(prop.Name) {
+		return "string"
+	}
+	return PropGoType(prop.Type)
+}
+
+// PropGoType преобразует тип TypeScript пропса в тип Go
+func PropGoType(tsType string) string {
+	tsType = strings.TrimSpace(tsType)
+
+	switch tsType {
+	case "string":
+		return "string"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "any", "":
+		return "interface{}"
+	case "array":
+		return "[]interface{}"
+	case "object":
+		return "map[string]interface{}"
+	}
+
+	// Объединение строковых литералов: 'primary' | 'secondary'
+	if _, ok := StringUnionValues(tsType); ok {
+		return "string"
+	}
+
+	switch {
+	case strings.HasPrefix(tsType, "Array<") && strings.HasSuffix(tsType, ">"):
+		return "[]" + PropGoType(tsType[6:len(tsType)-1])
+	case strings.HasSuffix(tsType, "[]"):
+		return "[]" + PropGoType(strings.TrimSuffix(tsType, "[]"))
+	case IsSlotType(tsType):
+		// Разметка в пропсе передается templ компонентом
+		return "templ.Component"
+	case IsCallbackType(tsType):
+		// Колбэк -> URL действия родителя
+		return "string"
+	}
+
+	if _, goTypes, ok := RenderPropParams(tsType); ok {
+		return fmt.Sprintf("func(%s) templ.Component", strings.Join(goTypes, ", "))
+	}
+
+	// Встроенный объектный тип -> анонимная структура
+	if match := objectTypeRegex.FindStringSubmatch(tsType); match != nil {
+		names, tsTypes := typeMembers(match[1], ";")
+		fields := make([]string, len(names))
+		for i, name := range names {
+			fields[i] = fmt.Sprintf("%s %s", exportedField(name), PropGoType(tsTypes[i]))
+		}
+		return "struct { " + strings.Join(fields, "; ") + " }"
+	}
+
+	return "interface{}"
+}
+
+// StringUnionValues возвращает значения типа-объединения строковых литералов
+// ('primary' | 'secondary'). ok = false для других типов
+func StringUnionValues(tsType string) ([]string, bool) {
+	var values []string
+	for _, member := range strings.Split(tsType, "|") {
+		literal, ok := ParseJSLiteral(member)
+		if !ok {
+			return nil, false
+		}
+		value, ok := literal.(string)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+	return values, len(values) > 0
+}
+
+// typeMembers разбирает список "имя: тип" (параметры функции или поля объектного типа)
+func typeMembers(list string, sep string) (names []string, tsTypes []string) {
+	depth, start := 0, 0
+	var parts []string
+	for i, r := range list {
+		switch {
+		case r == '(' || r == '{' || r == '<' || r == '[':
+			depth++
+		case r == ')' || r == '}' || r == '>' || r == ']':
+			depth--
+		case depth == 0 && (string(r) == sep || (sep == ";" && r == ',')):
+			parts = append(parts, list[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, list[start:])
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, tsType := part, "any"
+		if colon := strings.Index(part, ":"); colon >= 0 {
+			name, tsType = strings.TrimSpace(part[:colon]), strings.TrimSpace(part[colon+1:])
+		}
+		names = append(names, strings.TrimSuffix(name, "?"))
+		tsTypes = append(tsTypes, tsType)
+	}
+	return names, tsTypes
+}
+
+// exportedField возвращает имя экспортируемого поля Go
+func exportedField(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// isEventName проверяет, является ли имя обработчиком события (onSelect)
+func isEventName(name string) bool {
+	return strings.HasPrefix(name, "on") && len(name) > 2 && name[2] >= 'A' && name[2] <= 'Z'
+}
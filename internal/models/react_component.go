@@ -4,15 +4,21 @@ import "fmt"
 
 // ReactComponent представляет структуру React компонента
 type ReactComponent struct {
-	Name      string                 `json:"name"`
-	Props     []PropDefinition       `json:"props"`
-	State     []StateDefinition      `json:"state"`
-	Effects   []EffectDefinition     `json:"effects"`
-	Callbacks []CallbackDefinition   `json:"callbacks"`
-	Refs      []RefDefinition        `json:"refs"`
-	JSX       *JSXElement            `json:"jsx"`
-	Imports   []ImportDefinition     `json:"imports,omitempty"`
-	Exports   map[string]interface{} `json:"exports,omitempty"`
+	Name        string                 `json:"name"`
+	Props       []PropDefinition       `json:"props"`
+	State       []StateDefinition      `json:"state"`
+	ClientState []StateDefinition      `json:"clientState,omitempty"` // Состояния на клиенте (Alpine.js), режимы alpine и hybrid
+	Effects     []EffectDefinition     `json:"effects"`
+	Callbacks   []CallbackDefinition   `json:"callbacks"`
+	Refs        []RefDefinition        `json:"refs"`
+	Reducers    []ReducerDefinition    `json:"reducers,omitempty"`
+	Contexts    []ContextDefinition    `json:"contexts,omitempty"`
+	Hooks       []HookDefinition       `json:"hooks,omitempty"`
+	Styled      []StyledDefinition     `json:"styled,omitempty"`
+	Wrappers    []string               `json:"wrappers,omitempty"` // Обертки снаружи внутрь: memo, forwardRef, observer, HOC
+	JSX         *JSXElement            `json:"jsx"`
+	Imports     []ImportDefinition     `json:"imports,omitempty"`
+	Exports     map[string]interface{} `json:"exports,omitempty"`
 }
 
 // PropDefinition описывает пропс компонента
@@ -21,6 +27,8 @@ type PropDefinition struct {
 	Type         string      `json:"type"`
 	Required     bool        `json:"required"`
 	DefaultValue interface{} `json:"defaultValue,omitempty"`
+	// Rest - rest-параметр ({ title, ...rest }): атрибуты, не объявленные в пропсах
+	Rest bool `json:"rest,omitempty"`
 }
 
 // StateDefinition описывает состояние компонента (useState)
@@ -29,14 +37,29 @@ type StateDefinition struct {
 	Setter       string      `json:"setter"`
 	Type         string      `json:"type,omitempty"`
 	InitialValue interface{} `json:"initialValue,omitempty"`
+	Kind         string      `json:"kind,omitempty"` // Класс по анализу потоков данных: server, client, derived или readonly
 }
 
 // EffectDefinition описывает эффект компонента (useEffect)
 type EffectDefinition struct {
 	Body         string   `json:"body"`
 	Dependencies []string `json:"dependencies"`
+	Kind         string   `json:"kind,omitempty"`     // "interval", "timeout", "subscription" или "generic"
+	Delay        int      `json:"delay,omitempty"`    // Задержка таймера в миллисекундах
+	Callback     string   `json:"callback,omitempty"` // Код, переданный в setInterval/setTimeout
+	Source       string   `json:"source,omitempty"`   // URL подписки (EventSource/WebSocket)
+	Cleanup      string   `json:"cleanup,omitempty"`  // Тело функции очистки, возвращаемой эффектом
 }
 
+// Виды эффектов
+const (
+	EffectKindGeneric      = "generic"
+	EffectKindInterval     = "interval"
+	EffectKindTimeout      = "timeout"
+	EffectKindSubscription = "subscription"
+	EffectKindDOM          = "dom" // Эффект только обращается к DOM refs (фокус, прокрутка)
+)
+
 // CallbackDefinition описывает колбэк компонента (useCallback)
 type CallbackDefinition struct {
 	Name         string   `json:"name"`
@@ -50,6 +73,56 @@ type RefDefinition struct {
 	InitialValue interface{} `json:"initialValue,omitempty"`
 }
 
+// ReducerDefinition описывает редьюсер компонента (useReducer)
+type ReducerDefinition struct {
+	Name        string             `json:"name"`        // Переменная состояния
+	Dispatch    string             `json:"dispatch"`    // Функция dispatch
+	Reducer     string             `json:"reducer"`     // Имя функции-редьюсера
+	StateParam  string             `json:"stateParam"`  // Имя параметра состояния в редьюсере
+	ActionParam string             `json:"actionParam"` // Имя параметра действия в редьюсере
+	Fields      []ReducerField     `json:"fields,omitempty"`
+	Actions     []ActionDefinition `json:"actions,omitempty"`
+}
+
+// ReducerField описывает поле состояния редьюсера
+type ReducerField struct {
+	Name         string      `json:"name"`
+	Type         string      `json:"type,omitempty"`
+	InitialValue interface{} `json:"initialValue,omitempty"`
+}
+
+// ContextDefinition описывает React контекст: createContext в модуле или useContext в компоненте
+type ContextDefinition struct {
+	Name         string      `json:"name"`                   // Имя контекста (ThemeContext)
+	Variable     string      `json:"variable,omitempty"`     // Переменная, получающая значение useContext
+	Type         string      `json:"type,omitempty"`         // Тип значения контекста
+	DefaultValue interface{} `json:"defaultValue,omitempty"` // Значение по умолчанию из createContext
+	Created      bool        `json:"created,omitempty"`      // createContext вызывается в этом модуле
+}
+
+// HookDefinition описывает вызов пользовательского хука. Состояния, эффекты и колбэки
+// разрешенного хука встраиваются парсером в компонент
+type HookDefinition struct {
+	Name     string `json:"name"`
+	Source   string `json:"source,omitempty"` // Модуль, из которого импортирован хук
+	Resolved bool   `json:"resolved"`         // Удалось ли найти и встроить тело хука
+}
+
+// StyledDefinition описывает стилизованный компонент styled-components или emotion:
+// const Title = styled.h1`color: ${p => p.color};`
+type StyledDefinition struct {
+	Name        string   `json:"name"`                  // Имя компонента (Title)
+	Tag         string   `json:"tag"`                   // HTML тег (h1)
+	Quasis      []string `json:"quasis"`                // Текст шаблона стилей между подстановками
+	Expressions []string `json:"expressions,omitempty"` // Код подстановок ${...}
+}
+
+// ActionDefinition описывает действие редьюсера (ветку switch по action.type)
+type ActionDefinition struct {
+	Type string `json:"type"`
+	Body string `json:"body"`
+}
+
 // ImportDefinition описывает импорт в компоненте
 type ImportDefinition struct {
 	Source   string   `json:"source"`
@@ -59,9 +132,16 @@ type ImportDefinition struct {
 
 // JSXElement представляет JSX элемент в React компоненте
 type JSXElement struct {
-	Type     string                 `json:"type"`
-	Props    map[string]interface{} `json:"props,omitempty"`
-	Children []*JSXElement          `json:"children,omitempty"`
+	Type      string                 `json:"type"`
+	Props     map[string]interface{} `json:"props,omitempty"`
+	PropOrder []string               `json:"propOrder,omitempty"` // Порядок атрибутов в исходном коде
+	Children  []*JSXElement          `json:"children,omitempty"`
+}
+
+// IsFragment проверяет, является ли элемент фрагментом: <>...</>, <Fragment> или
+// <React.Fragment key={...}>. Фрагмент не создает элемента, выводятся только его дочерние узлы
+func (j *JSXElement) IsFragment() bool {
+	return j.Type == "Fragment" || j.Type == "React.Fragment"
 }
 
 // Clone создает глубокую копию компонента
@@ -71,13 +151,18 @@ func (c *ReactComponent) Clone() *ReactComponent {
 	}
 
 	clone := &ReactComponent{
-		Name:      c.Name,
-		Imports:   make([]ImportDefinition, len(c.Imports)),
-		Props:     make([]PropDefinition, len(c.Props)),
-		State:     make([]StateDefinition, len(c.State)),
-		Effects:   make([]EffectDefinition, len(c.Effects)),
-		Callbacks: make([]CallbackDefinition, len(c.Callbacks)),
-		Refs:      make([]RefDefinition, len(c.Refs)),
+		Name:        c.Name,
+		Imports:     make([]ImportDefinition, len(c.Imports)),
+		Props:       make([]PropDefinition, len(c.Props)),
+		State:       make([]StateDefinition, len(c.State)),
+		ClientState: make([]StateDefinition, len(c.ClientState)),
+		Effects:     make([]EffectDefinition, len(c.Effects)),
+		Callbacks:   make([]CallbackDefinition, len(c.Callbacks)),
+		Refs:        make([]RefDefinition, len(c.Refs)),
+		Reducers:    make([]ReducerDefinition, len(c.Reducers)),
+		Contexts:    make([]ContextDefinition, len(c.Contexts)),
+		Hooks:       make([]HookDefinition, len(c.Hooks)),
+		Styled:      make([]StyledDefinition, len(c.Styled)),
 	}
 
 	// Копирование импортов
@@ -90,31 +175,21 @@ func (c *ReactComponent) Clone() *ReactComponent {
 		copy(clone.Imports[i].Named, imp.Named)
 	}
 
-	// Копирование пропсов
-	for i, prop := range c.Props {
-		clone.Props[i] = PropDefinition{
-			Name:         prop.Name,
-			Type:         prop.Type,
-			Required:     prop.Required,
-			DefaultValue: prop.DefaultValue,
-		}
-	}
-
-	// Копирование состояний
-	for i, state := range c.State {
-		clone.State[i] = StateDefinition{
-			Name:         state.Name,
-			Setter:       state.Setter,
-			Type:         state.Type,
-			InitialValue: state.InitialValue,
-		}
-	}
+	// Копирование пропсов и состояний: все поля (Rest, Kind) копируются вместе со структурой
+	copy(clone.Props, c.Props)
+	copy(clone.State, c.State)
+	copy(clone.ClientState, c.ClientState)
 
 	// Копирование эффектов
 	for i, effect := range c.Effects {
 		clone.Effects[i] = EffectDefinition{
 			Body:         effect.Body,
 			Dependencies: make([]string, len(effect.Dependencies)),
+			Kind:         effect.Kind,
+			Delay:        effect.Delay,
+			Callback:     effect.Callback,
+			Source:       effect.Source,
+			Cleanup:      effect.Cleanup,
 		}
 		copy(clone.Effects[i].Dependencies, effect.Dependencies)
 	}
@@ -137,6 +212,29 @@ func (c *ReactComponent) Clone() *ReactComponent {
 		}
 	}
 
+	// Копирование редьюсеров
+	for i, reducer := range c.Reducers {
+		clone.Reducers[i] = reducer
+		clone.Reducers[i].Fields = make([]ReducerField, len(reducer.Fields))
+		copy(clone.Reducers[i].Fields, reducer.Fields)
+		clone.Reducers[i].Actions = make([]ActionDefinition, len(reducer.Actions))
+		copy(clone.Reducers[i].Actions, reducer.Actions)
+	}
+
+	// Копирование контекстов и хуков
+	copy(clone.Contexts, c.Contexts)
+	copy(clone.Hooks, c.Hooks)
+
+	// Копирование стилизованных компонентов
+	for i, styled := range c.Styled {
+		clone.Styled[i] = styled
+		clone.Styled[i].Quasis = append([]string(nil), styled.Quasis...)
+		clone.Styled[i].Expressions = append([]string(nil), styled.Expressions...)
+	}
+
+	// Копирование оберток
+	clone.Wrappers = append([]string(nil), c.Wrappers...)
+
 	// Копирование JSX (рекурсивно)
 	if c.JSX != nil {
 		clone.JSX = c.JSX.Clone()
@@ -160,8 +258,9 @@ func (j *JSXElement) Clone() *JSXElement {
 	}
 
 	clone := &JSXElement{
-		Type:  j.Type,
-		Props: make(map[string]interface{}),
+		Type:      j.Type,
+		Props:     make(map[string]interface{}),
+		PropOrder: append([]string(nil), j.PropOrder...),
 	}
 
 	// Копирование свойств
@@ -211,5 +310,22 @@ func (c *ReactComponent) Validate() []string {
 		}
 	}
 
+	// Проверка редьюсеров
+	for i, reducer := range c.Reducers {
+		if reducer.Name == "" {
+			errors = append(errors, fmt.Sprintf("Reducer #%d: имя состояния не может быть пустым", i+1))
+		}
+		if reducer.Dispatch == "" {
+			errors = append(errors, fmt.Sprintf("Reducer #%d: dispatch не может быть пустым", i+1))
+		}
+	}
+
+	// Проверка контекстов
+	for i, context := range c.Contexts {
+		if context.Name == "" {
+			errors = append(errors, fmt.Sprintf("Context #%d: имя не может быть пустым", i+1))
+		}
+	}
+
 	return errors
 }
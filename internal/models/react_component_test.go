@@ -0,0 +1,80 @@
+package models_test
+
+import (
+	"reflect"
+	"testing"
+
+	"react-to-templ-converter/internal/models"
+)
+
+// fullComponent возвращает компонент, в котором заполнены все поля модели
+func fullComponent() *models.ReactComponent {
+	return &models.ReactComponent{
+		Name: "Card",
+		Props: []models.PropDefinition{
+			{Name: "title", Type: "string", Required: true, DefaultValue: "Title"},
+			{Name: "rest", Type: "object", Rest: true},
+		},
+		State: []models.StateDefinition{
+			{Name: "count", Setter: "setCount", Type: "number", InitialValue: 0.0, Kind: models.StateKindServer},
+			{Name: "total", Setter: "setTotal", Type: "number", InitialValue: 0.0, Kind: models.StateKindDerived},
+		},
+		ClientState: []models.StateDefinition{
+			{Name: "open", Setter: "setOpen", Type: "boolean", InitialValue: false, Kind: models.StateKindClient},
+		},
+		Effects: []models.EffectDefinition{
+			{Body: "{ setTotal(count * 2); }", Dependencies: []string{"count"}, Kind: models.EffectKindInterval,
+				Delay: 1000, Callback: "tick", Source: "/events", Cleanup: "clearInterval(id)"},
+		},
+		Callbacks: []models.CallbackDefinition{
+			{Name: "toggle", Body: "() => setOpen(!open)", Dependencies: []string{"open"}},
+		},
+		Refs: []models.RefDefinition{{Name: "inputRef", InitialValue: nil}},
+		Reducers: []models.ReducerDefinition{{
+			Name: "state", Dispatch: "dispatch", Reducer: "reducer", StateParam: "s", ActionParam: "a",
+			Fields:  []models.ReducerField{{Name: "step", Type: "number", InitialValue: 1.0}},
+			Actions: []models.ActionDefinition{{Type: "inc", Body: "return { ...s, step: s.step + 1 };"}},
+		}},
+		Contexts: []models.ContextDefinition{{Name: "ThemeContext", Variable: "theme", Type: "string", DefaultValue: "light"}},
+		Hooks:    []models.HookDefinition{{Name: "useToggle", Source: "./hooks", Resolved: true}},
+		Styled:   []models.StyledDefinition{{Name: "Title", Tag: "h1", Quasis: []string{"color: ", ";"}, Expressions: []string{"p => p.color"}}},
+		Wrappers: []string{"memo", "forwardRef"},
+		JSX: &models.JSXElement{
+			Type:      "div",
+			Props:     map[string]interface{}{"className": "card", "onClick": map[string]interface{}{"code": "toggle"}},
+			PropOrder: []string{"className", "onClick"},
+			Children: []*models.JSXElement{
+				{Type: "text", Props: map[string]interface{}{"content": "Hello"}},
+			},
+		},
+		Imports: []models.ImportDefinition{{Source: "react", Defaults: "React", Named: []string{"useState"}}},
+		Exports: map[string]interface{}{"default": "Card"},
+	}
+}
+
+func TestCloneCopiesAllFields(t *testing.T) {
+	component := fullComponent()
+	clone := component.Clone()
+
+	if !reflect.DeepEqual(clone, component) {
+		t.Errorf("копия отличается от компонента:\nкопия:     %+v\nкомпонент: %+v", clone, component)
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	component := fullComponent()
+	clone := component.Clone()
+
+	clone.Props[1].Rest = false
+	clone.State[0].Kind = models.StateKindClient
+	clone.Effects[0].Dependencies[0] = "total"
+	clone.Reducers[0].Fields[0].Name = "size"
+	clone.Wrappers[0] = "observer"
+	clone.JSX.Props["className"] = "changed"
+	clone.JSX.Children[0].Type = "span"
+	clone.Exports["default"] = "Other"
+
+	if !reflect.DeepEqual(component, fullComponent()) {
+		t.Errorf("изменение копии изменило компонент: %+v", component)
+	}
+}
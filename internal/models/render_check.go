@@ -0,0 +1,38 @@
+package models
+
+// RenderFixture задает данные, с которыми сверяется рендеринг: пропсы и значения
+// состояний по именам. Состояния, которых нет в фикстуре, получают начальные значения
+type RenderFixture struct {
+	Props map[string]interface{} `json:"props,omitempty"`
+	State map[string]interface{} `json:"state,omitempty"`
+}
+
+// RenderCheck содержит результат сверки HTML исходного компонента (react-dom/server)
+// и сгенерированного templ компонента на одной фикстуре
+type RenderCheck struct {
+	Equivalent  bool               `json:"equivalent"`            // Разметка совпадает после нормализации
+	ReactHTML   string             `json:"reactHTML,omitempty"`   // HTML, отрендеренный React
+	TemplHTML   string             `json:"templHTML,omitempty"`   // HTML, отрендеренный templ
+	Diagnostics []RenderDiagnostic `json:"diagnostics,omitempty"` // Расхождения
+}
+
+// RenderDiagnostic описывает одно расхождение разметки. Path - путь к узлу в дереве
+// React (/div/ul/li[2]), Expected - значение React, Actual - значение templ
+type RenderDiagnostic struct {
+	Kind      string `json:"kind"`
+	Path      string `json:"path,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+	Expected  string `json:"expected,omitempty"`
+	Actual    string `json:"actual,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Виды расхождений разметки
+const (
+	RenderDiffError     = "error"     // Рендеринг одной из сторон не удался
+	RenderDiffElement   = "element"   // Разные элементы на одном месте
+	RenderDiffAttribute = "attribute" // Разные, отсутствующие или лишние атрибуты
+	RenderDiffText      = "text"      // Разный текст
+	RenderDiffMissing   = "missing"   // Узел React отсутствует в templ
+	RenderDiffExtra     = "extra"     // Лишний узел templ
+)
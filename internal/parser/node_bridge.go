@@ -2,7 +2,6 @@ package parser
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -22,9 +21,16 @@ type NodeJSParser struct {
 	parserPath  string
 	parserPort  int
 	parserURL   string
+	renderURL   string
 	parserCmd   *exec.Cmd
 	parserReady chan struct{}
 	mutex       sync.Mutex
+	// Дополнительные HOC, которые парсер снимает с компонентов (кроме memo, forwardRef,
+	// observer, withRouter, connect и HOC из переменной окружения PARSER_HOCS)
+	hocs []string
+	// Рендеринг исходных компонентов (сверка): процесс парсера принимает /render только
+	// при запуске с RENDER_CHECK=1, так как выполняет код компонента
+	render bool
 }
 
 // NewNodeJSParser создает новый экземпляр парсера на Node.js
@@ -42,10 +48,22 @@ func NewNodeJSParser(parserPath string) *NodeJSParser {
 		parserPath:  parserPath,
 		parserPort:  port,
 		parserURL:   fmt.Sprintf("http://localhost:%d/parse", port),
+		renderURL:   fmt.Sprintf("http://localhost:%d/render", port),
 		parserReady: make(chan struct{}),
 	}
 }
 
+// SetHOCs задает дополнительные HOC, которые парсер снимает с компонентов: withAuth(Page)
+func (p *NodeJSParser) SetHOCs(hocs []string) {
+	p.hocs = hocs
+}
+
+// EnableRendering разрешает рендеринг исходных компонентов через react-dom/server
+// (RenderComponent). Вызывается до запуска парсера
+func (p *NodeJSParser) EnableRendering() {
+	p.render = true
+}
+
 // StartParser запускает Node.js парсер как отдельный процесс
 func (p *NodeJSParser) StartParser() error {
 	p.mutex.Lock()
@@ -100,6 +118,9 @@ func (p *NodeJSParser) StartParser() error {
 	cmd := exec.Command(nodePath, "index.js")
 	cmd.Dir = p.parserPath
 	cmd.Env = append(os.Environ(), fmt.Sprintf("PARSER_PORT=%d", p.parserPort))
+	if p.render {
+		cmd.Env = append(cmd.Env, "RENDER_CHECK=1")
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
@@ -153,36 +174,96 @@ func (p *NodeJSParser) StopParser() {
 
 // ParseComponent парсит React компонент и возвращает его структуру
 func (p *NodeJSParser) ParseComponent(code string) (*models.ReactComponent, error) {
+	return p.ParseComponentFile(code, "")
+}
+
+// ParseComponentFile парсит React компонент из файла filePath. Путь нужен парсеру
+// для разрешения пользовательских хуков, импортированных из локальных модулей
+func (p *NodeJSParser) ParseComponentFile(code string, filePath string) (*models.ReactComponent, error) {
 	// Запускаем парсер, если он еще не запущен
 	if err := p.StartParser(); err != nil {
 		return nil, fmt.Errorf("ошибка запуска парсера: %w", err)
 	}
 
 	// Готовим данные для отправки
-	requestData := map[string]string{
+	requestData := map[string]interface{}{
 		"code": code,
 	}
+	if filePath != "" {
+		if absPath, err := filepath.Abs(filePath); err == nil {
+			filePath = absPath
+		}
+		requestData["filePath"] = filePath
+	}
+	if len(p.hocs) > 0 {
+		requestData["hocs"] = p.hocs
+	}
 
-	jsonData, err := json.Marshal(requestData)
+	resp, err := p.post(p.parserURL, requestData)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка сериализации данных: %w", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Парсим ответ
+	var result models.ReactComponent
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("ошибка декодирования ответа: %w", err)
+	}
+
+	return &result, nil
+}
+
+// RenderComponent рендерит исходный компонент через react-dom/server с пропсами и
+// состояниями фикстуры и возвращает HTML (renderToStaticMarkup). Доступен только после
+// EnableRendering
+func (p *NodeJSParser) RenderComponent(code string, filePath string, componentName string, fixture *models.RenderFixture) (string, error) {
+	if !p.render {
+		return "", fmt.Errorf("рендеринг компонентов не включен (EnableRendering)")
+	}
+	if err := p.StartParser(); err != nil {
+		return "", fmt.Errorf("ошибка запуска парсера: %w", err)
 	}
 
-	// Устанавливаем таймаут для запроса
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	requestData := map[string]interface{}{
+		"code":          code,
+		"componentName": componentName,
+		"fixture":       fixture,
+	}
+	if filePath != "" {
+		if absPath, err := filepath.Abs(filePath); err == nil {
+			filePath = absPath
+		}
+		requestData["filePath"] = filePath
+	}
 
-	// Создаем HTTP запрос
-	req, err := http.NewRequestWithContext(ctx, "POST", p.parserURL, bytes.NewBuffer(jsonData))
+	resp, err := p.post(p.renderURL, requestData)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+		return "", err
 	}
+	defer resp.Body.Close()
 
-	req.Header.Set("Content-Type", "application/json")
+	var result struct {
+		HTML string `json:"html"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("ошибка декодирования ответа: %w", err)
+	}
+
+	return result.HTML, nil
+}
 
-	// Отправляем запрос
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// post отправляет запрос Node.js процессу. Если процесс не отвечает, он перезапускается
+// и запрос повторяется. Ответ с кодом, отличным от 200, возвращается ошибкой
+func (p *NodeJSParser) post(url string, requestData map[string]interface{}) (*http.Response, error) {
+	jsonData, err := json.Marshal(requestData)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации данных: %w", err)
+	}
+
+	// Таймаут клиента распространяется и на чтение ответа вызывающим кодом
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(jsonData))
 	if err != nil {
 		// Пробуем перезапустить парсер и повторить запрос
 		p.StopParser()
@@ -190,31 +271,18 @@ func (p *NodeJSParser) ParseComponent(code string) (*models.ReactComponent, erro
 			return nil, fmt.Errorf("ошибка перезапуска парсера: %w", err)
 		}
 
-		// Создаем новый запрос с таймаутом
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		req, _ = http.NewRequestWithContext(ctx, "POST", p.parserURL, bytes.NewBuffer(jsonData))
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err = client.Do(req)
+		resp, err = client.Post(url, "application/json", bytes.NewReader(jsonData))
 		if err != nil {
 			return nil, fmt.Errorf("ошибка отправки запроса после перезапуска: %w", err)
 		}
 	}
-	defer resp.Body.Close()
 
 	// Проверяем статус ответа
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
 		return nil, fmt.Errorf("ошибка ответа парсера (код %d): %s", resp.StatusCode, string(body))
 	}
 
-	// Парсим ответ
-	var result models.ReactComponent
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("ошибка декодирования ответа: %w", err)
-	}
-
-	return &result, nil
+	return resp, nil
 }
@@ -0,0 +1,173 @@
+package rendercheck
+
+import (
+	"fmt"
+	"react-to-templ-converter/internal/models"
+	"sort"
+	"strings"
+)
+
+// Compare сравнивает HTML исходного компонента (React) и сгенерированного (templ)
+// после нормализации и возвращает расхождения. Узлы сопоставляются по позиции
+// среди соседей, пути в диагностике строятся по дереву React
+func Compare(reactHTML string, templHTML string) []models.RenderDiagnostic {
+	react, err := parseHTML(reactHTML)
+	if err != nil {
+		return []models.RenderDiagnostic{{Kind: models.RenderDiffError, Message: fmt.Sprintf("Не удалось разобрать HTML React: %v", err)}}
+	}
+	templ, err := parseHTML(templHTML)
+	if err != nil {
+		return []models.RenderDiagnostic{{Kind: models.RenderDiffError, Message: fmt.Sprintf("Не удалось разобрать HTML templ: %v", err)}}
+	}
+
+	reactNodes := normalize(react)
+	templNodes := unwrapRoot(reactNodes, normalize(templ))
+
+	var diagnostics []models.RenderDiagnostic
+	compareChildren("", reactNodes, templNodes, &diagnostics)
+	return diagnostics
+}
+
+// compareChildren сравнивает списки дочерних узлов
+func compareChildren(parentPath string, react []*node, templ []*node, diagnostics *[]models.RenderDiagnostic) {
+	paths := childPaths(parentPath, react)
+	for i := 0; i < len(react) || i < len(templ); i++ {
+		switch {
+		case i >= len(templ):
+			*diagnostics = append(*diagnostics, models.RenderDiagnostic{
+				Kind:     models.RenderDiffMissing,
+				Path:     paths[i],
+				Expected: describe(react[i]),
+				Message:  "Узел есть в разметке React, но отсутствует в templ",
+			})
+		case i >= len(react):
+			*diagnostics = append(*diagnostics, models.RenderDiagnostic{
+				Kind:    models.RenderDiffExtra,
+				Path:    parentPath + "/" + nodeName(templ[i]),
+				Actual:  describe(templ[i]),
+				Message: "Лишний узел в разметке templ",
+			})
+		default:
+			compareNodes(paths[i], react[i], templ[i], diagnostics)
+		}
+	}
+}
+
+// compareNodes сравнивает два узла и их поддеревья
+func compareNodes(path string, react *node, templ *node, diagnostics *[]models.RenderDiagnostic) {
+	if react.Tag == "" || templ.Tag == "" {
+		if react.Tag != templ.Tag {
+			*diagnostics = append(*diagnostics, models.RenderDiagnostic{
+				Kind:     models.RenderDiffElement,
+				Path:     path,
+				Expected: describe(react),
+				Actual:   describe(templ),
+				Message:  "На месте узла React в templ другой узел",
+			})
+		} else if react.Text != templ.Text {
+			*diagnostics = append(*diagnostics, models.RenderDiagnostic{
+				Kind:     models.RenderDiffText,
+				Path:     path,
+				Expected: react.Text,
+				Actual:   templ.Text,
+				Message:  "Текст различается",
+			})
+		}
+		return
+	}
+
+	if react.Tag != templ.Tag {
+		*diagnostics = append(*diagnostics, models.RenderDiagnostic{
+			Kind:     models.RenderDiffElement,
+			Path:     path,
+			Expected: "<" + react.Tag + ">",
+			Actual:   "<" + templ.Tag + ">",
+			Message:  "Элементы различаются",
+		})
+		return
+	}
+
+	for _, name := range attributeNames(react, templ) {
+		expected, inReact := react.Attrs[name]
+		actual, inTempl := templ.Attrs[name]
+		diagnostic := models.RenderDiagnostic{Kind: models.RenderDiffAttribute, Path: path, Attribute: name, Expected: expected, Actual: actual}
+		switch {
+		case !inTempl:
+			diagnostic.Message = fmt.Sprintf("Атрибут %s отсутствует в templ", name)
+		case !inReact:
+			diagnostic.Message = fmt.Sprintf("Лишний атрибут %s в templ", name)
+		case expected != actual:
+			diagnostic.Message = fmt.Sprintf("Значение атрибута %s различается", name)
+		default:
+			continue
+		}
+		*diagnostics = append(*diagnostics, diagnostic)
+	}
+
+	compareChildren(path, react.Children, templ.Children, diagnostics)
+}
+
+// attributeNames возвращает имена атрибутов обоих элементов по алфавиту
+func attributeNames(react *node, templ *node) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, attrs := range []map[string]string{react.Attrs, templ.Attrs} {
+		for name := range attrs {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// childPaths возвращает пути дочерних узлов: /div/ul/li[2]. Номер добавляется,
+// если у родителя несколько узлов с тем же именем
+func childPaths(parentPath string, nodes []*node) []string {
+	counts := make(map[string]int)
+	for _, n := range nodes {
+		counts[nodeName(n)]++
+	}
+
+	seen := make(map[string]int)
+	paths := make([]string, len(nodes))
+	for i, n := range nodes {
+		name := nodeName(n)
+		seen[name]++
+		if counts[name] > 1 {
+			name = fmt.Sprintf("%s[%d]", name, seen[name])
+		}
+		paths[i] = parentPath + "/" + name
+	}
+	return paths
+}
+
+// nodeName возвращает имя узла в пути: тег или text()
+func nodeName(n *node) string {
+	if n.Tag == "" {
+		return "text()"
+	}
+	return n.Tag
+}
+
+// describe возвращает краткое описание узла для диагностики
+func describe(n *node) string {
+	if n.Tag == "" {
+		return n.Text
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<" + n.Tag)
+	names := attributeNames(n, &node{})
+	for _, name := range names {
+		if n.Attrs[name] == "" {
+			sb.WriteString(" " + name)
+			continue
+		}
+		sb.WriteString(fmt.Sprintf(" %s=%q", name, n.Attrs[name]))
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
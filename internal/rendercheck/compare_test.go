@@ -0,0 +1,52 @@
+package rendercheck
+
+import (
+	"testing"
+
+	"react-to-templ-converter/internal/models"
+)
+
+// TestCompareNormalizesMarkup проверяет, что различия, не влияющие на разметку,
+// не считаются расхождениями: порядок атрибутов и классов, пробелы, булевы атрибуты,
+// атрибуты htmx и Alpine.js, id экземпляра и обертка корневого фрагмента
+func TestCompareNormalizesMarkup(t *testing.T) {
+	react := `<h1 class="title big" data-x="1">Hello, Bob</h1><input disabled="" type="text"/><p style="color:red;font-size:12px">a &amp; b</p>`
+	templ := `<div id="List-render-check">
+	<h1 data-x="1" class="big  title" hx-post="/api/list/x?id=render-check">
+		Hello,
+		Bob
+	</h1>
+	<input type="text" disabled>
+	<div hx-trigger="every 1s" hx-post="/api/list/effect/1"></div>
+	<p x-show="open" style="font-size: 12px; color: red">a &amp; b</p>
+</div>`
+
+	if diagnostics := Compare(react, templ); len(diagnostics) != 0 {
+		t.Fatalf("ожидалась эквивалентная разметка, расхождения: %+v", diagnostics)
+	}
+}
+
+// TestCompareReportsDifferences проверяет виды и пути расхождений
+func TestCompareReportsDifferences(t *testing.T) {
+	react := `<ul><li>one</li><li class="active">two</li></ul><footer></footer>`
+	templ := `<ul><li>one</li><li>three</li></ul><footer></footer><aside></aside>`
+
+	diagnostics := Compare(react, templ)
+	expected := []models.RenderDiagnostic{
+		{Kind: models.RenderDiffAttribute, Path: "/ul/li[2]", Attribute: "class"},
+		{Kind: models.RenderDiffText, Path: "/ul/li[2]/text()", Expected: "two", Actual: "three"},
+		{Kind: models.RenderDiffExtra, Path: "/aside"},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("ожидалось %d расхождения, получено %d: %+v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i, want := range expected {
+		got := diagnostics[i]
+		if got.Kind != want.Kind || got.Path != want.Path || got.Attribute != want.Attribute {
+			t.Errorf("расхождение %d: ожидалось %+v, получено %+v", i, want, got)
+		}
+		if want.Expected != "" && (got.Expected != want.Expected || got.Actual != want.Actual) {
+			t.Errorf("расхождение %d: ожидалось %q -> %q, получено %q -> %q", i, want.Expected, want.Actual, got.Expected, got.Actual)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package rendercheck
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+)
+
+// node - узел нормализованного DOM: элемент или текст
+type node struct {
+	Tag      string // Пусто у текстового узла
+	Text     string
+	Attrs    map[string]string
+	Children []*node
+}
+
+// parseHTML разбирает фрагмент HTML в список узлов верхнего уровня. Используется
+// нестрогий режим encoding/xml: пустые элементы HTML закрываются автоматически,
+// сущности HTML раскрываются
+func parseHTML(html string) ([]*node, error) {
+	decoder := xml.NewDecoder(strings.NewReader("<root>" + html + "</root>"))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	root := &node{Tag: "root"}
+	stack := []*node{root}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &node{Tag: strings.ToLower(t.Name.Local), Attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				name := strings.ToLower(attr.Name.Local)
+				if attr.Name.Space != "" {
+					name = strings.ToLower(attr.Name.Space) + ":" + name
+				}
+				element.Attrs[name] = attr.Value
+			}
+			parent.Children = append(parent.Children, element)
+			stack = append(stack, element)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			parent.Children = append(parent.Children, &node{Text: string(t)})
+		}
+	}
+
+	// Первый узел - обертка <root>, внутри которой разбирался фрагмент
+	if len(root.Children) == 1 && root.Children[0].Tag == "root" {
+		return root.Children[0].Children, nil
+	}
+	return root.Children, nil
+}
+
+// normalize приводит узлы к виду, в котором их можно сравнивать: соседний текст
+// объединяется, пробелы схлопываются, пустой текст удаляется. Атрибуты и элементы,
+// которые добавляет конвертер (htmx, Alpine.js, id экземпляров, триггеры эффектов),
+// удаляются: они не влияют на разметку до запуска клиентского кода
+func normalize(nodes []*node) []*node {
+	var result []*node
+	for _, n := range nodes {
+		if n.Tag == "" {
+			text := n.Text
+			if len(result) > 0 && result[len(result)-1].Tag == "" {
+				result[len(result)-1].Text += text
+				continue
+			}
+			result = append(result, &node{Text: text})
+			continue
+		}
+
+		if n.Tag == "script" || isEffectTrigger(n) {
+			continue
+		}
+		attrs := make(map[string]string)
+		for name, value := range n.Attrs {
+			if isConverterAttribute(name, value) {
+				continue
+			}
+			attrs[name] = normalizeAttribute(name, value)
+		}
+		result = append(result, &node{Tag: n.Tag, Attrs: attrs, Children: normalize(n.Children)})
+	}
+
+	// Текст сравнивается со схлопнутыми пробелами: форматирование templ файла
+	// добавляет переводы строк между элементами
+	filtered := result[:0]
+	for _, n := range result {
+		if n.Tag == "" {
+			n.Text = strings.Join(strings.Fields(n.Text), " ")
+			if n.Text == "" {
+				continue
+			}
+		}
+		filtered = append(filtered, n)
+	}
+	return filtered
+}
+
+// isConverterAttribute проверяет, добавлен ли атрибут конвертером: атрибуты htmx
+// и Alpine.js, а также id, производные от id экземпляра
+func isConverterAttribute(name string, value string) bool {
+	for _, prefix := range []string{"hx-", "x-", "sse-", "ws-", "@", ":"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return name == "id" && strings.Contains(value, FixtureID)
+}
+
+// isEffectTrigger проверяет, является ли элемент триггером эффекта (таймер, подписка):
+// пустой div с hx-trigger или sse-connect
+func isEffectTrigger(n *node) bool {
+	if n.Tag != "div" || len(n.Children) > 0 {
+		return false
+	}
+	_, trigger := n.Attrs["hx-trigger"]
+	_, sse := n.Attrs["sse-connect"]
+	return trigger || sse
+}
+
+// normalizeAttribute приводит значение атрибута к каноническому виду: классы и
+// объявления style сортируются, булевы атрибуты (disabled, disabled="",
+// disabled="disabled") получают пустое значение
+func normalizeAttribute(name string, value string) string {
+	switch {
+	case value == name:
+		return ""
+	case name == "class":
+		classes := strings.Fields(value)
+		sort.Strings(classes)
+		return strings.Join(classes, " ")
+	case name == "style":
+		var declarations []string
+		for _, declaration := range strings.Split(value, ";") {
+			property, val, ok := strings.Cut(declaration, ":")
+			if !ok {
+				continue
+			}
+			declarations = append(declarations, strings.TrimSpace(property)+":"+strings.TrimSpace(val))
+		}
+		sort.Strings(declarations)
+		return strings.Join(declarations, ";")
+	}
+	return value
+}
+
+// unwrapRoot снимает обертку корневого фрагмента: конвертер оборачивает несколько
+// корневых узлов в <div>, который после нормализации остается без атрибутов
+func unwrapRoot(react []*node, templ []*node) []*node {
+	if len(react) != 1 && len(templ) == 1 && templ[0].Tag == "div" && len(templ[0].Attrs) == 0 {
+		return templ[0].Children
+	}
+	return templ
+}
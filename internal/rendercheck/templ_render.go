@@ -0,0 +1,203 @@
+package rendercheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// FixtureID - id экземпляра компонента при сверке. Сгенерированные id элементов
+// содержат его, поэтому сравнение их пропускает
+const FixtureID = "render-check"
+
+// templModule - модуль templ, с которым собирается временный модуль (если версию
+// не удалось взять из сборки конвертера)
+const (
+	templModule  = "github.com/a-h/templ"
+	templVersion = "v0.3.833"
+)
+
+// buildTimeout ограничивает генерацию и сборку временного модуля
+const buildTimeout = 2 * time.Minute
+
+// RenderTempl рендерит сгенерированный templ компонент: файл шаблона собирается во
+// временном модуле Go вместе с программой, которая вызывает компонент с пропсами и
+// состоянием фикстуры и выводит HTML. Код шаблона проверяется в том виде, в каком его
+// выдал конвертер: программа вызывает компонент из своего пакета, как контроллеры.
+// Для генерации нужен templ в PATH (иначе используется go run
+// github.com/a-h/templ/cmd/templ той же версии)
+func RenderTempl(templFile string, componentName string, props map[string]interface{}, state map[string]interface{}) (string, error) {
+	if componentName == "" {
+		return "", fmt.Errorf("не указано имя компонента")
+	}
+	funcName := strings.ToUpper(componentName[:1]) + componentName[1:]
+	params, ok := templParams(templFile, funcName)
+	if !ok {
+		return "", fmt.Errorf("templ компонент %s не найден в шаблоне", funcName)
+	}
+
+	dir, err := os.MkdirTemp("", "render-check-")
+	if err != nil {
+		return "", fmt.Errorf("ошибка создания каталога: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	packageName := templPackage(templFile)
+	fixture, err := json.Marshal(map[string]interface{}{"props": props, "state": state})
+	if err != nil {
+		return "", fmt.Errorf("ошибка сериализации фикстуры: %w", err)
+	}
+
+	files := map[string]string{
+		"go.mod":       fmt.Sprintf("module rendercheck\n\ngo 1.24\n\nrequire %s %s\n", templModule, moduleVersion()),
+		"main.go":      mainSource(packageName, componentName, funcName, params, templFile),
+		"fixture.json": string(fixture),
+		filepath.Join(packageName, "component.templ"): templFile,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", fmt.Errorf("ошибка создания каталога: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return "", fmt.Errorf("ошибка записи %s: %w", name, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), buildTimeout)
+	defer cancel()
+
+	if _, err := run(ctx, dir, templCommand(filepath.Join(dir, packageName, "component.templ"))...); err != nil {
+		return "", fmt.Errorf("ошибка генерации templ: %w", err)
+	}
+	html, err := run(ctx, dir, "go", "run", ".", "fixture.json")
+	if err != nil {
+		return "", fmt.Errorf("ошибка сборки или рендеринга templ: %w", err)
+	}
+
+	return html, nil
+}
+
+// templParams возвращает параметры templ компонента funcName
+func templParams(templFile string, funcName string) ([][2]string, bool) {
+	match := regexp.MustCompile(`(?m)^templ ` + regexp.QuoteMeta(funcName) + `\(([^)]*)\)`).FindStringSubmatch(templFile)
+	if match == nil {
+		return nil, false
+	}
+
+	var params [][2]string
+	for _, param := range strings.Split(match[1], ",") {
+		if fields := strings.Fields(param); len(fields) == 2 {
+			params = append(params, [2]string{fields[0], fields[1]})
+		}
+	}
+	return params, true
+}
+
+// templPackage возвращает пакет templ файла
+func templPackage(templFile string) string {
+	if match := regexp.MustCompile(`(?m)^package (\w+)`).FindStringSubmatch(templFile); match != nil {
+		return match[1]
+	}
+	return "templates"
+}
+
+// mainSource генерирует программу, которая разбирает фикстуру в структуры пропсов
+// и состояния, рендерит компонент из пакета шаблонов и выводит HTML в stdout
+func mainSource(packageName string, componentName string, funcName string, params [][2]string, templFile string) string {
+	var sb strings.Builder
+	sb.WriteString("package main\n\n")
+	sb.WriteString(fmt.Sprintf("import (\n\t\"context\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"os\"\n\n\t\"rendercheck/%s\"\n)\n\n", packageName))
+	sb.WriteString("func main() {\n")
+	sb.WriteString("\tdata, err := os.ReadFile(os.Args[1])\n")
+	sb.WriteString("\tif err == nil {\n\t\terr = render(data)\n\t}\n")
+	sb.WriteString("\tif err != nil {\n\t\tfmt.Fprintln(os.Stderr, err)\n\t\tos.Exit(1)\n\t}\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// render рендерит компонент с пропсами и состоянием фикстуры\n")
+	sb.WriteString("func render(data []byte) error {\n")
+	sb.WriteString("\tvar fixture struct {\n\t\tProps json.RawMessage `json:\"props\"`\n\t\tState json.RawMessage `json:\"state\"`\n\t}\n")
+	sb.WriteString("\tif err := json.Unmarshal(data, &fixture); err != nil {\n\t\treturn err\n\t}\n")
+
+	var args []string
+	for _, param := range params {
+		name, goType := param[0], qualifiedType(packageName, param[1])
+		switch {
+		case name == "id" && goType == "string":
+			args = append(args, fmt.Sprintf("%q", FixtureID))
+			continue
+		case param[1] == componentName+"Props":
+			if strings.Contains(templFile, fmt.Sprintf("func New%sProps()", componentName)) {
+				sb.WriteString(fmt.Sprintf("\t%s := %s.New%sProps()\n", name, packageName, componentName))
+			} else {
+				sb.WriteString(fmt.Sprintf("\tvar %s %s\n", name, goType))
+			}
+			sb.WriteString(fmt.Sprintf("\tif len(fixture.Props) > 0 && string(fixture.Props) != \"null\" {\n\t\tif err := json.Unmarshal(fixture.Props, &%s); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", name))
+		case param[1] == componentName+"State":
+			sb.WriteString(fmt.Sprintf("\tvar %s %s\n", name, goType))
+			sb.WriteString(fmt.Sprintf("\tif len(fixture.State) > 0 && string(fixture.State) != \"null\" {\n\t\tif err := json.Unmarshal(fixture.State, &%s); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", name))
+		default:
+			sb.WriteString(fmt.Sprintf("\tvar %s %s\n", name, goType))
+		}
+		args = append(args, name)
+	}
+
+	sb.WriteString(fmt.Sprintf("\treturn %s.%s(%s).Render(context.Background(), os.Stdout)\n", packageName, funcName, strings.Join(args, ", ")))
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// qualifiedType добавляет пакет шаблонов к типам, объявленным в нем (CounterProps,
+// []ItemProps). Встроенные типы и типы других пакетов остаются как есть
+func qualifiedType(packageName string, goType string) string {
+	prefix := goType[:len(goType)-len(strings.TrimLeft(goType, "[]*"))]
+	name := goType[len(prefix):]
+	if name == "" || strings.Contains(name, ".") || name[0] < 'A' || name[0] > 'Z' {
+		return goType
+	}
+	return prefix + packageName + "." + name
+}
+
+// templCommand возвращает команду генерации Go кода из templ файла
+func templCommand(path string) []string {
+	if _, err := exec.LookPath("templ"); err == nil {
+		return []string{"templ", "generate", "-f", path}
+	}
+	return []string{"go", "run", templModule + "/cmd/templ@" + moduleVersion(), "generate", "-f", path}
+}
+
+// moduleVersion возвращает версию templ, с которой собран конвертер
+func moduleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == templModule && strings.HasPrefix(dep.Version, "v") {
+				return dep.Version
+			}
+		}
+	}
+	return templVersion
+}
+
+// run выполняет команду в каталоге dir и возвращает ее stdout; при ошибке в нее
+// включается stderr
+func run(ctx context.Context, dir string, command ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -2,11 +2,15 @@
 const fs = require('fs');
 const path = require('path');
 const parser = require('./dist/parser'); // Используем скомпилированный JavaScript
+const renderer = require('./dist/renderer');
 const http = require('http');
 
 // Порт для HTTP сервера парсера
 const PORT = process.env.PARSER_PORT || 3001;
 
+// Рендеринг исходных компонентов выполняет их код, поэтому включается явно (сверка рендеринга)
+const RENDER_CHECK = process.env.RENDER_CHECK === '1';
+
 // Создаем HTTP сервер для обработки запросов от Go
 const server = http.createServer((req, res) => {
     if (req.method === 'POST' && req.url === '/parse') {
@@ -20,9 +24,13 @@ const server = http.createServer((req, res) => {
             try {
                 const requestData = JSON.parse(body);
                 const code = requestData.code;
+                // Путь к файлу (необязательный) нужен для разрешения хуков из локальных модулей
+                const filePath = requestData.filePath;
+                // Дополнительные HOC (необязательно), которые снимаются с компонента
+                const hocs = requestData.hocs;
 
                 // Парсим React/TypeScript код
-                const result = parser.parseReactComponent(code);
+                const result = parser.parseReactComponent(code, filePath, hocs);
 
                 // Отправляем результат обратно в Go
                 res.writeHead(200, { 'Content-Type': 'application/json' });
@@ -33,6 +41,32 @@ const server = http.createServer((req, res) => {
                 res.end(JSON.stringify({ error: error.message }));
             }
         });
+    } else if (RENDER_CHECK && req.method === 'POST' && req.url === '/render') {
+        // Сверка рендеринга: исходный компонент рендерится react-dom/server с фикстурой
+        let body = '';
+
+        req.on('data', chunk => {
+            body += chunk.toString();
+        });
+
+        req.on('end', () => {
+            try {
+                const requestData = JSON.parse(body);
+                const html = renderer.renderReactComponent(
+                    requestData.code,
+                    requestData.componentName,
+                    requestData.fixture || {},
+                    requestData.filePath
+                );
+
+                res.writeHead(200, { 'Content-Type': 'application/json' });
+                res.end(JSON.stringify({ html }));
+            } catch (error) {
+                console.error('Ошибка рендеринга:', error);
+                res.writeHead(500, { 'Content-Type': 'application/json' });
+                res.end(JSON.stringify({ error: error.message }));
+            }
+        });
     } else {
         res.writeHead(404);
         res.end();
@@ -4,6 +4,7 @@ import * as babel from '@babel/core';
 interface JSXElementInfo {
     type: string;
     props: Record<string, any>;
+    propOrder?: string[]; // Порядок атрибутов в исходном коде
     children: JSXElementInfo[];
 }
 
@@ -31,8 +32,19 @@ export function transformJSX(node: babel.types.Node, sourceCode: string): JSXEle
         // Получаем имя тега или компонента
         const tagName = getJSXElementName(element.name);
 
-        // Извлекаем атрибуты (props)
-        const props = extractJSXAttributes(element.attributes, sourceCode);
+        // <Fragment key={...}> и <React.Fragment> - тот же фрагмент: key не выводится в разметку,
+        // но сохраняется для элементов списков
+        if (tagName === 'Fragment' || tagName === 'React.Fragment') {
+            const { props } = extractJSXAttributes(element.attributes, sourceCode);
+            return {
+                type: 'Fragment',
+                props: props.key !== undefined ? { key: props.key } : {},
+                children: extractJSXChildren(node.children, sourceCode),
+            };
+        }
+
+        // Извлекаем атрибуты (props) и их порядок
+        const { props, propOrder } = extractJSXAttributes(element.attributes, sourceCode);
 
         // Извлекаем дочерние элементы
         const children = extractJSXChildren(node.children, sourceCode);
@@ -40,19 +52,14 @@ export function transformJSX(node: babel.types.Node, sourceCode: string): JSXEle
         return {
             type: tagName,
             props,
+            propOrder,
             children,
         };
     }
 
     // JSX выражение (например, {condition && <div>...</div>})
     if (babel.types.isJSXExpressionContainer(node)) {
-        return {
-            type: 'expression',
-            props: {
-                content: sourceCode.substring(node.expression.start as number, node.expression.end as number),
-            },
-            children: [],
-        };
+        return transformExpressionContainer(node.expression, sourceCode);
     }
 
     // JSX текст
@@ -96,6 +103,36 @@ export function transformJSX(node: babel.types.Node, sourceCode: string): JSXEle
     return null;
 }
 
+/**
+ * Преобразует выражение в фигурных скобках. Для условного рендеринга
+ * {condition && <div>...</div>} дополнительно сохраняет условие и разобранный JSX
+ */
+function transformExpressionContainer(
+    expression: babel.types.Expression | babel.types.JSXEmptyExpression,
+    sourceCode: string
+): JSXElementInfo {
+    const getCode = (node: babel.types.Node) => sourceCode.substring(node.start as number, node.end as number);
+
+    const info: JSXElementInfo = {
+        type: 'expression',
+        props: {
+            content: getCode(expression),
+        },
+        children: [],
+    };
+
+    if (babel.types.isLogicalExpression(expression) && expression.operator === '&&' &&
+        (babel.types.isJSXElement(expression.right) || babel.types.isJSXFragment(expression.right))) {
+        const child = transformJSX(expression.right, sourceCode);
+        if (child) {
+            info.props.condition = getCode(expression.left);
+            info.children.push(child);
+        }
+    }
+
+    return info;
+}
+
 /**
  * Получает имя JSX элемента
  */
@@ -114,17 +151,20 @@ function getJSXElementName(
 }
 
 /**
- * Извлекает атрибуты JSX элемента
+ * Извлекает атрибуты JSX элемента. Порядок атрибутов возвращается отдельным списком:
+ * генераторы выводят атрибуты в порядке исходного кода
  */
 function extractJSXAttributes(
     attributes: Array<babel.types.JSXAttribute | babel.types.JSXSpreadAttribute>,
     sourceCode: string
-): Record<string, any> {
+): { props: Record<string, any>; propOrder: string[] } {
     const props: Record<string, any> = {};
+    const propOrder: string[] = [];
 
     attributes.forEach(attr => {
         if (babel.types.isJSXAttribute(attr)) {
             const name = (attr.name.name as string);
+            propOrder.push(name);
 
             // Атрибут без значения (например, disabled)
             if (attr.value === null) {
@@ -142,6 +182,18 @@ function extractJSXAttributes(
             if (babel.types.isJSXExpressionContainer(attr.value)) {
                 if (babel.types.isJSXEmptyExpression(attr.value.expression)) {
                     props[name] = null;
+                } else if (babel.types.isJSXElement(attr.value.expression) || babel.types.isJSXFragment(attr.value.expression)) {
+                    // Разметка в пропсе (header={<h1>...</h1>}) передается деревом элементов
+                    props[name] = transformJSX(attr.value.expression, sourceCode);
+                } else if (babel.types.isArrowFunctionExpression(attr.value.expression) &&
+                    (babel.types.isJSXElement(attr.value.expression.body) || babel.types.isJSXFragment(attr.value.expression.body))) {
+                    // Render prop (renderItem={item => <li>{item}</li>}): параметры и разметка
+                    props[name] = {
+                        type: 'renderProp',
+                        params: attr.value.expression.params.map(param =>
+                            babel.types.isIdentifier(param) ? param.name : sourceCode.substring(param.start as number, param.end as number)),
+                        jsx: transformJSX(attr.value.expression.body, sourceCode),
+                    };
                 } else {
                     props[name] = {
                         type: 'expression',
@@ -161,6 +213,7 @@ function extractJSXAttributes(
             }
         } else if (babel.types.isJSXSpreadAttribute(attr)) {
             // Spread атрибуты ({...props})
+            propOrder.push(`__spread__${attr.argument.start}`);
             props[`__spread__${attr.argument.start}`] = {
                 type: 'spread',
                 code: sourceCode.substring(attr.argument.start as number, attr.argument.end as number),
@@ -168,7 +221,7 @@ function extractJSXAttributes(
         }
     });
 
-    return props;
+    return { props, propOrder };
 }
 
 /**
@@ -202,16 +255,7 @@ function extractJSXChildren(
             }
         } else if (babel.types.isJSXExpressionContainer(child)) {
             if (!babel.types.isJSXEmptyExpression(child.expression)) {
-                result.push({
-                    type: 'expression',
-                    props: {
-                        content: sourceCode.substring(
-                            child.expression.start as number,
-                            child.expression.end as number
-                        ),
-                    },
-                    children: [],
-                });
+                result.push(transformExpressionContainer(child.expression, sourceCode));
             }
         } else if (babel.types.isJSXSpreadChild && babel.types.isJSXSpreadChild(child)) {
             result.push({
@@ -0,0 +1,585 @@
+import * as babel from '@babel/core';
+
+// Методы жизненного цикла, переводимые в useEffect
+const LIFECYCLE_METHODS = ['componentDidMount', 'componentDidUpdate', 'componentWillUnmount'];
+
+// Методы класса, которые не становятся колбэками функционального компонента
+const SKIPPED_METHODS = ['constructor', 'render', 'shouldComponentUpdate', 'getSnapshotBeforeUpdate',
+    'componentDidCatch', ...LIFECYCLE_METHODS];
+
+// Замена фрагмента исходного кода
+interface Replacement {
+    start: number;
+    end: number;
+    text: string;
+}
+
+// Сведения о классовом компоненте, нужные для переписывания обращений к this
+interface ClassInfo {
+    sourceCode: string;
+    state: string[];          // Поля состояния: из this.state и из вызовов setState
+    props: string[];          // Пропсы, читаемые из this.props (когда тип пропсов не указан)
+    typedProps: boolean;      // Тип пропсов указан: React.Component<Props>
+    methods: string[];        // Методы и стрелочные поля класса
+    createdRefs: string[];    // Поля, созданные React.createRef()
+    fields: string[];         // Прочие поля экземпляра (this.timer) -> useRef
+    stateAliases: string[];   // Параметры состояния в setState(prev => ...)
+    propsAliases: string[];   // Параметры пропсов в setState((prev, props) => ...)
+}
+
+/**
+ * Проверяет, является ли класс React компонентом: class X extends React.Component
+ * (Component, PureComponent)
+ */
+export function isReactClassComponent(node: babel.types.Class): boolean {
+    const superClass = node.superClass;
+    let name = '';
+    if (babel.types.isMemberExpression(superClass) && babel.types.isIdentifier(superClass.property)) {
+        name = superClass.property.name;
+    } else if (babel.types.isIdentifier(superClass)) {
+        name = superClass.name;
+    }
+    return name === 'Component' || name === 'PureComponent';
+}
+
+/**
+ * Переписывает классовые компоненты в функциональные компоненты с хуками, чтобы их
+ * разобрал тот же код, что и функциональные: this.state -> useState, this.setState ->
+ * вызовы сеттеров, методы -> useCallback, this.timer -> useRef, componentDidMount и
+ * componentWillUnmount -> useEffect с функцией очистки, render -> тело функции.
+ * Если классовых компонентов нет, возвращает исходный код без изменений
+ */
+export function rewriteClassComponents(ast: babel.types.File, sourceCode: string): string {
+    const replacements: Replacement[] = [];
+
+    babel.traverse(ast, {
+        ClassDeclaration(path) {
+            if (path.node.id && isReactClassComponent(path.node)) {
+                replacements.push({
+                    start: path.node.start as number,
+                    end: path.node.end as number,
+                    text: classToFunction(path.node, ast, sourceCode),
+                });
+                path.skip();
+            }
+        },
+    });
+
+    if (replacements.length === 0) {
+        return sourceCode;
+    }
+    return applyReplacements(sourceCode, 0, sourceCode.length, replacements);
+}
+
+/**
+ * Строит код функционального компонента, эквивалентного классу
+ */
+function classToFunction(node: babel.types.ClassDeclaration, ast: babel.types.File, sourceCode: string): string {
+    const getCode = (n: babel.types.Node) => sourceCode.substring(n.start as number, n.end as number);
+    const name = (node.id as babel.types.Identifier).name;
+
+    // Типы пропсов и состояния: React.Component<Props, State>
+    const typeParams = node.superTypeParameters && babel.types.isTSTypeParameterInstantiation(node.superTypeParameters)
+        ? node.superTypeParameters.params
+        : [];
+    const propsType = typeParams.length > 0 ? getCode(typeParams[0]) : '';
+    const stateTypes = typeParams.length > 1 ? typeMembers(typeParams[1], ast, sourceCode) : {};
+
+    const info = collectClassInfo(node, propsType !== '', sourceCode);
+    const rewrite = (n: babel.types.Node) => rewriteNode(n, info);
+
+    // Начальное состояние: state = {...} или this.state = {...} в конструкторе
+    const initialState: Record<string, string> = {};
+    const fieldValues: Record<string, string> = {};
+    const methods: Record<string, string> = {};
+    const lifecycle: Record<string, babel.types.BlockStatement> = {};
+    let render: babel.types.BlockStatement | null = null;
+
+    for (const member of node.body.body) {
+        if (babel.types.isClassProperty(member) && !member.static && babel.types.isIdentifier(member.key)) {
+            const value = member.value;
+            if (member.key.name === 'state' && babel.types.isObjectExpression(value)) {
+                collectInitialState(value, initialState, rewrite);
+            } else if (value && (babel.types.isArrowFunctionExpression(value) || babel.types.isFunctionExpression(value))) {
+                methods[member.key.name] = rewrite(value);
+            } else if (value) {
+                fieldValues[member.key.name] = rewrite(value);
+            }
+            continue;
+        }
+
+        if (!babel.types.isClassMethod(member) || member.static || !babel.types.isIdentifier(member.key)) {
+            continue;
+        }
+        const methodName = member.key.name;
+
+        if (member.kind === 'constructor') {
+            for (const statement of member.body.body) {
+                if (babel.types.isExpressionStatement(statement) &&
+                    babel.types.isAssignmentExpression(statement.expression) &&
+                    isThisMember(statement.expression.left, 'state') &&
+                    babel.types.isObjectExpression(statement.expression.right)) {
+                    collectInitialState(statement.expression.right, initialState, rewrite);
+                }
+            }
+        } else if (methodName === 'render') {
+            render = member.body;
+        } else if (LIFECYCLE_METHODS.includes(methodName)) {
+            lifecycle[methodName] = member.body;
+        } else if (!SKIPPED_METHODS.includes(methodName) && member.kind === 'method') {
+            const params = member.params.map(param => getCode(param)).join(', ');
+            methods[methodName] = `${member.async ? 'async ' : ''}(${params}) => ${rewrite(member.body)}`;
+        }
+    }
+
+    const lines: string[] = [];
+    let params = '';
+    if (info.typedProps) {
+        params = `props: ${propsType}`;
+    } else if (info.props.length > 0) {
+        params = `{ ${info.props.join(', ')} }`;
+    }
+    lines.push(`function ${name}(${params}) {`);
+
+    // Состояние: поля this.state и поля, которые появляются только в setState
+    for (const field of info.state) {
+        const type = stateTypes[field] ? `<${stateTypes[field]}>` : '';
+        lines.push(`    const [${field}, ${setterName(field)}] = useState${type}(${initialState[field] ?? ''});`);
+    }
+
+    // Поля экземпляра: React.createRef() и значения между рендерами (this.timer)
+    for (const field of [...info.createdRefs, ...info.fields]) {
+        const value = info.fields.includes(field) ? fieldValues[field] : undefined;
+        lines.push(`    const ${field} = useRef(${value ?? 'null'});`);
+    }
+
+    for (const method of info.methods) {
+        if (methods[method]) {
+            lines.push(`    const ${method} = useCallback(${methods[method]}, []);`);
+        }
+    }
+
+    // componentDidMount + componentWillUnmount -> эффект с функцией очистки
+    const didMount = lifecycle.componentDidMount;
+    const willUnmount = lifecycle.componentWillUnmount;
+    if (didMount || willUnmount) {
+        lines.push('    useEffect(() => {');
+        if (didMount) {
+            lines.push(blockContent(rewrite(didMount)));
+        }
+        if (willUnmount) {
+            lines.push(`        return () => ${rewrite(willUnmount)};`);
+        }
+        lines.push('    }, []);');
+    }
+
+    // componentDidUpdate выполняется после каждого обновления
+    if (lifecycle.componentDidUpdate) {
+        lines.push(`    useEffect(() => ${rewrite(lifecycle.componentDidUpdate)});`);
+    }
+
+    if (render) {
+        lines.push(blockContent(rewrite(render)));
+    }
+    lines.push('}');
+
+    // static defaultProps = {...} -> Name.defaultProps = {...}
+    for (const member of node.body.body) {
+        if (babel.types.isClassProperty(member) && member.static && member.value &&
+            babel.types.isIdentifier(member.key, { name: 'defaultProps' })) {
+            lines.push(`${name}.defaultProps = ${getCode(member.value)};`);
+        }
+    }
+
+    return lines.join('\n');
+}
+
+/**
+ * Собирает поля состояния, пропсы, методы и поля экземпляра класса
+ */
+function collectClassInfo(node: babel.types.ClassDeclaration, typedProps: boolean, sourceCode: string): ClassInfo {
+    const info: ClassInfo = {
+        sourceCode,
+        state: [],
+        props: [],
+        typedProps,
+        methods: [],
+        createdRefs: [],
+        fields: [],
+        stateAliases: [],
+        propsAliases: [],
+    };
+    const add = (list: string[], name: string) => {
+        if (!list.includes(name)) {
+            list.push(name);
+        }
+    };
+    const addKeys = (object: babel.types.ObjectExpression | babel.types.ObjectPattern, list: string[]) => {
+        for (const property of object.properties) {
+            if ((babel.types.isObjectProperty(property) || babel.types.isObjectMethod(property)) &&
+                babel.types.isIdentifier(property.key)) {
+                add(list, property.key.name);
+            }
+        }
+    };
+
+    for (const member of node.body.body) {
+        if (babel.types.isClassMethod(member)) {
+            if (!member.static && member.kind === 'method' && babel.types.isIdentifier(member.key) &&
+                !SKIPPED_METHODS.includes(member.key.name)) {
+                add(info.methods, member.key.name);
+            }
+            continue;
+        }
+        if (!babel.types.isClassProperty(member) || member.static || !babel.types.isIdentifier(member.key)) {
+            continue;
+        }
+        const memberName = member.key.name;
+        const value = member.value;
+        if (memberName === 'state') {
+            if (babel.types.isObjectExpression(value)) {
+                addKeys(value, info.state);
+            }
+        } else if (value && (babel.types.isArrowFunctionExpression(value) || babel.types.isFunctionExpression(value))) {
+            add(info.methods, memberName);
+        } else if (value && babel.types.isCallExpression(value) && isCreateRef(value.callee)) {
+            add(info.createdRefs, memberName);
+        } else {
+            add(info.fields, memberName);
+        }
+    }
+
+    babel.traverse(node.body, {
+        AssignmentExpression(path) {
+            // this.state = {...} в конструкторе
+            if (isThisMember(path.node.left, 'state') && babel.types.isObjectExpression(path.node.right)) {
+                addKeys(path.node.right, info.state);
+            }
+        },
+        CallExpression(path) {
+            // Ключи setState({...}) и setState(prev => ({...})) - тоже поля состояния
+            if (!isThisMember(path.node.callee, 'setState') || path.node.arguments.length === 0) {
+                return;
+            }
+            const update = updaterObject(path.node.arguments[0]);
+            if (update) {
+                addKeys(update, info.state);
+            }
+        },
+        MemberExpression(path) {
+            const object = path.node.object;
+            // this.props.title и const { title } = this.props
+            if (isThisMember(object, 'props') && babel.types.isIdentifier(path.node.property) && !path.node.computed) {
+                add(info.props, path.node.property.name);
+                return;
+            }
+            if (isThisMember(path.node, 'props') && babel.types.isVariableDeclarator(path.parent) &&
+                babel.types.isObjectPattern(path.parent.id)) {
+                addKeys(path.parent.id, info.props);
+                return;
+            }
+
+            // this.timer = setInterval(...) - поле экземпляра
+            if (babel.types.isThisExpression(object) && babel.types.isIdentifier(path.node.property) && !path.node.computed) {
+                const field = path.node.property.name;
+                if (!['state', 'props', 'setState', 'context', 'forceUpdate'].includes(field) &&
+                    !info.methods.includes(field) && !info.createdRefs.includes(field) && !SKIPPED_METHODS.includes(field)) {
+                    add(info.fields, field);
+                }
+            }
+        },
+    }, { scopeToSkip: null } as any);
+
+    return info;
+}
+
+/**
+ * Переписывает код узла класса для функционального компонента
+ */
+function rewriteNode(node: babel.types.Node, info: ClassInfo): string {
+    const own = replacementFor(node, info);
+    if (own !== null) {
+        return own;
+    }
+
+    const replacements: Replacement[] = [];
+    babel.traverse(node, {
+        enter(path) {
+            const text = replacementFor(path.node, info);
+            if (text !== null) {
+                replacements.push({ start: path.node.start as number, end: path.node.end as number, text });
+                path.skip();
+            }
+        },
+    }, { scopeToSkip: null } as any);
+
+    return applyReplacements(info.sourceCode, node.start as number, node.end as number, replacements);
+}
+
+/**
+ * Возвращает код, заменяющий узел, или null, если узел переписывать не нужно
+ */
+function replacementFor(node: babel.types.Node, info: ClassInfo): string | null {
+    const rewrite = (n: babel.types.Node) => rewriteNode(n, info);
+
+    // const { count } = this.state; -> объявление не нужно: count - переменная состояния
+    if (babel.types.isVariableDeclaration(node) &&
+        node.declarations.some(declarator => isDestructuredInstance(declarator, info))) {
+        const declarations: string[] = [];
+        for (const declarator of node.declarations) {
+            if (!isDestructuredInstance(declarator, info)) {
+                declarations.push(rewrite(declarator));
+                continue;
+            }
+            for (const property of (declarator.id as babel.types.ObjectPattern).properties) {
+                // Переименование { count: total } -> total = count
+                if (babel.types.isObjectProperty(property) && babel.types.isIdentifier(property.key) &&
+                    babel.types.isIdentifier(property.value) && property.value.name !== property.key.name) {
+                    declarations.push(`${property.value.name} = ${property.key.name}`);
+                }
+            }
+        }
+        return declarations.length > 0 ? `${node.kind} ${declarations.join(', ')};` : '';
+    }
+
+    // this.setState({ count: 1 }); -> setCount(1);
+    if (babel.types.isExpressionStatement(node) && isSetStateCall(node.expression)) {
+        const calls = setterCalls(node.expression as babel.types.CallExpression, info);
+        return calls.map(call => `${call};`).join(' ');
+    }
+    if (isSetStateCall(node)) {
+        const calls = setterCalls(node as babel.types.CallExpression, info);
+        return calls.length === 1 ? calls[0] : `(${calls.join(', ')})`;
+    }
+
+    // this.handleClick.bind(this) -> handleClick
+    if (babel.types.isCallExpression(node) && babel.types.isMemberExpression(node.callee) &&
+        babel.types.isIdentifier(node.callee.property, { name: 'bind' }) &&
+        node.arguments.length === 1 && babel.types.isThisExpression(node.arguments[0]) &&
+        babel.types.isMemberExpression(node.callee.object) && babel.types.isThisExpression(node.callee.object.object)) {
+        return rewrite(node.callee.object);
+    }
+
+    if (!babel.types.isMemberExpression(node) || node.computed || !babel.types.isIdentifier(node.property)) {
+        return null;
+    }
+    const property = node.property.name;
+
+    // prev.count в setState(prev => ...) -> count
+    if (babel.types.isIdentifier(node.object) && info.stateAliases.includes(node.object.name)) {
+        return property;
+    }
+    if (babel.types.isIdentifier(node.object) && info.propsAliases.includes(node.object.name)) {
+        return info.typedProps ? `props.${property}` : property;
+    }
+
+    // this.state.count -> count, this.props.title -> props.title (title без типа пропсов)
+    if (isThisMember(node.object, 'state')) {
+        return property;
+    }
+    if (isThisMember(node.object, 'props')) {
+        return info.typedProps ? `props.${property}` : property;
+    }
+
+    if (!babel.types.isThisExpression(node.object)) {
+        return null;
+    }
+    if (property === 'state') {
+        return `{ ${info.state.join(', ')} }`;
+    }
+    if (property === 'props') {
+        return info.typedProps ? 'props' : `{ ${info.props.join(', ')} }`;
+    }
+    if (info.methods.includes(property) || info.createdRefs.includes(property)) {
+        return property;
+    }
+    if (info.fields.includes(property)) {
+        return `${property}.current`;
+    }
+    return null;
+}
+
+/**
+ * Формирует вызовы сеттеров для this.setState(update, callback)
+ */
+function setterCalls(call: babel.types.CallExpression, info: ClassInfo): string[] {
+    const [update, callback] = call.arguments;
+    const calls: string[] = [];
+
+    // Функция обновления: setState((prev, props) => ({ ... }))
+    const aliases: { state?: string; props?: string } = {};
+    if (update && (babel.types.isArrowFunctionExpression(update) || babel.types.isFunctionExpression(update))) {
+        const [stateParam, propsParam] = update.params;
+        if (babel.types.isIdentifier(stateParam)) {
+            aliases.state = stateParam.name;
+            info.stateAliases.push(stateParam.name);
+        }
+        if (babel.types.isIdentifier(propsParam)) {
+            aliases.props = propsParam.name;
+            info.propsAliases.push(propsParam.name);
+        }
+    }
+
+    const object = update ? updaterObject(update) : null;
+    if (object) {
+        for (const property of object.properties) {
+            if (babel.types.isObjectProperty(property) && babel.types.isIdentifier(property.key)) {
+                calls.push(`${setterName(property.key.name)}(${rewriteNode(property.value, info)})`);
+            }
+        }
+    }
+
+    if (aliases.state) {
+        info.stateAliases.pop();
+    }
+    if (aliases.props) {
+        info.propsAliases.pop();
+    }
+
+    // Колбэк после обновления выполняется сразу за сеттерами
+    if (callback) {
+        calls.push(`(${rewriteNode(callback, info)})()`);
+    }
+    return calls;
+}
+
+/**
+ * Возвращает объект обновления состояния из аргумента setState: сам объект или объект,
+ * который возвращает функция обновления
+ */
+function updaterObject(update: babel.types.Node): babel.types.ObjectExpression | null {
+    if (babel.types.isObjectExpression(update)) {
+        return update;
+    }
+    if (!babel.types.isArrowFunctionExpression(update) && !babel.types.isFunctionExpression(update)) {
+        return null;
+    }
+    if (babel.types.isObjectExpression(update.body)) {
+        return update.body;
+    }
+    if (babel.types.isBlockStatement(update.body)) {
+        for (const statement of update.body.body) {
+            if (babel.types.isReturnStatement(statement) && statement.argument &&
+                babel.types.isObjectExpression(statement.argument)) {
+                return statement.argument;
+            }
+        }
+    }
+    return null;
+}
+
+/**
+ * Добавляет поля начального состояния с их кодом
+ */
+function collectInitialState(
+    object: babel.types.ObjectExpression,
+    initialState: Record<string, string>,
+    rewrite: (n: babel.types.Node) => string
+) {
+    for (const property of object.properties) {
+        if (babel.types.isObjectProperty(property) && babel.types.isIdentifier(property.key)) {
+            initialState[property.key.name] = rewrite(property.value);
+        }
+    }
+}
+
+/**
+ * Возвращает код типов полей интерфейса или псевдонима типа (State) по имени или литералу типа
+ */
+function typeMembers(typeNode: babel.types.TSType, ast: babel.types.File, sourceCode: string): Record<string, string> {
+    const getCode = (n: babel.types.Node) => sourceCode.substring(n.start as number, n.end as number);
+    let members: babel.types.TSTypeElement[] = [];
+
+    if (babel.types.isTSTypeLiteral(typeNode)) {
+        members = typeNode.members;
+    } else if (babel.types.isTSTypeReference(typeNode) && babel.types.isIdentifier(typeNode.typeName)) {
+        const typeName = typeNode.typeName.name;
+        for (const statement of ast.program.body) {
+            const declaration = babel.types.isExportNamedDeclaration(statement) ? statement.declaration : statement;
+            if (babel.types.isTSInterfaceDeclaration(declaration) && declaration.id.name === typeName) {
+                members = declaration.body.body;
+            } else if (babel.types.isTSTypeAliasDeclaration(declaration) && declaration.id.name === typeName &&
+                babel.types.isTSTypeLiteral(declaration.typeAnnotation)) {
+                members = declaration.typeAnnotation.members;
+            }
+        }
+    }
+
+    const types: Record<string, string> = {};
+    for (const member of members) {
+        if (babel.types.isTSPropertySignature(member) && babel.types.isIdentifier(member.key) &&
+            member.typeAnnotation) {
+            types[member.key.name] = getCode(member.typeAnnotation.typeAnnotation);
+        }
+    }
+    return types;
+}
+
+/**
+ * Проверяет, деструктурирует ли объявление this.state (или this.props без типа пропсов):
+ * такие переменные уже объявлены хуками или параметром функции
+ */
+function isDestructuredInstance(declarator: babel.types.VariableDeclarator, info: ClassInfo): boolean {
+    if (!declarator.init || !babel.types.isObjectPattern(declarator.id)) {
+        return false;
+    }
+    return isThisMember(declarator.init, 'state') || (!info.typedProps && isThisMember(declarator.init, 'props'));
+}
+
+/**
+ * Проверяет, является ли узел вызовом this.setState(...)
+ */
+function isSetStateCall(node: babel.types.Node): boolean {
+    return babel.types.isCallExpression(node) && isThisMember(node.callee, 'setState');
+}
+
+/**
+ * Проверяет, является ли узел обращением к полю this: this.state, this.props
+ */
+function isThisMember(node: babel.types.Node, name: string): boolean {
+    return babel.types.isMemberExpression(node) && !node.computed &&
+        babel.types.isThisExpression(node.object) &&
+        babel.types.isIdentifier(node.property, { name });
+}
+
+/**
+ * Проверяет, является ли вызываемая функция React.createRef / createRef
+ */
+function isCreateRef(callee: babel.types.Node): boolean {
+    if (babel.types.isIdentifier(callee, { name: 'createRef' })) {
+        return true;
+    }
+    return babel.types.isMemberExpression(callee) && babel.types.isIdentifier(callee.property, { name: 'createRef' });
+}
+
+/**
+ * Возвращает имя сеттера поля состояния: count -> setCount
+ */
+function setterName(field: string): string {
+    return 'set' + field.charAt(0).toUpperCase() + field.slice(1);
+}
+
+/**
+ * Возвращает содержимое блока { ... } без фигурных скобок
+ */
+function blockContent(block: string): string {
+    const trimmed = block.trim();
+    if (trimmed.startsWith('{') && trimmed.endsWith('}')) {
+        return trimmed.slice(1, -1);
+    }
+    return trimmed;
+}
+
+/**
+ * Применяет замены к фрагменту исходного кода [start, end)
+ */
+function applyReplacements(sourceCode: string, start: number, end: number, replacements: Replacement[]): string {
+    const sorted = [...replacements].sort((a, b) => a.start - b.start);
+    let result = '';
+    let position = start;
+    for (const replacement of sorted) {
+        result += sourceCode.substring(position, replacement.start) + replacement.text;
+        position = replacement.end;
+    }
+    return result + sourceCode.substring(position, end);
+}
@@ -1,7 +1,10 @@
 import * as babel from '@babel/core';
+import * as fs from 'fs';
+import * as nodePath from 'path';
 import * as babelPresetReact from '@babel/preset-react';
 import * as babelPresetTypeScript from '@babel/preset-typescript';
 import { transformJSX } from './ast-converter';
+import { rewriteClassComponents } from './class-converter';
 
 // Интерфейс для пропсов компонента
 interface PropDefinition {
@@ -9,6 +12,7 @@ interface PropDefinition {
     type: string;
     required: boolean;
     defaultValue?: any;
+    rest?: boolean;
 }
 
 // Интерфейс для состояний компонента
@@ -23,6 +27,51 @@ interface StateDefinition {
 interface EffectDefinition {
     body: string;
     dependencies: string[];
+    kind?: string;      // 'interval' | 'timeout' | 'subscription' | 'generic'
+    delay?: number;     // Задержка таймера в миллисекундах
+    callback?: string;  // Код, переданный в setInterval/setTimeout
+    source?: string;    // URL подписки (EventSource/WebSocket)
+    cleanup?: string;   // Тело функции очистки
+}
+
+// Интерфейс для поля состояния редьюсера
+interface ReducerFieldDefinition {
+    name: string;
+    type: string;
+    initialValue?: any;
+}
+
+// Интерфейс для действия редьюсера (ветка switch по action.type)
+interface ActionDefinition {
+    type: string;
+    body: string;
+}
+
+// Интерфейс для редьюсеров компонента (useReducer)
+interface ReducerDefinition {
+    name: string;         // Переменная состояния
+    dispatch: string;     // Функция dispatch
+    reducer: string;      // Имя функции-редьюсера
+    stateParam: string;   // Имя параметра состояния в редьюсере
+    actionParam: string;  // Имя параметра действия в редьюсере
+    fields: ReducerFieldDefinition[];
+    actions: ActionDefinition[];
+}
+
+// Интерфейс для контекстов (createContext / useContext)
+interface ContextDefinition {
+    name: string;         // Имя контекста (ThemeContext)
+    variable?: string;    // Переменная, получающая значение useContext
+    type?: string;
+    defaultValue?: any;
+    created?: boolean;    // createContext вызывается в этом модуле
+}
+
+// Интерфейс для пользовательских хуков (useCart, useToggle, ...)
+interface HookDefinition {
+    name: string;
+    source?: string;      // Модуль, из которого импортирован хук
+    resolved: boolean;    // Удалось ли найти и встроить тело хука
 }
 
 // Интерфейс для колбэк-функций компонента
@@ -38,6 +87,14 @@ interface RefDefinition {
     initialValue?: any;
 }
 
+// Интерфейс для стилизованных компонентов (styled-components, @emotion/styled)
+interface StyledDefinition {
+    name: string;           // Имя компонента (Title)
+    tag: string;            // HTML тег (h1)
+    quasis: string[];       // Текст шаблона стилей между подстановками
+    expressions: string[];  // Код подстановок ${...}
+}
+
 // Интерфейс для импортов
 interface ImportDefinition {
     source: string;
@@ -53,28 +110,66 @@ interface ReactComponent {
     effects: EffectDefinition[];
     callbacks: CallbackDefinition[];
     refs: RefDefinition[];
+    reducers: ReducerDefinition[];
+    contexts: ContextDefinition[];
+    hooks: HookDefinition[];
+    styled?: StyledDefinition[];
+    wrappers?: string[];    // Обертки компонента снаружи внутрь: memo(forwardRef(...)) -> ['memo', 'forwardRef']
     jsx: any;
     imports?: ImportDefinition[];
     exports?: { [key: string]: any };
 }
 
+// Встроенные хуки React, которые обрабатываются отдельно
+const BUILTIN_HOOKS = ['useState', 'useEffect', 'useCallback', 'useRef', 'useReducer', 'useContext',
+    'useMemo', 'useLayoutEffect', 'useId', 'useTransition', 'useDeferredValue', 'useImperativeHandle'];
+
+// Модули, экспортирующие styled для стилизованных компонентов
+const STYLED_MODULES = ['styled-components', '@emotion/styled'];
+
+// Обертки компонентов, которые снимаются при поиске компонента: React.memo, forwardRef,
+// observer (MobX) и распространенные HOC. Дополнительные HOC задаются переменной
+// окружения PARSER_HOCS через запятую или параметром hocs запроса
+const COMPONENT_WRAPPERS = ['memo', 'forwardRef', 'observer', 'withRouter', 'connect'];
+
+// Максимальная глубина встраивания хуков, вызывающих другие хуки
+const MAX_HOOK_DEPTH = 5;
+
+/**
+ * Парсит код с помощью Babel и возвращает AST
+ */
+export function parseToAST(code: string): babel.types.File {
+    // Код только разбирается без трансформации: JSX и аннотации типов остаются в AST
+    const ast = babel.parseSync(code, {
+        presets: [
+            babelPresetReact,
+            [babelPresetTypeScript, { isTSX: true, allExtensions: true }]
+        ],
+        babelrc: false,
+        configFile: false,
+    });
+
+    if (!ast) {
+        throw new Error('Не удалось распарсить код с помощью Babel');
+    }
+
+    return ast;
+}
+
 /**
- * Парсит React компонент и возвращает его структуру
+ * Парсит React компонент и возвращает его структуру.
+ * filePath нужен для разрешения хуков, импортированных из локальных модулей,
+ * hocs - дополнительные HOC, которые снимаются с компонента (withAuth(Page))
  */
-export function parseReactComponent(code: string): ReactComponent {
+export function parseReactComponent(code: string, filePath?: string, hocs: string[] = []): ReactComponent {
     try {
         // Шаг 1: Используем Babel для парсинга JSX/TSX в AST
-        const babelResult = babel.transformSync(code, {
-            presets: [
-                babelPresetReact,
-                [babelPresetTypeScript, { isTSX: true, allExtensions: true }]
-            ],
-            ast: true,
-            code: false,
-        });
+        const ast = parseToAST(code);
 
-        if (!babelResult || !babelResult.ast) {
-            throw new Error('Не удалось распарсить код с помощью Babel');
+        // Классовые компоненты переписываются в функциональные с хуками и разбираются заново
+        const rewritten = rewriteClassComponents(ast, code);
+        if (rewritten !== code) {
+            return parseReactComponent(rewritten, filePath, hocs);
         }
 
         // Шаг 2: Извлекаем информацию из AST
@@ -85,6 +180,9 @@ export function parseReactComponent(code: string): ReactComponent {
             effects: [],
             callbacks: [],
             refs: [],
+            reducers: [],
+            contexts: [],
+            hooks: [],
             jsx: null,
             imports: [],
         };
@@ -92,8 +190,17 @@ export function parseReactComponent(code: string): ReactComponent {
         // Сохраняем исходный код для извлечения фрагментов кода
         const sourceCode = code;
 
+        // HOC из переменной окружения и из запроса
+        const componentHOCs = [...configuredHOCs(), ...hocs];
+
+        // Обертки компонентов, объявленных отдельно: export default memo(Button)
+        const wrappedComponents: { [name: string]: string[] } = {};
+
+        // Значения по умолчанию из Card.defaultProps по именам компонентов
+        const defaultProps: { [name: string]: babel.types.ObjectExpression } = {};
+
         // Выполняем обход AST с помощью посетителей
-        babel.traverse(babelResult.ast, {
+        babel.traverse(ast, {
             // Обработка импортов
             ImportDeclaration(path) {
                 const importNode = path.node;
@@ -141,11 +248,40 @@ export function parseReactComponent(code: string): ReactComponent {
 
             // Поиск стрелочных функций компонентов
             VariableDeclarator(path) {
+                // Стилизованные компоненты: const Title = styled.h1`...`
+                if (babel.types.isTaggedTemplateExpression(path.node.init)) {
+                    extractStyledDefinition(path, componentInfo, sourceCode);
+                    return;
+                }
+
+                // Компоненты в обертках: const Button = memo((props) => ...), forwardRef, HOC
+                if (babel.types.isCallExpression(path.node.init) && babel.types.isIdentifier(path.node.id)) {
+                    const { inner, wrappers } = unwrapComponent(path.get('init') as babel.NodePath, componentHOCs);
+                    if (wrappers.length === 0) {
+                        return;
+                    }
+                    if (babel.types.isIdentifier(inner.node)) {
+                        // const Enhanced = withRouter(Page)
+                        wrappedComponents[inner.node.name] = wrappers;
+                    } else if (isReactComponent(inner.node)) {
+                        componentInfo.name = path.node.id.name;
+                        componentInfo.wrappers = wrappers;
+                        extractPropsFromFunction(inner, componentInfo, sourceCode);
+                        extractJSXFromFunction(inner, componentInfo, sourceCode);
+                    }
+                    return;
+                }
+
                 if (path.node.init &&
                     (babel.types.isArrowFunctionExpression(path.node.init) ||
                         babel.types.isFunctionExpression(path.node.init)) &&
                     babel.types.isIdentifier(path.node.id)) {
 
+                    // Пользовательские хуки и функции внутри них не являются компонентами
+                    if (isCustomHookName(path.node.id.name) || isInsideCustomHook(path)) {
+                        return;
+                    }
+
                     componentInfo.name = path.node.id.name;
 
                     if (isReactComponent(path.node.init)) {
@@ -156,31 +292,25 @@ export function parseReactComponent(code: string): ReactComponent {
                 }
             },
 
-            // Поиск вызовов хуков (useState, useEffect, useRef, useCallback)
-            CallExpression(path) {
-                if (babel.types.isIdentifier(path.node.callee)) {
-                    const calleeName = path.node.callee.name;
-
-                    // Обработка useState
-                    if (calleeName === 'useState') {
-                        extractUseState(path, componentInfo, sourceCode);
-                    }
-
-                    // Обработка useEffect
-                    else if (calleeName === 'useEffect') {
-                        extractUseEffect(path, componentInfo, sourceCode);
-                    }
-
-                    // Обработка useCallback
-                    else if (calleeName === 'useCallback') {
-                        extractUseCallback(path, componentInfo, sourceCode);
-                    }
+            // Значения пропсов по умолчанию: Card.defaultProps = { size: 'md' }
+            AssignmentExpression(path) {
+                const left = path.node.left;
+                if (babel.types.isMemberExpression(left) &&
+                    babel.types.isIdentifier(left.object) &&
+                    babel.types.isIdentifier(left.property, { name: 'defaultProps' }) &&
+                    babel.types.isObjectExpression(path.node.right)) {
+                    defaultProps[left.object.name] = path.node.right;
+                }
+            },
 
-                    // Обработка useRef
-                    else if (calleeName === 'useRef') {
-                        extractUseRef(path, componentInfo, sourceCode);
-                    }
+            // Поиск вызовов хуков (useState, useEffect, useRef, useCallback, useReducer, useContext)
+            CallExpression(path) {
+                // Хуки внутри пользовательских хуков встраиваются в месте вызова хука
+                if (isInsideCustomHook(path)) {
+                    return;
                 }
+
+                extractHookCall(path, componentInfo, sourceCode, filePath, 0);
             },
 
             // Обработка экспортов
@@ -222,14 +352,41 @@ export function parseReactComponent(code: string): ReactComponent {
                     componentInfo.exports = {};
                 }
 
-                if (babel.types.isIdentifier(path.node.declaration)) {
-                    componentInfo.exports.default = path.node.declaration.name;
+                // export default memo(Button), export default observer(function Page() {...})
+                const { inner, wrappers } = unwrapComponent(path.get('declaration') as babel.NodePath, componentHOCs);
+
+                if (babel.types.isIdentifier(inner.node)) {
+                    componentInfo.exports.default = inner.node.name;
+                    if (wrappers.length > 0) {
+                        wrappedComponents[inner.node.name] = wrappers;
+                    }
                 } else {
                     componentInfo.exports.default = true;
                 }
+
+                if (wrappers.length > 0 && !babel.types.isIdentifier(inner.node) && isReactComponent(inner.node)) {
+                    if (babel.types.isFunctionExpression(inner.node) && inner.node.id) {
+                        componentInfo.name = inner.node.id.name;
+                        componentInfo.exports.default = inner.node.id.name;
+                    } else if (!componentInfo.name && filePath) {
+                        componentInfo.name = nodePath.basename(filePath, nodePath.extname(filePath));
+                    }
+                    componentInfo.wrappers = wrappers;
+                    extractPropsFromFunction(inner, componentInfo, sourceCode);
+                    extractJSXFromFunction(inner, componentInfo, sourceCode);
+                }
             }
         });
 
+        // Card.defaultProps = { size: 'md' }
+        applyDefaultProps(componentInfo, defaultProps[componentInfo.name]);
+
+        // Обертки, примененные к компоненту отдельно, идут снаружи его собственных
+        const outerWrappers = wrappedComponents[componentInfo.name];
+        if (outerWrappers) {
+            componentInfo.wrappers = [...outerWrappers, ...(componentInfo.wrappers || [])];
+        }
+
         // Логирование результата для отладки
         console.log("Результат парсинга:", JSON.stringify(componentInfo, null, 2));
 
@@ -241,6 +398,56 @@ export function parseReactComponent(code: string): ReactComponent {
     }
 }
 
+/**
+ * Возвращает имена дополнительных HOC из переменной окружения PARSER_HOCS (через запятую)
+ */
+function configuredHOCs(): string[] {
+    return (process.env.PARSER_HOCS || '')
+        .split(',')
+        .map(name => name.trim())
+        .filter(name => name !== '');
+}
+
+/**
+ * Снимает с выражения обертки компонента: memo(...), React.forwardRef(...), observer(...),
+ * HOC (withRouter(Page), connect(mapState)(Page)). Возвращает путь к обернутому выражению
+ * и имена оберток снаружи внутрь
+ */
+function unwrapComponent(path: babel.NodePath, hocs: string[]): { inner: babel.NodePath; wrappers: string[] } {
+    const wrappers: string[] = [];
+    let current = path;
+
+    while (babel.types.isCallExpression(current.node) && current.node.arguments.length > 0) {
+        const wrapper = wrapperName(current.node.callee, hocs);
+        if (!wrapper) {
+            break;
+        }
+        wrappers.push(wrapper);
+        current = current.get('arguments.0') as babel.NodePath;
+    }
+
+    return { inner: current, wrappers };
+}
+
+/**
+ * Возвращает имя обертки по вызываемому выражению или пустую строку, если это не обертка.
+ * HOC с параметрами (connect(mapState)(Page)) определяется по внутреннему вызову
+ */
+function wrapperName(callee: babel.types.Node, hocs: string[]): string {
+    if (babel.types.isCallExpression(callee)) {
+        return wrapperName(callee.callee, hocs);
+    }
+
+    let name = '';
+    if (babel.types.isIdentifier(callee)) {
+        name = callee.name;
+    } else if (babel.types.isMemberExpression(callee) && babel.types.isIdentifier(callee.property)) {
+        name = callee.property.name;
+    }
+
+    return COMPONENT_WRAPPERS.includes(name) || hocs.includes(name) ? name : '';
+}
+
 /**
  * Проверяет, является ли узел функцией React компонента
  */
@@ -292,7 +499,10 @@ function extractPropsFromFunction(path: babel.NodePath, componentInfo: ReactComp
     // Получаем параметры функции
     let params;
 
-    if (babel.types.isFunctionDeclaration(node)) {
+    if (babel.types.isFunctionDeclaration(node) ||
+        babel.types.isArrowFunctionExpression(node) ||
+        babel.types.isFunctionExpression(node)) {
+        // Функция, снятая с обертки: memo((props) => ...)
         params = node.params;
     } else if (babel.types.isVariableDeclarator(node) &&
         (babel.types.isArrowFunctionExpression(node.init) ||
@@ -314,11 +524,23 @@ function extractPropsFromFunction(path: babel.NodePath, componentInfo: ReactComp
         propsParam.properties.forEach(prop => {
             if (babel.types.isObjectProperty(prop) &&
                 babel.types.isIdentifier(prop.key)) {
-                componentInfo.props.push({
+                const definition: PropDefinition = {
                     name: prop.key.name,
                     required: true, // По умолчанию считаем обязательным
                     type: 'any',    // Тип неизвестен из деструктуризации
-                });
+                };
+
+                // Значение по умолчанию: { size = 'md' } - пропс необязательный, тип - по значению
+                if (babel.types.isAssignmentPattern(prop.value)) {
+                    definition.required = false;
+                    const defaultValue = getLiteralValue(prop.value.right);
+                    if (defaultValue !== undefined) {
+                        definition.defaultValue = defaultValue;
+                        definition.type = getTypeFromValue(defaultValue);
+                    }
+                }
+
+                componentInfo.props.push(definition);
             } else if (babel.types.isRestElement(prop) &&
                 babel.types.isIdentifier(prop.argument)) {
                 // Обработка rest-параметра {...restProps}
@@ -326,6 +548,7 @@ function extractPropsFromFunction(path: babel.NodePath, componentInfo: ReactComp
                     name: prop.argument.name,
                     required: false,
                     type: 'object',
+                    rest: true,
                 });
             }
         });
@@ -448,7 +671,9 @@ function extractPropsFromTypeAnnotation(propsParam: babel.types.Identifier, comp
 function extractJSXFromFunction(path: babel.NodePath, componentInfo: ReactComponent, sourceCode: string) {
     let node;
 
-    if (babel.types.isFunctionDeclaration(path.node)) {
+    if (babel.types.isFunctionDeclaration(path.node) ||
+        babel.types.isArrowFunctionExpression(path.node) ||
+        babel.types.isFunctionExpression(path.node)) {
         node = path.node;
     } else if (babel.types.isVariableDeclarator(path.node) &&
         (babel.types.isArrowFunctionExpression(path.node.init) ||
@@ -591,9 +816,89 @@ function extractUseEffect(path: babel.NodePath<babel.types.CallExpression>, comp
     componentInfo.effects.push({
         body: effectBody,
         dependencies,
+        ...analyzeEffectBody(effectFunc, sourceCode),
     });
 }
 
+/**
+ * Определяет вид эффекта: таймер (setInterval/setTimeout), подписка
+ * (EventSource/WebSocket) или обычный эффект, а также его функцию очистки
+ */
+function analyzeEffectBody(
+    effectFunc: babel.types.ArrowFunctionExpression | babel.types.FunctionExpression,
+    sourceCode: string
+): Partial<EffectDefinition> {
+    const info: Partial<EffectDefinition> = { kind: 'generic' };
+
+    if (!babel.types.isBlockStatement(effectFunc.body)) {
+        return info;
+    }
+
+    const getCode = (node: babel.types.Node) => sourceCode.substring(node.start as number, node.end as number);
+
+    babel.traverse(effectFunc.body, {
+        CallExpression(callPath) {
+            if (info.kind !== 'generic') return;
+
+            // Поддерживаем как setInterval(...), так и window.setInterval(...)
+            let calleeName = '';
+            const callee = callPath.node.callee;
+            if (babel.types.isIdentifier(callee)) {
+                calleeName = callee.name;
+            } else if (babel.types.isMemberExpression(callee) && babel.types.isIdentifier(callee.property)) {
+                calleeName = callee.property.name;
+            }
+
+            if (calleeName !== 'setInterval' && calleeName !== 'setTimeout') {
+                return;
+            }
+
+            info.kind = calleeName === 'setInterval' ? 'interval' : 'timeout';
+
+            const [callback, delay] = callPath.node.arguments;
+            if (callback) {
+                info.callback = getCode(callback);
+            }
+            if (delay && babel.types.isNumericLiteral(delay)) {
+                info.delay = delay.value;
+            }
+        },
+
+        NewExpression(newPath) {
+            const callee = newPath.node.callee;
+            if (!babel.types.isIdentifier(callee) ||
+                (callee.name !== 'EventSource' && callee.name !== 'WebSocket')) {
+                return;
+            }
+
+            info.kind = 'subscription';
+
+            const [source] = newPath.node.arguments;
+            if (source && babel.types.isStringLiteral(source)) {
+                info.source = source.value;
+            } else if (source) {
+                info.source = getCode(source);
+            }
+        }
+    }, { scopeToSkip: null } as any);
+
+    // Функция очистки - return верхнего уровня в теле эффекта
+    effectFunc.body.body.forEach(statement => {
+        if (!babel.types.isReturnStatement(statement) || !statement.argument) {
+            return;
+        }
+
+        const cleanup = statement.argument;
+        if (babel.types.isArrowFunctionExpression(cleanup) || babel.types.isFunctionExpression(cleanup)) {
+            info.cleanup = getCode(cleanup.body);
+        } else {
+            info.cleanup = getCode(cleanup);
+        }
+    });
+
+    return info;
+}
+
 /**
  * Извлекает вызов useCallback
  */
@@ -692,6 +997,758 @@ function extractUseRef(path: babel.NodePath<babel.types.CallExpression>, compone
     });
 }
 
+/**
+ * Извлекает вызов useReducer: функцию-редьюсер, поля начального состояния и типы действий
+ */
+function extractUseReducer(path: babel.NodePath<babel.types.CallExpression>, componentInfo: ReactComponent, sourceCode: string) {
+    const variableDeclarator = path.findParent(p => babel.types.isVariableDeclarator(p.node));
+
+    if (!variableDeclarator || !babel.types.isVariableDeclarator(variableDeclarator.node)) {
+        return;
+    }
+
+    const declaration = variableDeclarator.node;
+
+    // Ожидаем деструктуризацию [state, dispatch]
+    if (!babel.types.isArrayPattern(declaration.id) ||
+        declaration.id.elements.length !== 2 ||
+        !babel.types.isIdentifier(declaration.id.elements[0]) ||
+        !babel.types.isIdentifier(declaration.id.elements[1]) ||
+        path.node.arguments.length === 0) {
+        return;
+    }
+
+    const stateVar = declaration.id.elements[0] as babel.types.Identifier;
+    const dispatch = declaration.id.elements[1] as babel.types.Identifier;
+    const program = getProgram(path);
+
+    // Находим функцию-редьюсер: по имени в файле или прямо в аргументе
+    let reducerName = '';
+    let reducerFunc: babel.types.Node | null = null;
+    const reducerArg = path.node.arguments[0];
+
+    if (babel.types.isIdentifier(reducerArg)) {
+        reducerName = reducerArg.name;
+        reducerFunc = program ? findFunctionByName(program, reducerName) : null;
+    } else if (babel.types.isArrowFunctionExpression(reducerArg) || babel.types.isFunctionExpression(reducerArg)) {
+        reducerName = `${stateVar.name}Reducer`;
+        reducerFunc = reducerArg;
+    }
+
+    const reducerInfo: ReducerDefinition = {
+        name: stateVar.name,
+        dispatch: dispatch.name,
+        reducer: reducerName,
+        stateParam: 'state',
+        actionParam: 'action',
+        fields: [],
+        actions: [],
+    };
+
+    // Поля состояния из начального значения (литерал или константа в файле)
+    let initialState: babel.types.Node | null = path.node.arguments.length > 1 ? path.node.arguments[1] : null;
+    if (initialState && babel.types.isIdentifier(initialState) && program) {
+        initialState = findVariableInit(program, initialState.name);
+    }
+
+    if (initialState && babel.types.isObjectExpression(initialState)) {
+        initialState.properties.forEach(property => {
+            if (babel.types.isObjectProperty(property) && babel.types.isIdentifier(property.key)) {
+                reducerInfo.fields.push({
+                    name: property.key.name,
+                    ...getReducerFieldValue(property.value, sourceCode),
+                });
+            }
+        });
+    } else if (initialState) {
+        // Скалярное состояние: одно поле с именем переменной состояния
+        reducerInfo.fields.push({
+            name: stateVar.name,
+            ...getReducerFieldValue(initialState, sourceCode),
+        });
+    }
+
+    if (reducerFunc && (babel.types.isFunctionDeclaration(reducerFunc) ||
+        babel.types.isArrowFunctionExpression(reducerFunc) ||
+        babel.types.isFunctionExpression(reducerFunc))) {
+
+        const [stateParam, actionParam] = reducerFunc.params;
+        if (stateParam && babel.types.isIdentifier(stateParam)) {
+            reducerInfo.stateParam = stateParam.name;
+        }
+        if (actionParam && babel.types.isIdentifier(actionParam)) {
+            reducerInfo.actionParam = actionParam.name;
+        }
+
+        extractReducerActions(reducerFunc, reducerInfo, sourceCode);
+    }
+
+    componentInfo.reducers.push(reducerInfo);
+}
+
+/**
+ * Извлекает вызов createContext: имя контекста, тип и значение по умолчанию
+ */
+function extractCreateContext(path: babel.NodePath<babel.types.CallExpression>, componentInfo: ReactComponent, sourceCode: string) {
+    const variableDeclarator = path.findParent(p => babel.types.isVariableDeclarator(p.node));
+
+    if (!variableDeclarator || !babel.types.isVariableDeclarator(variableDeclarator.node) ||
+        !babel.types.isIdentifier(variableDeclarator.node.id)) {
+        return;
+    }
+
+    let defaultValue = undefined;
+    if (path.node.arguments.length > 0) {
+        defaultValue = getInitialStateValue(path.node.arguments[0], sourceCode);
+    }
+
+    // Тип из createContext<T> или из значения по умолчанию
+    let contextType = 'any';
+    if (path.node.typeParameters &&
+        babel.types.isTSTypeParameterInstantiation(path.node.typeParameters) &&
+        path.node.typeParameters.params.length > 0) {
+
+        contextType = getTypeFromTSAnnotation(path.node.typeParameters.params[0]);
+    } else if (defaultValue !== undefined) {
+        contextType = getTypeFromValue(defaultValue);
+    }
+
+    componentInfo.contexts.push({
+        name: variableDeclarator.node.id.name,
+        type: contextType,
+        defaultValue,
+        created: true,
+    });
+}
+
+/**
+ * Извлекает вызов useContext: const theme = useContext(ThemeContext)
+ */
+function extractUseContext(path: babel.NodePath<babel.types.CallExpression>, componentInfo: ReactComponent) {
+    const variableDeclarator = path.findParent(p => babel.types.isVariableDeclarator(p.node));
+
+    if (!variableDeclarator || !babel.types.isVariableDeclarator(variableDeclarator.node) ||
+        !babel.types.isIdentifier(variableDeclarator.node.id) ||
+        path.node.arguments.length === 0 ||
+        !babel.types.isIdentifier(path.node.arguments[0])) {
+        return;
+    }
+
+    componentInfo.contexts.push({
+        name: path.node.arguments[0].name,
+        variable: variableDeclarator.node.id.name,
+    });
+}
+
+/**
+ * Обрабатывает вызов хука: встроенные хуки React, createContext и пользовательские хуки
+ */
+function extractHookCall(
+    path: babel.NodePath<babel.types.CallExpression>,
+    componentInfo: ReactComponent,
+    sourceCode: string,
+    filePath: string | undefined,
+    depth: number
+) {
+    // createContext и useContext часто вызываются как React.createContext
+    const callee = path.node.callee;
+    if (babel.types.isMemberExpression(callee) &&
+        babel.types.isIdentifier(callee.object, { name: 'React' }) &&
+        babel.types.isIdentifier(callee.property)) {
+        if (callee.property.name === 'createContext') {
+            extractCreateContext(path, componentInfo, sourceCode);
+        } else if (callee.property.name === 'useContext') {
+            extractUseContext(path, componentInfo);
+        }
+    }
+
+    if (babel.types.isIdentifier(path.node.callee)) {
+        const calleeName = path.node.callee.name;
+
+        // Обработка useState
+        if (calleeName === 'useState') {
+            extractUseState(path, componentInfo, sourceCode);
+        }
+
+        // Обработка useEffect
+        else if (calleeName === 'useEffect') {
+            extractUseEffect(path, componentInfo, sourceCode);
+        }
+
+        // Обработка useCallback
+        else if (calleeName === 'useCallback') {
+            extractUseCallback(path, componentInfo, sourceCode);
+        }
+
+        // Обработка useRef
+        else if (calleeName === 'useRef') {
+            extractUseRef(path, componentInfo, sourceCode);
+        }
+
+        // Обработка useReducer
+        else if (calleeName === 'useReducer') {
+            extractUseReducer(path, componentInfo, sourceCode);
+        }
+
+        // Обработка createContext и useContext
+        else if (calleeName === 'createContext') {
+            extractCreateContext(path, componentInfo, sourceCode);
+        }
+        else if (calleeName === 'useContext') {
+            extractUseContext(path, componentInfo);
+        }
+
+        // Пользовательские хуки: const { items, add } = useCart()
+        else if (isCustomHookName(calleeName)) {
+            extractCustomHook(path, componentInfo, sourceCode, filePath, depth);
+        }
+    }
+}
+
+/**
+ * Проверяет, является ли имя именем пользовательского хука (useXxx)
+ */
+function isCustomHookName(name: string): boolean {
+    return /^use[A-Z0-9]/.test(name) && !BUILTIN_HOOKS.includes(name);
+}
+
+/**
+ * Проверяет, находится ли узел внутри тела пользовательского хука
+ */
+function isInsideCustomHook(path: babel.NodePath): boolean {
+    return !!path.findParent(p => {
+        if (babel.types.isFunctionDeclaration(p.node)) {
+            return !!p.node.id && isCustomHookName(p.node.id.name);
+        }
+        if ((babel.types.isArrowFunctionExpression(p.node) || babel.types.isFunctionExpression(p.node)) &&
+            p.parentPath && babel.types.isVariableDeclarator(p.parentPath.node) &&
+            babel.types.isIdentifier(p.parentPath.node.id)) {
+            return isCustomHookName(p.parentPath.node.id.name);
+        }
+        return false;
+    });
+}
+
+/**
+ * Встраивает пользовательский хук в модель компонента: состояния, эффекты и колбэки
+ * из тела хука добавляются в компонент, а имена из return хука заменяются именами,
+ * под которыми компонент получает их (const { items, add } = useCart()). Прочие имена,
+ * объявленные в хуке, получают суффикс вызова.
+ * Хуки ищутся в том же файле и в локальных модулях (import { useCart } from './cart')
+ */
+function extractCustomHook(
+    path: babel.NodePath<babel.types.CallExpression>,
+    componentInfo: ReactComponent,
+    sourceCode: string,
+    filePath: string | undefined,
+    depth: number
+) {
+    const hookName = (path.node.callee as babel.types.Identifier).name;
+    const program = getProgram(path);
+    const hook = program ? resolveCustomHook(program, hookName, sourceCode, filePath) : null;
+
+    if (!hook || depth >= MAX_HOOK_DEPTH) {
+        componentInfo.hooks.push({
+            name: hookName,
+            source: program ? findImportSource(program, hookName) : undefined,
+            resolved: false,
+        });
+        return;
+    }
+
+    // Собираем хуки из тела пользовательского хука
+    const hookInfo: ReactComponent = {
+        name: hookName,
+        props: [],
+        state: [],
+        effects: [],
+        callbacks: [],
+        refs: [],
+        reducers: [],
+        contexts: [],
+        hooks: [],
+        jsx: null,
+    };
+
+    hook.path.traverse({
+        CallExpression(callPath) {
+            // Вложенные функции внутри хука обрабатываются как часть хука
+            if (callPath.getFunctionParent()?.node !== hook.path.node) {
+                return;
+            }
+            extractHookCall(callPath, hookInfo, hook.sourceCode, hook.filePath, depth + 1);
+        },
+    });
+
+    // Обычные функции хука (const add = item => ...) становятся колбэками
+    const hookBody = hook.path.node.body;
+    if (babel.types.isBlockStatement(hookBody)) {
+        hookBody.body.forEach(statement => {
+            if (!babel.types.isVariableDeclaration(statement)) {
+                return;
+            }
+            statement.declarations.forEach(declarator => {
+                if (babel.types.isIdentifier(declarator.id) && declarator.init &&
+                    (babel.types.isArrowFunctionExpression(declarator.init) ||
+                        babel.types.isFunctionExpression(declarator.init))) {
+                    hookInfo.callbacks.push({
+                        name: declarator.id.name,
+                        body: hook.sourceCode.substring(declarator.init.start as number, declarator.init.end as number),
+                        dependencies: [],
+                    });
+                }
+            });
+        });
+    }
+
+    // Переименование: параметры хука -> аргументы вызова, результаты хука -> переменные компонента
+    const renames: Record<string, string> = {};
+    const argValues: Record<string, any> = {};
+
+    hook.path.node.params.forEach((param, index) => {
+        const paramNode = babel.types.isAssignmentPattern(param) ? param.left : param;
+        const arg = path.node.arguments[index];
+        if (!babel.types.isIdentifier(paramNode)) {
+            return;
+        }
+        if (arg) {
+            renames[paramNode.name] = sourceCode.substring(arg.start as number, arg.end as number);
+            argValues[paramNode.name] = getInitialStateValue(arg, sourceCode);
+        } else if (babel.types.isAssignmentPattern(param)) {
+            argValues[paramNode.name] = getInitialStateValue(param.right, hook.sourceCode);
+        }
+    });
+
+    const returned = getHookReturnNames(hook.path.node);
+    const declarator = path.parentPath && babel.types.isVariableDeclarator(path.parentPath.node)
+        ? path.parentPath.node
+        : null;
+
+    if (declarator && returned) {
+        if (babel.types.isObjectPattern(declarator.id) && !Array.isArray(returned)) {
+            declarator.id.properties.forEach(property => {
+                if (babel.types.isObjectProperty(property) &&
+                    babel.types.isIdentifier(property.key) &&
+                    babel.types.isIdentifier(property.value) &&
+                    returned[property.key.name]) {
+                    renames[returned[property.key.name]] = property.value.name;
+                }
+            });
+        } else if (babel.types.isArrayPattern(declarator.id) && Array.isArray(returned)) {
+            declarator.id.elements.forEach((element, index) => {
+                if (element && babel.types.isIdentifier(element) && returned[index]) {
+                    renames[returned[index]] = element.name;
+                }
+            });
+        } else if (babel.types.isIdentifier(declarator.id) && typeof returned === 'string') {
+            renames[returned] = declarator.id.name;
+        }
+    }
+
+    // Остальные имена хука получают суффикс вызова, чтобы два вызова одного хука (и локальные
+    // переменные компонента) не конфликтовали: open -> openToggle2 для второго useToggle()
+    const suffix = hookName.replace(/^use/, '') +
+        (componentInfo.hooks.filter(hook => hook.name === hookName && hook.resolved).length + 1);
+    const declared = [
+        ...Object.keys(hook.path.scope.bindings).filter(name => {
+            const kind = hook.path.scope.bindings[name].kind;
+            return kind !== 'param' && kind !== 'local';
+        }),
+        // Имена из вложенных хуков уже переименованы и не являются привязками этого хука
+        ...hookInfo.state.flatMap(state => [state.name, state.setter]),
+        ...hookInfo.callbacks.map(callback => callback.name),
+        ...hookInfo.refs.map(ref => ref.name),
+        ...hookInfo.reducers.flatMap(reducer => [reducer.name, reducer.dispatch]),
+        ...hookInfo.contexts.map(context => context.variable).filter((name): name is string => !!name),
+    ];
+    declared.forEach(name => {
+        if (name && !Object.prototype.hasOwnProperty.call(renames, name)) {
+            renames[name] = name + suffix;
+        }
+    });
+
+    const rename = (code: string) => renameIdentifiers(code, renames);
+
+    hookInfo.state.forEach(state => {
+        // Начальное значение из параметра хука: useCounter(10) -> useState(initial)
+        if (typeof state.initialValue === 'string' && state.initialValue in argValues) {
+            const value = argValues[state.initialValue];
+            if (state.type === getTypeFromValue(state.initialValue)) {
+                state.type = getTypeFromValue(value);
+            }
+            state.initialValue = value;
+        }
+        componentInfo.state.push({ ...state, name: rename(state.name), setter: rename(state.setter) });
+    });
+
+    hookInfo.effects.forEach(effect => {
+        componentInfo.effects.push({
+            ...effect,
+            body: rename(effect.body),
+            dependencies: effect.dependencies.map(rename),
+            callback: effect.callback && rename(effect.callback),
+            cleanup: effect.cleanup && rename(effect.cleanup),
+        });
+    });
+
+    hookInfo.callbacks.forEach(callback => {
+        componentInfo.callbacks.push({
+            name: rename(callback.name),
+            body: rename(callback.body),
+            dependencies: callback.dependencies.map(rename),
+        });
+    });
+
+    hookInfo.refs.forEach(ref => componentInfo.refs.push({ ...ref, name: rename(ref.name) }));
+    hookInfo.reducers.forEach(reducer => componentInfo.reducers.push({
+        ...reducer,
+        name: rename(reducer.name),
+        dispatch: rename(reducer.dispatch),
+    }));
+    hookInfo.contexts.forEach(context => componentInfo.contexts.push({
+        ...context,
+        variable: context.variable && rename(context.variable),
+    }));
+
+    componentInfo.hooks.push({ name: hookName, source: hook.source, resolved: true });
+    hookInfo.hooks.forEach(nested => componentInfo.hooks.push(nested));
+}
+
+/**
+ * Находит функцию пользовательского хука: в текущем файле или в импортированном локальном модуле
+ */
+function resolveCustomHook(
+    program: babel.NodePath<babel.types.Program>,
+    hookName: string,
+    sourceCode: string,
+    filePath: string | undefined
+): { path: babel.NodePath<babel.types.Function>; sourceCode: string; filePath?: string; source?: string } | null {
+    const local = findFunctionPath(program, hookName);
+    if (local) {
+        return { path: local, sourceCode, filePath };
+    }
+
+    // Импорт из локального модуля: import { useCart } from './hooks/useCart'
+    const source = findImportSource(program, hookName);
+    if (!source || !source.startsWith('.') || !filePath) {
+        return null;
+    }
+
+    const basePath = nodePath.resolve(nodePath.dirname(filePath), source);
+    const candidates = ['', '.ts', '.tsx', '.js', '.jsx', '/index.ts', '/index.tsx', '/index.js', '/index.jsx']
+        .map(ext => basePath + ext);
+    const modulePath = candidates.find(candidate => fs.existsSync(candidate) && fs.statSync(candidate).isFile());
+    if (!modulePath) {
+        return null;
+    }
+
+    const moduleCode = fs.readFileSync(modulePath, 'utf-8');
+    let moduleProgram: babel.NodePath<babel.types.Program> | null = null;
+    babel.traverse(parseToAST(moduleCode), {
+        Program(programPath) {
+            moduleProgram = programPath;
+            programPath.stop();
+        },
+    });
+
+    // Имя хука в модуле может отличаться: import { useCart as useShop }
+    const exportedName = findImportedName(program, hookName) || hookName;
+    const hookPath = moduleProgram ? findFunctionPath(moduleProgram, exportedName) : null;
+    if (!hookPath) {
+        return null;
+    }
+
+    return { path: hookPath, sourceCode: moduleCode, filePath: modulePath, source };
+}
+
+/**
+ * Ищет путь функции по имени на верхнем уровне файла (включая export и export default)
+ */
+function findFunctionPath(program: babel.NodePath<babel.types.Program>, name: string): babel.NodePath<babel.types.Function> | null {
+    const func = findFunctionByName(program, name) || findDefaultExportFunction(program, name);
+    if (!func) {
+        return null;
+    }
+
+    let found: babel.NodePath<babel.types.Function> | null = null;
+    program.traverse({
+        Function(funcPath) {
+            if (funcPath.node === func) {
+                found = funcPath;
+                funcPath.stop();
+            }
+        },
+    });
+
+    return found;
+}
+
+/**
+ * Ищет функцию, экспортированную по умолчанию: export default function useCart() {}
+ */
+function findDefaultExportFunction(program: babel.NodePath<babel.types.Program>, name: string): babel.types.Function | null {
+    for (const statement of program.node.body) {
+        if (babel.types.isExportDefaultDeclaration(statement) &&
+            babel.types.isFunctionDeclaration(statement.declaration) &&
+            (!statement.declaration.id || statement.declaration.id.name === name)) {
+            return statement.declaration;
+        }
+    }
+
+    return null;
+}
+
+/**
+ * Извлекает определение стилизованного компонента: styled.h1`...` или styled('h1')`...`,
+ * где styled импортирован из styled-components или @emotion/styled
+ */
+function extractStyledDefinition(
+    path: babel.NodePath<babel.types.VariableDeclarator>,
+    componentInfo: ReactComponent,
+    sourceCode: string
+) {
+    const init = path.node.init;
+    if (!babel.types.isTaggedTemplateExpression(init) || !babel.types.isIdentifier(path.node.id)) {
+        return;
+    }
+
+    let styledName: string | undefined;
+    let tag: string | undefined;
+    if (babel.types.isMemberExpression(init.tag) && !init.tag.computed &&
+        babel.types.isIdentifier(init.tag.object) && babel.types.isIdentifier(init.tag.property)) {
+        styledName = init.tag.object.name;
+        tag = init.tag.property.name;
+    } else if (babel.types.isCallExpression(init.tag) && babel.types.isIdentifier(init.tag.callee) &&
+        init.tag.arguments.length === 1 && babel.types.isStringLiteral(init.tag.arguments[0])) {
+        styledName = init.tag.callee.name;
+        tag = (init.tag.arguments[0] as babel.types.StringLiteral).value;
+    }
+
+    const program = getProgram(path);
+    const source = program && styledName ? findImportSource(program, styledName) : undefined;
+    if (!tag || !source || !STYLED_MODULES.includes(source)) {
+        return;
+    }
+
+    if (!componentInfo.styled) {
+        componentInfo.styled = [];
+    }
+    componentInfo.styled.push({
+        name: path.node.id.name,
+        tag,
+        quasis: init.quasi.quasis.map(quasi => quasi.value.cooked ?? quasi.value.raw),
+        expressions: init.quasi.expressions.map(expression =>
+            sourceCode.substring(expression.start as number, expression.end as number)),
+    });
+}
+
+/**
+ * Возвращает модуль, из которого импортировано имя
+ */
+function findImportSource(program: babel.NodePath<babel.types.Program>, localName: string): string | undefined {
+    for (const statement of program.node.body) {
+        if (babel.types.isImportDeclaration(statement) &&
+            statement.specifiers.some(specifier => specifier.local.name === localName)) {
+            return statement.source.value;
+        }
+    }
+
+    return undefined;
+}
+
+/**
+ * Возвращает имя, под которым импортированное значение экспортировано из модуля
+ */
+function findImportedName(program: babel.NodePath<babel.types.Program>, localName: string): string | undefined {
+    for (const statement of program.node.body) {
+        if (!babel.types.isImportDeclaration(statement)) {
+            continue;
+        }
+        for (const specifier of statement.specifiers) {
+            if (specifier.local.name !== localName) {
+                continue;
+            }
+            if (babel.types.isImportSpecifier(specifier)) {
+                return babel.types.isIdentifier(specifier.imported) ? specifier.imported.name : specifier.imported.value;
+            }
+            // Для импорта по умолчанию имя в модуле неизвестно
+            return undefined;
+        }
+    }
+
+    return undefined;
+}
+
+/**
+ * Возвращает имена, которые хук возвращает: { items, add } -> { items: 'items', add: 'add' },
+ * [value, toggle] -> ['value', 'toggle'], value -> 'value'
+ */
+function getHookReturnNames(func: babel.types.Function): Record<string, string> | string[] | string | null {
+    let argument: babel.types.Node | null | undefined = null;
+
+    if (babel.types.isBlockStatement(func.body)) {
+        const returnStatement = func.body.body.find(statement => babel.types.isReturnStatement(statement));
+        argument = returnStatement ? (returnStatement as babel.types.ReturnStatement).argument : null;
+    } else {
+        argument = func.body;
+    }
+
+    if (!argument) {
+        return null;
+    }
+
+    if (babel.types.isIdentifier(argument)) {
+        return argument.name;
+    }
+
+    if (babel.types.isArrayExpression(argument)) {
+        return argument.elements.map(element => babel.types.isIdentifier(element) ? element.name : '');
+    }
+
+    if (babel.types.isObjectExpression(argument)) {
+        const names: Record<string, string> = {};
+        argument.properties.forEach(property => {
+            if (babel.types.isObjectProperty(property) &&
+                babel.types.isIdentifier(property.key) &&
+                babel.types.isIdentifier(property.value)) {
+                names[property.key.name] = property.value.name;
+            }
+        });
+        return names;
+    }
+
+    return null;
+}
+
+/**
+ * Заменяет идентификаторы в коде (кроме обращений к свойствам: obj.name)
+ */
+function renameIdentifiers(code: string, renames: Record<string, string>): string {
+    const names = Object.keys(renames).filter(name => renames[name] !== name);
+    if (names.length === 0) {
+        return code;
+    }
+
+    const pattern = new RegExp(`(?<![\\w.$])(${names.join('|')})(?![\\w$])`, 'g');
+    return code.replace(pattern, name => renames[name]);
+}
+
+/**
+ * Определяет тип и начальное значение поля состояния редьюсера.
+ * Непустые массивы и объекты не переносятся как значения, только как тип
+ */
+function getReducerFieldValue(node: babel.types.Node, sourceCode: string): { type: string; initialValue?: any } {
+    if (babel.types.isArrayExpression(node)) {
+        return { type: 'array', initialValue: node.elements.length === 0 ? [] : undefined };
+    }
+    if (babel.types.isObjectExpression(node)) {
+        return { type: 'object', initialValue: node.properties.length === 0 ? {} : undefined };
+    }
+
+    const initialValue = getInitialStateValue(node, sourceCode);
+    return { type: getTypeFromValue(initialValue), initialValue };
+}
+
+/**
+ * Извлекает типы действий из switch (action.type) или цепочки if (action.type === '...')
+ */
+function extractReducerActions(reducerFunc: babel.types.Function, reducerInfo: ReducerDefinition, sourceCode: string) {
+    const isActionType = (node: babel.types.Node) =>
+        babel.types.isMemberExpression(node) &&
+        babel.types.isIdentifier(node.object, { name: reducerInfo.actionParam }) &&
+        babel.types.isIdentifier(node.property, { name: 'type' });
+
+    const getCode = (node: babel.types.Node) => sourceCode.substring(node.start as number, node.end as number);
+
+    babel.traverse(reducerFunc.body, {
+        SwitchStatement(switchPath) {
+            if (!isActionType(switchPath.node.discriminant)) return;
+
+            switchPath.node.cases.forEach(switchCase => {
+                if (!switchCase.test || !babel.types.isStringLiteral(switchCase.test)) return;
+
+                reducerInfo.actions.push({
+                    type: switchCase.test.value,
+                    body: switchCase.consequent.map(getCode).join('\n'),
+                });
+            });
+        },
+
+        IfStatement(ifPath) {
+            const test = ifPath.node.test;
+            if (!babel.types.isBinaryExpression(test) ||
+                (test.operator !== '===' && test.operator !== '==') ||
+                !isActionType(test.left) ||
+                !babel.types.isStringLiteral(test.right)) {
+                return;
+            }
+
+            const consequent = ifPath.node.consequent;
+            reducerInfo.actions.push({
+                type: test.right.value,
+                body: babel.types.isBlockStatement(consequent)
+                    ? consequent.body.map(getCode).join('\n')
+                    : getCode(consequent),
+            });
+        }
+    }, { scopeToSkip: null } as any);
+}
+
+/**
+ * Ищет функцию по имени на верхнем уровне файла (function f() {} или const f = () => {})
+ */
+function findFunctionByName(program: babel.NodePath<babel.types.Program>, name: string): babel.types.Function | null {
+    for (const statement of program.node.body) {
+        const declaration = babel.types.isExportNamedDeclaration(statement) && statement.declaration
+            ? statement.declaration
+            : statement;
+
+        if (babel.types.isFunctionDeclaration(declaration) && declaration.id && declaration.id.name === name) {
+            return declaration;
+        }
+
+        const init = babel.types.isVariableDeclaration(declaration) ? findVariableInitIn(declaration, name) : null;
+        if (init && (babel.types.isArrowFunctionExpression(init) || babel.types.isFunctionExpression(init))) {
+            return init;
+        }
+    }
+
+    return null;
+}
+
+/**
+ * Ищет инициализатор переменной верхнего уровня по имени
+ */
+function findVariableInit(program: babel.NodePath<babel.types.Program>, name: string): babel.types.Expression | null {
+    for (const statement of program.node.body) {
+        const declaration = babel.types.isExportNamedDeclaration(statement) && statement.declaration
+            ? statement.declaration
+            : statement;
+
+        if (babel.types.isVariableDeclaration(declaration)) {
+            const init = findVariableInitIn(declaration, name);
+            if (init) {
+                return init;
+            }
+        }
+    }
+
+    return null;
+}
+
+/**
+ * Ищет инициализатор переменной в объявлении const/let/var
+ */
+function findVariableInitIn(declaration: babel.types.VariableDeclaration, name: string): babel.types.Expression | null {
+    for (const declarator of declaration.declarations) {
+        if (babel.types.isIdentifier(declarator.id) && declarator.id.name === name && declarator.init) {
+            return declarator.init;
+        }
+    }
+
+    return null;
+}
+
 /**
  * Получает родительский узел Program
  */
@@ -724,11 +1781,41 @@ function getTypeFromTSAnnotation(typeAnnotation: babel.types.TSType): string {
     } else if (babel.types.isTSObjectKeyword(typeAnnotation)) {
         return 'object';
     } else if (babel.types.isTSFunctionType(typeAnnotation)) {
-        return 'function';
+        // Сигнатура сохраняется для render props: (item: string) => ReactNode
+        const params = ((typeAnnotation as any).parameters || (typeAnnotation as any).params || []).map((param: any) => {
+            const paramType = param.typeAnnotation && babel.types.isTSTypeAnnotation(param.typeAnnotation)
+                ? getTypeFromTSAnnotation(param.typeAnnotation.typeAnnotation)
+                : 'any';
+            return `${param.name}: ${paramType}`;
+        });
+        const returnType = typeAnnotation.typeAnnotation
+            ? getTypeFromTSAnnotation(typeAnnotation.typeAnnotation.typeAnnotation)
+            : 'any';
+        return `(${params.join(', ')}) => ${returnType}`;
+    } else if (babel.types.isTSTypeLiteral(typeAnnotation)) {
+        // Встроенный объектный тип сохраняется с полями: { x: number; y: string }
+        const members = typeAnnotation.members
+            .filter(member => babel.types.isTSPropertySignature(member) && babel.types.isIdentifier(member.key))
+            .map(member => {
+                const signature = member as babel.types.TSPropertySignature;
+                const memberType = signature.typeAnnotation
+                    ? getTypeFromTSAnnotation(signature.typeAnnotation.typeAnnotation)
+                    : 'any';
+                return `${(signature.key as babel.types.Identifier).name}: ${memberType}`;
+            });
+        return `{ ${members.join('; ')} }`;
     } else if (babel.types.isTSUnionType(typeAnnotation)) {
         return 'union';
+    } else if (babel.types.isTSTypeReference(typeAnnotation) && babel.types.isIdentifier(typeAnnotation.typeName) &&
+        typeAnnotation.typeName.name === 'Array' && typeAnnotation.typeParameters?.params.length === 1) {
+        // Array<string>
+        return `Array<${getTypeFromTSAnnotation(typeAnnotation.typeParameters.params[0])}>`;
     } else if (babel.types.isTSTypeReference(typeAnnotation) && babel.types.isIdentifier(typeAnnotation.typeName)) {
         return typeAnnotation.typeName.name;
+    } else if (babel.types.isTSTypeReference(typeAnnotation) && babel.types.isTSQualifiedName(typeAnnotation.typeName) &&
+        babel.types.isIdentifier(typeAnnotation.typeName.left)) {
+        // React.ReactNode, JSX.Element
+        return `${typeAnnotation.typeName.left.name}.${typeAnnotation.typeName.right.name}`;
     }
 
     return 'any';
@@ -770,6 +1857,92 @@ function getInitialStateValue(node: babel.types.Node, sourceCode: string): any {
         : undefined;
 }
 
+/**
+ * Возвращает значение литерала (строки, числа, булевы значения, массивы и объекты из
+ * литералов) или undefined, если выражение вычисляется только при рендеринге
+ */
+function getLiteralValue(node: babel.types.Node): any {
+    if (babel.types.isStringLiteral(node) || babel.types.isNumericLiteral(node) ||
+        babel.types.isBooleanLiteral(node)) {
+        return node.value;
+    }
+    if (babel.types.isUnaryExpression(node, { operator: '-' }) && babel.types.isNumericLiteral(node.argument)) {
+        return -node.argument.value;
+    }
+    if (babel.types.isTemplateLiteral(node) && node.expressions.length === 0) {
+        return node.quasis.map(quasi => quasi.value.cooked ?? quasi.value.raw).join('');
+    }
+
+    if (babel.types.isArrayExpression(node)) {
+        const items: any[] = [];
+        for (const element of node.elements) {
+            const value = element ? getLiteralValue(element) : undefined;
+            if (value === undefined) {
+                return undefined;
+            }
+            items.push(value);
+        }
+        return items;
+    }
+
+    if (babel.types.isObjectExpression(node)) {
+        const object: { [key: string]: any } = {};
+        for (const property of node.properties) {
+            if (!babel.types.isObjectProperty(property) || property.computed) {
+                return undefined;
+            }
+            let key: string;
+            if (babel.types.isIdentifier(property.key)) {
+                key = property.key.name;
+            } else if (babel.types.isStringLiteral(property.key)) {
+                key = property.key.value;
+            } else {
+                return undefined;
+            }
+            const value = getLiteralValue(property.value);
+            if (value === undefined) {
+                return undefined;
+            }
+            object[key] = value;
+        }
+        return object;
+    }
+
+    return undefined;
+}
+
+/**
+ * Применяет Component.defaultProps к пропсам компонента: пропсы со значением по умолчанию
+ * необязательны. Пропсы, которых нет в параметрах функции (props без деструктуризации),
+ * добавляются с типом по значению
+ */
+function applyDefaultProps(componentInfo: ReactComponent, defaults?: babel.types.ObjectExpression) {
+    if (!defaults) {
+        return;
+    }
+
+    for (const property of defaults.properties) {
+        if (!babel.types.isObjectProperty(property) || !babel.types.isIdentifier(property.key)) {
+            continue;
+        }
+        const name = property.key.name;
+        const defaultValue = getLiteralValue(property.value);
+
+        let definition = componentInfo.props.find(prop => prop.name === name);
+        if (!definition) {
+            definition = { name, type: 'any', required: false };
+            componentInfo.props.push(definition);
+        }
+        definition.required = false;
+        if (defaultValue !== undefined) {
+            definition.defaultValue = defaultValue;
+            if (definition.type === 'any') {
+                definition.type = getTypeFromValue(defaultValue);
+            }
+        }
+    }
+}
+
 /**
  * Определяет тип на основе значения
  */
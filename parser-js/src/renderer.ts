@@ -0,0 +1,189 @@
+import * as babel from '@babel/core';
+import * as fs from 'fs';
+import * as nodePath from 'path';
+import * as vm from 'vm';
+import * as babelPresetReact from '@babel/preset-react';
+import * as babelPresetTypeScript from '@babel/preset-typescript';
+import * as babelTransformModules from '@babel/plugin-transform-modules-commonjs';
+import { parseToAST } from './parser';
+import { rewriteClassComponents } from './class-converter';
+
+// Фикстура сверки рендеринга: пропсы и значения состояний по именам
+export interface RenderFixture {
+    props?: Record<string, any>;
+    state?: Record<string, any>;
+}
+
+// Имя параметра модуля, через который подставляются значения состояний фикстуры
+const FIXTURE_STATE = '__fixtureState';
+
+// Расширения файлов стилей: импорт CSS модуля возвращает имена классов как есть
+const STYLE_EXTENSIONS = ['.css', '.scss', '.sass', '.less'];
+
+// Пакеты, которые компонент может импортировать при сверке
+const ALLOWED_PACKAGES = ['react', 'react/jsx-runtime', 'react/jsx-dev-runtime', 'react-dom', 'react-dom/server'];
+
+// Имя переменной контекста, через которую модулю передаются exports, require и фикстура
+const MODULE_ARGS = '__moduleArgs';
+
+// Ограничение времени выполнения модуля, мс
+const EVALUATE_TIMEOUT = 1000;
+
+// Песочница сверки: контекст vm без глобальных объектов Node.js и каталог компонента,
+// за пределы которого не выходят локальные импорты. vm не является границей
+// безопасности, поэтому сверка включается только явно (RENDER_CHECK)
+interface Sandbox {
+    context: vm.Context;
+    root: string;
+}
+
+/**
+ * Рендерит компонент через react-dom/server (renderToStaticMarkup). Значения состояний
+ * фикстуры подставляются в useState по имени переменной: const [count, setCount] = useState(0)
+ * получает fixture.state.count. Классовые компоненты предварительно переписываются в функции
+ */
+export function renderReactComponent(code: string, componentName: string, fixture: RenderFixture = {}, filePath?: string): string {
+    const React = require('react');
+    const ReactDOMServer = require('react-dom/server');
+
+    // Классовый компонент рендерится так же, как его видит конвертер: состояние в useState
+    const rewritten = rewriteClassComponents(parseToAST(code), code);
+
+    const fileName = filePath || nodePath.join(process.cwd(), `${componentName || 'Component'}.tsx`);
+    const sandbox: Sandbox = {
+        context: vm.createContext({}, { codeGeneration: { strings: false, wasm: false } }),
+        root: nodePath.dirname(fileName),
+    };
+    const moduleExports = evaluateModule(rewritten, fileName, fixture.state || {}, sandbox, componentName);
+
+    const component = moduleExports.__renderCheckComponent || moduleExports[componentName] || moduleExports.default;
+    if (typeof component !== 'function' && !(component && typeof component === 'object' && component.$$typeof)) {
+        throw new Error(`Компонент ${componentName} не найден в модуле`);
+    }
+
+    return ReactDOMServer.renderToStaticMarkup(React.createElement(component, fixture.props || {}));
+}
+
+/**
+ * Компилирует модуль в CommonJS и выполняет его в контексте песочницы. Локальные модули
+ * загружаются так же (без подстановки состояний), из пакетов доступны только react и react-dom
+ */
+function evaluateModule(code: string, fileName: string, state: Record<string, any>, sandbox: Sandbox, componentName?: string): any {
+    const plugins: any[] = [babelTransformModules];
+    if (componentName) {
+        plugins.unshift(fixtureStatePlugin);
+    }
+
+    const result = babel.transformSync(code, {
+        filename: fileName,
+        babelrc: false,
+        configFile: false,
+        presets: [
+            [babelPresetReact, { runtime: 'automatic' }],
+            [babelPresetTypeScript, { isTSX: true, allExtensions: true }]
+        ],
+        plugins,
+    });
+    if (!result || result.code == null) {
+        throw new Error(`Не удалось скомпилировать модуль ${fileName}`);
+    }
+
+    let source = result.code;
+    if (componentName && /^[A-Za-z_$][\w$]*$/.test(componentName)) {
+        // Компонент может быть не экспортирован: забираем его по локальному имени
+        source += `\nexports.__renderCheckComponent = typeof ${componentName} !== 'undefined' ? ${componentName} : undefined;`;
+    }
+
+    const loaded = { exports: {} as any };
+    const dirName = nodePath.dirname(fileName);
+    const moduleRequire = (request: string) => requireModule(request, dirName, sandbox);
+
+    sandbox.context[MODULE_ARGS] = [loaded.exports, moduleRequire, loaded, fileName, dirName, state];
+    try {
+        vm.runInContext(
+            `(function (exports, require, module, __filename, __dirname, ${FIXTURE_STATE}) {\n${source}\n}).apply(undefined, ${MODULE_ARGS});`,
+            sandbox.context,
+            { filename: fileName, timeout: EVALUATE_TIMEOUT }
+        );
+    } finally {
+        delete sandbox.context[MODULE_ARGS];
+    }
+
+    return loaded.exports;
+}
+
+/**
+ * Загружает модуль, импортированный компонентом: разрешенный пакет или локальный
+ * модуль из каталога компонента
+ */
+function requireModule(request: string, dirName: string, sandbox: Sandbox): any {
+    if (!request.startsWith('.') && !request.startsWith('/')) {
+        if (!ALLOWED_PACKAGES.includes(request)) {
+            throw new Error(`Модуль ${request} недоступен при сверке: разрешены только ${ALLOWED_PACKAGES.join(', ')}`);
+        }
+        return require(request);
+    }
+
+    const resolved = nodePath.resolve(dirName, request);
+    const relative = nodePath.relative(sandbox.root, resolved);
+    if (relative.startsWith('..') || nodePath.isAbsolute(relative)) {
+        throw new Error(`Модуль ${request} находится вне каталога компонента`);
+    }
+    if (STYLE_EXTENSIONS.some(ext => resolved.endsWith(ext))) {
+        // styles.title -> "title": имена классов CSS модулей сверяются без переименования
+        return new Proxy({}, {
+            get: (_target, key) => key === '__esModule' ? false : typeof key === 'string' ? key : undefined,
+        });
+    }
+
+    for (const candidate of [resolved, ...['.tsx', '.ts', '.jsx', '.js'].map(ext => resolved + ext),
+        ...['index.tsx', 'index.ts', 'index.jsx', 'index.js'].map(name => nodePath.join(resolved, name))]) {
+        if (fs.existsSync(candidate) && fs.statSync(candidate).isFile()) {
+            if (candidate.endsWith('.json')) {
+                return JSON.parse(fs.readFileSync(candidate, 'utf-8'));
+            }
+            return evaluateModule(fs.readFileSync(candidate, 'utf-8'), candidate, {}, sandbox);
+        }
+    }
+
+    throw new Error(`Модуль ${request} не найден`);
+}
+
+/**
+ * Плагин Babel: useState(initial) в объявлении const [name, setName] = useState(initial)
+ * получает значение фикстуры, если оно задано для name
+ */
+function fixtureStatePlugin({ types: t }: typeof babel): babel.PluginObj {
+    return {
+        visitor: {
+            CallExpression(path) {
+                const callee = path.node.callee;
+                const isUseState = t.isIdentifier(callee, { name: 'useState' }) ||
+                    (t.isMemberExpression(callee) && t.isIdentifier(callee.property, { name: 'useState' }));
+                if (!isUseState) {
+                    return;
+                }
+
+                const declarator = path.parent;
+                if (!t.isVariableDeclarator(declarator) || !t.isArrayPattern(declarator.id)) {
+                    return;
+                }
+                const variable = declarator.id.elements[0];
+                if (!t.isIdentifier(variable)) {
+                    return;
+                }
+
+                const initial = (path.node.arguments[0] as babel.types.Expression) || t.identifier('undefined');
+                const name = t.stringLiteral(variable.name);
+                path.node.arguments = [t.conditionalExpression(
+                    t.callExpression(
+                        t.memberExpression(t.memberExpression(t.memberExpression(t.identifier('Object'), t.identifier('prototype')), t.identifier('hasOwnProperty')), t.identifier('call')),
+                        [t.identifier(FIXTURE_STATE), name]
+                    ),
+                    t.memberExpression(t.identifier(FIXTURE_STATE), name, true),
+                    initial
+                )];
+            },
+        },
+    };
+}
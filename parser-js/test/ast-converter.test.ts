@@ -0,0 +1,61 @@
+import { processArrayMapping, transformJSX } from '../src/ast-converter';
+import { parseToAST } from '../src/parser';
+
+// Возвращает выражение первой инструкции кода: <div /> или items.map(...)
+function expression(code: string): any {
+    return (parseToAST(code).program.body[0] as any).expression;
+}
+
+function transform(code: string) {
+    return transformJSX(expression(code), code);
+}
+
+describe('фрагменты', () => {
+    it('выводит <>...</> фрагментом без атрибутов', () => {
+        expect(transform('<><b>a</b></>;')).toEqual({
+            type: 'Fragment',
+            props: {},
+            children: [{ type: 'b', props: {}, propOrder: [], children: [{ type: 'text', props: { content: 'a' }, children: [] }] }],
+        });
+    });
+
+    it('сохраняет key у React.Fragment', () => {
+        const fragment = transform('<React.Fragment key={item.id}><dt>{item.term}</dt></React.Fragment>;');
+
+        expect(fragment.type).toBe('Fragment');
+        expect(fragment.props).toEqual({ key: { type: 'expression', code: 'item.id' } });
+        expect(fragment.children.map(child => child.type)).toEqual(['dt']);
+    });
+
+    it('сохраняет только key из атрибутов Fragment', () => {
+        expect(transform('<Fragment key="a" data-x="1"><i /></Fragment>;').props).toEqual({ key: 'a' });
+        expect(transform('<Fragment><i /></Fragment>;').props).toEqual({});
+    });
+
+    it('сохраняет key фрагмента в шаблоне списка', () => {
+        const code = 'items.map(item => <Fragment key={item.id}><dt>{item.term}</dt><dd>{item.text}</dd></Fragment>);';
+        const mapping = processArrayMapping(expression(code), code);
+
+        expect(mapping.props.template.type).toBe('Fragment');
+        expect(mapping.props.template.props).toEqual({ key: { type: 'expression', code: 'item.id' } });
+        expect(mapping.props.template.children.map(child => child.type)).toEqual(['dt', 'dd']);
+    });
+});
+
+describe('порядок атрибутов', () => {
+    it('возвращает атрибуты и spread в порядке исходного кода', () => {
+        const code = '<input type="text" {...rest} id="name" autoComplete="off" disabled />;';
+        const element = transform(code);
+        const spread = `__spread__${code.indexOf('rest')}`;
+
+        expect(element.propOrder).toEqual(['type', spread, 'id', 'autoComplete', 'disabled']);
+        expect(element.props[spread]).toEqual({ type: 'spread', code: 'rest' });
+        expect(Object.keys(element.props).sort()).toEqual([...element.propOrder].sort());
+    });
+
+    it('дает одинаковый результат для одного и того же кода', () => {
+        const code = '<a href={url} className="link" onClick={() => open(url)}>Open</a>;';
+
+        expect(JSON.stringify(transform(code))).toBe(JSON.stringify(transform(code)));
+    });
+});
@@ -0,0 +1,110 @@
+import { rewriteClassComponents } from '../src/class-converter';
+import { parseReactComponent, parseToAST } from '../src/parser';
+
+beforeAll(() => {
+    jest.spyOn(console, 'log').mockImplementation(() => undefined);
+});
+
+afterAll(() => {
+    jest.restoreAllMocks();
+});
+
+const CLOCK = `
+import React from 'react';
+
+class Clock extends React.Component<{ label: string }, { ticks: number }> {
+    static defaultProps = { label: 'Time' };
+
+    state = { ticks: 0 };
+
+    componentDidMount() {
+        this.timer = setInterval(() => this.tick(), 1000);
+    }
+
+    componentWillUnmount() {
+        clearInterval(this.timer);
+    }
+
+    tick() {
+        this.setState(prev => ({ ticks: prev.ticks + 1 }));
+    }
+
+    reset = () => this.setState({ ticks: 0 }, () => console.log('reset'));
+
+    render() {
+        return <span onClick={this.reset}>{this.props.label}: {this.state.ticks}</span>;
+    }
+}
+`;
+
+function rewrite(code: string): string {
+    return rewriteClassComponents(parseToAST(code), code);
+}
+
+describe('переписывание классовых компонентов', () => {
+    it('переводит состояние, методы и поля экземпляра в хуки', () => {
+        const code = rewrite(CLOCK);
+
+        expect(code).toContain('function Clock(props: { label: string }) {');
+        expect(code).toContain('const [ticks, setTicks] = useState<number>(0);');
+        expect(code).toContain('const timer = useRef(null);');
+        expect(code).toContain('const tick = useCallback(() => {');
+        expect(code).toContain('setTicks(ticks + 1);');
+        expect(code).toContain(
+            "const reset = useCallback(() => (setTicks(0), (() => console.log('reset'))()), []);");
+        expect(code).toContain('return <span onClick={reset}>{props.label}: {ticks}</span>;');
+        expect(code).not.toContain('this.');
+    });
+
+    it('переводит componentDidMount и componentWillUnmount в эффект с очисткой', () => {
+        const code = rewrite(CLOCK);
+
+        expect(code).toContain('useEffect(() => {');
+        expect(code).toContain('timer.current = setInterval(() => tick(), 1000);');
+        expect(code).toContain('return () => {');
+        expect(code).toContain('clearInterval(timer.current);');
+        expect(code).toContain('}, []);');
+    });
+
+    it('выносит static defaultProps за функцию', () => {
+        expect(rewrite(CLOCK)).toContain("Clock.defaultProps = { label: 'Time' };");
+    });
+
+    it('деструктурирует пропсы без типа в параметре функции', () => {
+        const code = rewrite(`
+class Greeting extends Component {
+    render() {
+        const { name } = this.props;
+        return <p>{name}</p>;
+    }
+}
+`);
+
+        expect(code).toContain('function Greeting({ name }) {');
+        expect(code).not.toContain('this.props');
+    });
+
+    it('возвращает код без классовых компонентов без изменений', () => {
+        const code = 'export function Title() { return <h1>Title</h1>; }';
+        expect(rewrite(code)).toBe(code);
+    });
+
+    it('разбирает классовый компонент как функциональный', () => {
+        const component = parseReactComponent(CLOCK);
+
+        expect(component.name).toBe('Clock');
+        expect(component.state).toEqual([
+            expect.objectContaining({ name: 'ticks', setter: 'setTicks', initialValue: 0 }),
+        ]);
+        expect(component.refs.map(ref => ref.name)).toEqual(['timer']);
+        expect(component.effects).toEqual([
+            expect.objectContaining({
+                kind: 'interval',
+                delay: 1000,
+                callback: '() => tick()',
+                dependencies: [],
+            }),
+        ]);
+        expect(component.effects[0].cleanup).toContain('clearInterval(timer.current);');
+    });
+});
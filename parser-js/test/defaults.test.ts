@@ -0,0 +1,75 @@
+import { parseReactComponent } from '../src/parser';
+
+beforeAll(() => {
+    jest.spyOn(console, 'log').mockImplementation(() => undefined);
+});
+
+afterAll(() => {
+    jest.restoreAllMocks();
+});
+
+describe('значения пропсов по умолчанию', () => {
+    it('берет значения из деструктуризации параметра', () => {
+        const component = parseReactComponent(`
+export function Badge({ label, size = 'md', count = -1, tags = ['new'], onClick = () => {} }) {
+    return <span className={size} onClick={onClick}>{label} {count} {tags.length}</span>;
+}
+`);
+
+        expect(component.props).toEqual([
+            { name: 'label', required: true, type: 'any' },
+            { name: 'size', required: false, type: 'string', defaultValue: 'md' },
+            { name: 'count', required: false, type: 'number', defaultValue: -1 },
+            { name: 'tags', required: false, type: 'array', defaultValue: ['new'] },
+            { name: 'onClick', required: false, type: 'any' },
+        ]);
+    });
+
+    it('применяет Card.defaultProps к деструктурированным пропсам', () => {
+        const component = parseReactComponent(`
+export function Card({ title, variant }) {
+    return <div className={variant}>{title}</div>;
+}
+
+Card.defaultProps = { variant: 'plain', bordered: true };
+`);
+
+        expect(component.props).toEqual([
+            { name: 'title', required: true, type: 'any' },
+            { name: 'variant', required: false, type: 'string', defaultValue: 'plain' },
+            { name: 'bordered', required: false, type: 'boolean', defaultValue: true },
+        ]);
+    });
+
+    it('делает пропс из интерфейса необязательным', () => {
+        const component = parseReactComponent(`
+interface CardProps {
+    title: string;
+    variant: string;
+}
+
+export function Card(props: CardProps) {
+    return <div className={props.variant}>{props.title}</div>;
+}
+
+Card.defaultProps = { variant: 'plain' };
+`);
+
+        expect(component.props).toEqual([
+            { name: 'title', required: true, type: 'string' },
+            { name: 'variant', required: false, type: 'string', defaultValue: 'plain' },
+        ]);
+    });
+
+    it('игнорирует defaultProps других компонентов', () => {
+        const component = parseReactComponent(`
+export function Card({ title }) {
+    return <div>{title}</div>;
+}
+
+Other.defaultProps = { title: 'Untitled' };
+`);
+
+        expect(component.props).toEqual([{ name: 'title', required: true, type: 'any' }]);
+    });
+});
@@ -0,0 +1,110 @@
+import * as fs from 'fs';
+import * as os from 'os';
+import * as nodePath from 'path';
+import { parseReactComponent } from '../src/parser';
+
+beforeAll(() => {
+    jest.spyOn(console, 'log').mockImplementation(() => undefined);
+});
+
+afterAll(() => {
+    jest.restoreAllMocks();
+});
+
+const TOGGLE_HOOK = `
+import { useState } from 'react';
+
+function useToggle(initial = false) {
+    const [on, setOn] = useState(initial);
+    const toggle = () => setOn(!on);
+    return [on, toggle];
+}
+`;
+
+describe('встраивание пользовательских хуков', () => {
+    it('встраивает хук из того же файла под именами компонента', () => {
+        const component = parseReactComponent(TOGGLE_HOOK + `
+export function Panel() {
+    const [open, flip] = useToggle(true);
+    return <button onClick={flip}>{open ? 'yes' : 'no'}</button>;
+}
+`);
+
+        expect(component.name).toBe('Panel');
+        expect(component.state).toEqual([
+            expect.objectContaining({ name: 'open', setter: 'setOnToggle1', initialValue: true, type: 'boolean' }),
+        ]);
+        expect(component.callbacks).toEqual([
+            expect.objectContaining({ name: 'flip', body: '() => setOnToggle1(!open)' }),
+        ]);
+        expect(component.hooks).toEqual([{ name: 'useToggle', source: undefined, resolved: true }]);
+    });
+
+    it('добавляет суффикс вызова к внутренним именам второго вызова', () => {
+        const component = parseReactComponent(TOGGLE_HOOK + `
+export function Panel() {
+    const [open, flip] = useToggle(true);
+    const [dark, flipDark] = useToggle();
+    return <div className={dark ? 'dark' : ''} onClick={flipDark}>{open && 'open'}</div>;
+}
+`);
+
+        expect(component.state.map(state => [state.name, state.setter, state.initialValue])).toEqual([
+            ['open', 'setOnToggle1', true],
+            ['dark', 'setOnToggle2', false],
+        ]);
+        expect(component.callbacks.map(callback => callback.body)).toEqual([
+            '() => setOnToggle1(!open)',
+            '() => setOnToggle2(!dark)',
+        ]);
+    });
+
+    it('встраивает хук из локального модуля, импортированный под другим именем', () => {
+        const dir = fs.mkdtempSync(nodePath.join(os.tmpdir(), 'parser-hooks-'));
+        try {
+            fs.mkdirSync(nodePath.join(dir, 'hooks'));
+            fs.writeFileSync(nodePath.join(dir, 'hooks', 'useCart.ts'), `
+import { useState } from 'react';
+
+export function useCart() {
+    const [items, setItems] = useState([]);
+    const add = item => setItems([...items, item]);
+    return { items, add };
+}
+`);
+            const filePath = nodePath.join(dir, 'Shop.tsx');
+            const component = parseReactComponent(`
+import { useCart as useShop } from './hooks/useCart';
+
+export function Shop() {
+    const { items, add: addItem } = useShop();
+    return <button onClick={() => addItem(1)}>{items.length}</button>;
+}
+`, filePath);
+
+            expect(component.state).toEqual([
+                expect.objectContaining({ name: 'items', setter: 'setItemsShop1' }),
+            ]);
+            expect(component.callbacks).toEqual([
+                expect.objectContaining({ name: 'addItem', body: 'item => setItemsShop1([...items, item])' }),
+            ]);
+            expect(component.hooks).toEqual([{ name: 'useShop', source: './hooks/useCart', resolved: true }]);
+        } finally {
+            fs.rmSync(dir, { recursive: true, force: true });
+        }
+    });
+
+    it('отмечает хуки из пакетов как неразрешенные', () => {
+        const component = parseReactComponent(`
+import { useQuery } from 'react-query';
+
+export function List() {
+    const data = useQuery('items');
+    return <ul>{data.length}</ul>;
+}
+`);
+
+        expect(component.state).toEqual([]);
+        expect(component.hooks).toEqual([{ name: 'useQuery', source: 'react-query', resolved: false }]);
+    });
+});
@@ -0,0 +1,102 @@
+import { parseReactComponent } from '../src/parser';
+
+beforeAll(() => {
+    jest.spyOn(console, 'log').mockImplementation(() => undefined);
+});
+
+afterAll(() => {
+    jest.restoreAllMocks();
+});
+
+describe('снятие оберток компонента', () => {
+    it('снимает memo и forwardRef с объявления компонента', () => {
+        const component = parseReactComponent(`
+import { memo, forwardRef } from 'react';
+
+export const Button = memo(forwardRef(({ label }, ref) => <button ref={ref}>{label}</button>));
+`);
+
+        expect(component.name).toBe('Button');
+        expect(component.wrappers).toEqual(['memo', 'forwardRef']);
+        expect(component.props.map(prop => prop.name)).toEqual(['label']);
+        expect(component.jsx).toEqual(expect.objectContaining({ type: 'button' }));
+    });
+
+    it('находит компонент, обернутый в экспорте по умолчанию', () => {
+        const component = parseReactComponent(`
+import { memo } from 'react';
+
+function Button({ label }) {
+    return <button>{label}</button>;
+}
+
+export default memo(Button);
+`);
+
+        expect(component.name).toBe('Button');
+        expect(component.wrappers).toEqual(['memo']);
+        expect(component.exports).toEqual(expect.objectContaining({ default: 'Button' }));
+    });
+
+    it('берет имя функции, переданной в обертку', () => {
+        const component = parseReactComponent(`
+import React from 'react';
+
+export default React.memo(function Page() {
+    return <main>Page</main>;
+});
+`);
+
+        expect(component.name).toBe('Page');
+        expect(component.wrappers).toEqual(['memo']);
+        expect(component.exports).toEqual(expect.objectContaining({ default: 'Page' }));
+    });
+
+    it('ставит обертки, примененные отдельно, снаружи собственных', () => {
+        const component = parseReactComponent(`
+import { memo } from 'react';
+import { withRouter } from 'react-router';
+
+const Page = memo(() => <main>Page</main>);
+
+export default withRouter(Page);
+`);
+
+        expect(component.name).toBe('Page');
+        expect(component.wrappers).toEqual(['withRouter', 'memo']);
+    });
+
+    const HOC_PAGE = `
+import { connect } from 'react-redux';
+import { withAuth } from './auth';
+
+function Page() {
+    return <main>Page</main>;
+}
+
+export default withAuth(connect(mapState)(Page));
+`;
+
+    it('снимает HOC из параметра hocs и HOC с параметрами', () => {
+        const component = parseReactComponent(HOC_PAGE, undefined, ['withAuth']);
+
+        expect(component.name).toBe('Page');
+        expect(component.wrappers).toEqual(['withAuth', 'connect']);
+    });
+
+    it('снимает HOC из переменной окружения PARSER_HOCS', () => {
+        process.env.PARSER_HOCS = 'withAuth, withTheme';
+        try {
+            expect(parseReactComponent(HOC_PAGE).wrappers).toEqual(['withAuth', 'connect']);
+        } finally {
+            delete process.env.PARSER_HOCS;
+        }
+    });
+
+    it('не снимает неизвестные HOC', () => {
+        const component = parseReactComponent(HOC_PAGE);
+
+        expect(component.name).toBe('Page');
+        expect(component.wrappers).toBeUndefined();
+    });
+});